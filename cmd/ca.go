@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/charles-d-burton/tailsys/connections"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+type caFlags struct {
+	Role    string
+	TTL     time.Duration
+	OutFile string
+	Serial  string
+}
+
+var caf = caFlags{}
+
+func caCommand() *cobra.Command {
+	ccmd := &cobra.Command{
+		Use:   "ca",
+		Short: "Manage the certificate authority of the coordination server, run on the coordination server host",
+	}
+	ccmd.AddCommand(issueCertificate())
+	ccmd.AddCommand(caBundle())
+	ccmd.AddCommand(revokeCertificate())
+	return ccmd
+}
+
+func loadCA() (*connections.CA, error) {
+	return connections.LoadCA(gf.ConfigDirectory + "/ca")
+}
+
+func issueCertificate() *cobra.Command {
+	ccmd := &cobra.Command{
+		Use:   "issue <name>",
+		Short: "Issue a key and certificate, copy the output to certs/server-config.yaml of the cli",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(ccmd *cobra.Command, args []string) error {
+			switch caf.Role {
+			case connections.RoleOperator, connections.RoleNode, connections.RoleCoordinator:
+			default:
+				return fmt.Errorf("unknown role %s, must be operator, node or coordinator", caf.Role)
+			}
+			ca, err := loadCA()
+			if err != nil {
+				return err
+			}
+			tc, err := ca.Issue(args[0], caf.Role, caf.TTL)
+			if err != nil {
+				return err
+			}
+			return writeTLSConfig(tc, caf.OutFile)
+		},
+	}
+	ccmd.Flags().StringVar(&caf.Role, "role", connections.RoleOperator, "role of the certificate: operator, node or coordinator")
+	ccmd.Flags().DurationVar(&caf.TTL, "ttl", connections.OperatorCertLifetime, "how long the certificate is valid for")
+	ccmd.Flags().StringVarP(&caf.OutFile, "out-file", "f", "", "file to write the certificate to, stdout when not set")
+	return ccmd
+}
+
+func caBundle() *cobra.Command {
+	ccmd := &cobra.Command{
+		Use:   "bundle",
+		Short: "Print the ca certificate, copy the output to certs/server-config.yaml of each node",
+		Args:  cobra.NoArgs,
+		RunE: func(ccmd *cobra.Command, args []string) error {
+			ca, err := loadCA()
+			if err != nil {
+				return err
+			}
+			return writeTLSConfig(&connections.TLSConfig{CA: ca.Bundle()}, caf.OutFile)
+		},
+	}
+	ccmd.Flags().StringVarP(&caf.OutFile, "out-file", "f", "", "file to write the bundle to, stdout when not set")
+	return ccmd
+}
+
+func revokeCertificate() *cobra.Command {
+	ccmd := &cobra.Command{
+		Use:   "revoke [name]",
+		Short: "Revoke every certificate issued to name, or a single certificate with --serial",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(ccmd *cobra.Command, args []string) error {
+			if (len(args) == 0) == (caf.Serial == "") {
+				return errors.New("either a name or --serial is required")
+			}
+			ca, err := loadCA()
+			if err != nil {
+				return err
+			}
+			var n int
+			if caf.Serial != "" {
+				n, err = ca.RevokeSerial(caf.Serial)
+			} else {
+				n, err = ca.RevokeName(args[0], "")
+			}
+			if err != nil {
+				return err
+			}
+			if n == 0 {
+				return errors.New("no unexpired certificates matched")
+			}
+			fmt.Printf("revoked %d certificates, the coordination server picks up the new crl within a minute\n", n)
+			return nil
+		},
+	}
+	ccmd.Flags().StringVar(&caf.Serial, "serial", "", "serial number of the certificate to revoke")
+	return ccmd
+}
+
+func writeTLSConfig(tc *connections.TLSConfig, output string) error {
+	d, err := yaml.Marshal(tc)
+	if err != nil {
+		return err
+	}
+	if output == "" {
+		_, err = os.Stdout.Write(d)
+		return err
+	}
+	return os.WriteFile(output, d, 0600)
+}
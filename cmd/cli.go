@@ -2,18 +2,29 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strings"
+	"syscall"
+	"time"
 
+	pb "github.com/charles-d-burton/tailsys/commands"
+	"github.com/charles-d-burton/tailsys/connections"
+	"github.com/charles-d-burton/tailsys/logging"
 	"github.com/charles-d-burton/tailsys/services/client"
 	"github.com/charles-d-burton/tailsys/services/commander"
 	"github.com/charles-d-burton/tailsys/services/coordination"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
+	"google.golang.org/protobuf/types/known/durationpb"
 )
+
 func initConfig(cmd *cobra.Command) error {
 	v := viper.New()
 	if Check() {
@@ -80,13 +91,37 @@ type GlobalFlags struct {
 	Port            string
 	Hostname        string
 	Verbose         bool
+	LogFormat       string
 	ConfigDirectory string
+	Output          string
 }
 
 var gf = GlobalFlags{}
 
+// logger is set up from the global flags before any command runs
+var logger = slog.Default()
+
+// setupLogging creates the logger the flags ask for and makes it the default, so code without a logger of its own
+// logs the same way
+func setupLogging() error {
+	l, err := logging.New(os.Stderr, gf.LogFormat, gf.Verbose)
+	if err != nil {
+		return err
+	}
+	logger = l
+	slog.SetDefault(l)
+	return nil
+}
+
 func Start() error {
-	return rootCommand().Execute()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		//a second signal kills us right away instead of waiting for the shutdown
+		stop()
+	}()
+	return rootCommand().ExecuteContext(ctx)
 }
 
 func rootCommand() *cobra.Command {
@@ -94,7 +129,10 @@ func rootCommand() *cobra.Command {
 		Use:   "tailsys",
 		Short: "A configuration manager built for Tailscale",
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
-			return initConfig(cmd)
+			if err := initConfig(cmd); err != nil {
+				return err
+			}
+			return setupLogging()
 		},
 	}
 	rootCmd.PersistentFlags().StringVar(&gf.ClientId, "client-id", "", "Oauth client id")
@@ -112,18 +150,39 @@ func rootCommand() *cobra.Command {
 	rootCmd.PersistentFlags().StringVar(&gf.ConfigDirectory, "data-directory", getConfigDirectory(), "Set the location for the data store")
 	viper.BindPFlag("data-directory", rootCmd.PersistentFlags().Lookup("data-directory"))
 
-	rootCmd.PersistentFlags().BoolVarP(&gf.Verbose, "verbose", "v", false, "Verbose logging")
+	rootCmd.PersistentFlags().BoolVarP(&gf.Verbose, "verbose", "v", false, "Verbose logging, including debug messages and the logs of the tailnet connection")
+	rootCmd.PersistentFlags().StringVar(&gf.LogFormat, "log-format", logging.FormatText, "Format of the logs written to stderr, text or json")
+	viper.BindPFlag("log-format", rootCmd.PersistentFlags().Lookup("log-format"))
+	rootCmd.PersistentFlags().StringVarP(&gf.Output, "output", "o", string(commander.FormatTable), "Format results are written in, table, json, yaml or ndjson")
 
 	rootCmd.AddCommand(coodinationServerCommand())
 	rootCmd.AddCommand(clientCommand())
-	//	rootCmd.AddCommand(interactiveCommand())
+	rootCmd.AddCommand(interactiveCommand())
 	rootCmd.AddCommand(noninteractiveCommand())
+	rootCmd.AddCommand(copyCommand())
+	rootCmd.AddCommand(fetchCommand())
+	rootCmd.AddCommand(shellCommand())
+	rootCmd.AddCommand(keysCommand())
+	rootCmd.AddCommand(caCommand())
+	rootCmd.AddCommand(identityCommand())
+	rootCmd.AddCommand(nodesCommand())
 
 	return rootCmd
 }
 
 type coFlags struct {
-	DevMode bool
+	DevMode    bool
+	ACL        string
+	NodeGroups string
+	// DiscoveryTags is a comma separated list
+	DiscoveryTags     string
+	DiscoveryInterval time.Duration
+	DevicesFile       string
+	Health            coordination.HealthThresholds
+	ConnIdleTimeout   time.Duration
+	MaxSendSize       uint64
+	ShutdownTimeout   time.Duration
+	Metrics           metricsFlags
 }
 
 var cof = coFlags{}
@@ -134,15 +193,19 @@ func coodinationServerCommand() *cobra.Command {
 		Aliases: []string{"co"},
 		Short:   "Start the application coordination server",
 		RunE: func(ccmd *cobra.Command, args []string) error {
-			fmt.Println("starting the server code")
+			logger.Info("starting coordination server", "dev_mode", cof.DevMode, "data_dir", gf.ConfigDirectory)
 
 			var co coordination.Coordinator
-			fmt.Println("dev-mode: ", cof.DevMode)
-			fmt.Println("data-dir: ", gf.ConfigDirectory)
 
-			ctx := context.Background()
+			ctx := ccmd.Context()
 			err := co.NewCoordinator(ctx,
 				co.WithDevMode(cof.DevMode),
+				co.WithACL(cof.ACL),
+				co.WithNodeGroups(cof.NodeGroups),
+				co.WithDiscovery(splitTags(cof.DiscoveryTags), cof.DiscoveryInterval),
+				co.WithHealthThresholds(cof.Health),
+				co.WithConnIdleTimeout(cof.ConnIdleTimeout),
+				co.WithMaxSendSize(cof.MaxSendSize),
 			)
 
 			if err != nil {
@@ -158,24 +221,91 @@ func coodinationServerCommand() *cobra.Command {
 				co.WithScopes("devices", "logs:read", "routes:read"),
 				co.WithPort(gf.Port),
 				co.WithConfigDir(gf.ConfigDirectory),
+				co.WithMethodRoles(coordination.MethodRoles),
+				co.WithAuthorizer(co.Authorize),
+				co.WithDeviceLister(deviceLister(cof.DevicesFile)),
+				co.WithShutdownTimeout(cof.ShutdownTimeout),
+				co.WithLogger(logger),
+				co.WithTailnetLogging(gf.Verbose),
+				co.WithMetrics(cof.Metrics.Addr, cof.Metrics.Tailnet),
 			); err != nil {
 				return err
 			}
+			defer co.Close()
+			if err := co.ServeMetrics(ctx); err != nil {
+				return fmt.Errorf("unable to serve metrics: %w", err)
+			}
+			if err := co.LoadIdentity(); err != nil {
+				return err
+			}
 			err = co.StartDatabase(ctx)
 			if err != nil {
 				return err
 			}
+			defer co.CloseDB()
+			if err := co.StartCertificateAuthority(ctx); err != nil {
+				return err
+			}
+			logger.Info("create operator certificates with tailsys ca issue and the node trust bundle with tailsys ca bundle")
 			return co.StartRPCCoordinationServer(ctx)
 		},
 	}
-	ccmd.Flags().BoolVar(&cof.DevMode, "dev", false, "Enable dev mode, accept all incoming keys")
+	ccmd.Flags().BoolVar(&cof.DevMode, "dev", false, "Enable dev mode, accept all incoming keys and allow every request when there is no acl policy")
+	ccmd.Flags().StringVar(&cof.ACL, "acl", "", "ACL policy file that says who may send which commands to which nodes, <data-directory>/acl.yaml by default")
+	ccmd.Flags().StringVar(&cof.NodeGroups, "nodegroups", "", "file of named target expressions usable in patterns as N@name, <data-directory>/nodegroups.yaml by default")
+	ccmd.Flags().StringVar(&cof.DiscoveryTags, "discover-tags", "tag:tailsys", "Tailnet tags of the devices that should register, comma separated")
+	ccmd.Flags().DurationVar(&cof.DiscoveryInterval, "discover-interval", coordination.DefaultDiscoveryInterval, "how often to look for unregistered and missing nodes")
+	hd := coordination.DefaultHealthThresholds
+	ccmd.Flags().DurationVar(&cof.Health.Interval, "ping-interval", hd.Interval, "how often to ping each node")
+	ccmd.Flags().DurationVar(&cof.Health.Timeout, "ping-timeout", hd.Timeout, "how long a ping may take before it fails")
+	ccmd.Flags().DurationVar(&cof.Health.DegradedLatency, "degraded-latency", hd.DegradedLatency, "ping round trip time at which a node is degraded")
+	ccmd.Flags().IntVar(&cof.Health.OfflineFailures, "offline-after", hd.OfflineFailures, "failed pings in a row before a node is offline")
+	ccmd.Flags().DurationVar(&cof.Health.MaxBackoff, "max-ping-backoff", hd.MaxBackoff, "longest wait between pings of an offline node")
+	ccmd.Flags().DurationVar(&cof.ConnIdleTimeout, "conn-idle-timeout", coordination.DefaultConnIdleTimeout, "how long a connection to a node is kept open without being used")
+	ccmd.Flags().Uint64Var(&cof.MaxSendSize, "max-send-size", coordination.DefaultMaxSendSize, "largest file in bytes tailsys cp may send, uploads are buffered on disk before they go to the nodes")
+	ccmd.Flags().DurationVar(&cof.ShutdownTimeout, "shutdown-timeout", connections.DefaultShutdownTimeout, "how long running jobs and requests get to finish on shutdown before they are canceled")
+	ccmd.Flags().StringVar(&cof.DevicesFile, "devices-file", "", "read the tailnet devices from a JSON file in the format of the tailscale devices api instead of asking the api")
+	cof.Metrics.register(ccmd)
 
 	return ccmd
 }
 
+// splitTags splits a comma separated list of tags, ignoring empty ones
+func splitTags(list string) []string {
+	tags := make([]string, 0)
+	for _, tag := range strings.Split(list, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// deviceLister is the file to list devices from, nil to ask the tailscale api
+func deviceLister(file string) connections.DeviceLister {
+	if file == "" {
+		return nil
+	}
+	return connections.DeviceFile(file)
+}
+
+// metricsFlags say where the coordination server and the nodes serve their metrics
+type metricsFlags struct {
+	Addr    string
+	Tailnet bool
+}
+
+func (mf *metricsFlags) register(ccmd *cobra.Command) {
+	ccmd.Flags().StringVar(&mf.Addr, "metrics-addr", "", "serve prometheus metrics at /metrics on this address, like localhost:9100, off when empty")
+	ccmd.Flags().BoolVar(&mf.Tailnet, "metrics-tailnet", false, "serve the metrics on the tailnet at the port of --metrics-addr instead of on a local port")
+}
+
 type clientFlags struct {
-	DiscoveryTags      string
 	CoordinationServer string
+	Reverse            bool
+	HeartbeatInterval  time.Duration
+	ShutdownTimeout    time.Duration
+	Metrics            metricsFlags
 }
 
 var cif = clientFlags{}
@@ -186,8 +316,8 @@ func clientCommand() *cobra.Command {
 		Aliases: []string{"cl"},
 		Short:   "Start the application in client mode",
 		RunE: func(ccmd *cobra.Command, args []string) error {
-			fmt.Println("starting the client code")
-			ctx := context.Background()
+			logger.Info("starting node", "reverse", cif.Reverse, "data_dir", gf.ConfigDirectory)
+			ctx := ccmd.Context()
 
 			var cl client.Client
 			err := cl.NewClient(ctx)
@@ -205,43 +335,78 @@ func clientCommand() *cobra.Command {
 				cl.WithScopes("devices", "logs:read", "routes:read"),
 				cl.WithPort(gf.Port),
 				cl.WithConfigDir(gf.ConfigDirectory),
+				cl.WithMethodRoles(client.MethodRoles),
+				cl.WithAuthorizer(cl.Authorize),
+				cl.WithInboundListener(!cif.Reverse),
+				cl.WithShutdownTimeout(cif.ShutdownTimeout),
+				cl.WithLogger(logger),
+				cl.WithTailnetLogging(gf.Verbose),
+				cl.WithMetrics(cif.Metrics.Addr, cif.Metrics.Tailnet),
 			); err != nil {
 				return err
 			}
-			fmt.Println("registered, starting client operations")
+			defer cl.Close()
+			if err := cl.ServeMetrics(ctx); err != nil {
+				return fmt.Errorf("unable to serve metrics: %w", err)
+			}
+			if err := cl.LoadIdentity(); err != nil {
+				return err
+			}
 			err = cl.StartDatabase(ctx)
 			if err != nil {
 				return err
 			}
-			fmt.Println("connected, registering with coordination server")
+			defer cl.CloseDB()
 			if err := cl.RegisterWithCoordinationServer(ctx, coServer); err != nil {
 				return err
 			}
+			cl.StartCertificateRenewal(ctx, coServer)
+			if cif.Reverse {
+				cl.StartTunnel(ctx, coServer, cif.HeartbeatInterval)
+			}
 			return cl.StartRPCClientMode(ctx)
 
 		},
 	}
 	ccmd.Flags().StringVar(&cif.CoordinationServer, "coordination-server", "", "Hostname of the coordination server")
-	ccmd.Flags().StringVar(&cif.DiscoveryTags, "discover-tags", "", "Tailnet tags to filter and discover hosts")
+	ccmd.Flags().BoolVar(&cif.Reverse, "reverse", false, "Don't listen for the coordination server, keep a tunnel open to it and take commands through that")
+	ccmd.Flags().DurationVar(&cif.ShutdownTimeout, "shutdown-timeout", connections.DefaultShutdownTimeout, "how long running commands get to finish on shutdown before they are killed")
+	ccmd.Flags().DurationVar(&cif.HeartbeatInterval, "heartbeat-interval", client.DefaultHeartbeatInterval, "how often to send heartbeats through the tunnel in reverse mode")
+	cif.Metrics.register(ccmd)
 	return ccmd
 }
 
-// func interactiveCommand() *cobra.Command {
-// 	ccmd := &cobra.Command{
-// 		Use:     "interactive",
-// 		Aliases: []string{"i"},
-// 		Short:   "Start the application interactive ui",
-// 		Run: func(ccmd *cobra.Command, args []string) {
-
-// 		},
-// 	}
-// 	return ccmd
-// }
+func interactiveCommand() *cobra.Command {
+	ccmd := &cobra.Command{
+		Use:     "interactive",
+		Aliases: []string{"i"},
+		Short:   "Start the interactive ui to browse nodes and jobs and run commands on nodes",
+		RunE: func(ccmd *cobra.Command, args []string) error {
+			client, err := newCommanderClient(ccmd.Context(), cmdf.CoordinationServer)
+			if err != nil {
+				return err
+			}
+			return client.Interactive(ccmd.Context())
+		},
+	}
+	ccmd.Flags().StringVar(&cmdf.CoordinationServer, "coordination-server", "", "Hostname of the coordination server")
+	return ccmd
+}
 
 type cmdFlags struct {
 	Cmd                string
 	Pattern            string
 	CoordinationServer string
+	Timeout            time.Duration
+	Argv               []string
+	Shell              bool
+	Interpreter        string
+	Cwd                string
+	Env                []string
+	RunAs              string
+	Stdin              string
+	DryRun             bool
+	FailFast           bool
 }
 
 var cmdf = cmdFlags{}
@@ -253,49 +418,77 @@ func noninteractiveCommand() *cobra.Command {
 		Short:   "Start the command non-interactively",
 		// Run: func(ccmd *cobra.Command, args []string) {
 		// 	fmt.Println("starting the non-interactive code")
-  //     fmt.Println("coordination-server: ", cmdf.CoordinationServer)
+		//     fmt.Println("coordination-server: ", cmdf.CoordinationServer)
 		// },
 	}
 	ccmd.PersistentFlags().StringVar(&cmdf.CoordinationServer, "coordination-server", "", "Hostname of the coordination server")
-	ccmd.PersistentFlags().StringVar(&cmdf.Pattern, "pattern", "p", "pattern of nodes to look up")
-  ccmd.MarkFlagRequired("pattern")
-
-  ccmd.AddCommand(getNodes())
-  ccmd.AddCommand(sendCommandToNodes())
+	ccmd.PersistentFlags().StringVar(&cmdf.Pattern, "pattern", "p", `target expression of nodes, e.g. "web-*", "tag:prod and os=ubuntu" or "mem_gb>16 and not N@db". Bare words are case insensitive globs, not regular expressions, use E@ for those`)
+
+	ccmd.AddCommand(getNodes())
+	ccmd.AddCommand(sendCommandToNodes())
+	ccmd.AddCommand(commandHistory())
+	ccmd.AddCommand(showJob())
+	ccmd.AddCommand(jobCommand())
+	ccmd.AddCommand(stateCommand())
+	ccmd.AddCommand(shellSessions())
+	ccmd.AddCommand(shellTranscript())
 	return ccmd
 }
 
+// newCommanderClient connects to the tailnet to talk to the coordination server
+func newCommanderClient(ctx context.Context, server string) (*commander.Client, error) {
+	client := &commander.Client{}
+	if err := client.NewClient(ctx, client.WithCoordinationServer(server), client.WithOutput(commander.Format(gf.Output))); err != nil {
+		return nil, err
+	}
+
+	if err := client.ConnectCmd(ctx,
+		client.WithAuthKey(gf.AuthKey),
+		client.WithOauth(gf.ClientId, gf.ClientSecret),
+		client.WithHostname(gf.Hostname),
+		client.WithTags("tag:tailsys"),
+		client.WithScopes("devices", "logs:read", "routes:read"),
+		client.WithPort(gf.Port),
+		client.WithConfigDir(gf.ConfigDirectory),
+		client.WithLogger(logger),
+		client.WithTailnetLogging(gf.Verbose),
+	); err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// runErr keeps cobra from printing the usage when a command failed on nodes, the flags were fine
+func runErr(ccmd *cobra.Command, err error) error {
+	var failed *commander.FailedError
+	if errors.As(err, &failed) {
+		ccmd.SilenceUsage = true
+	}
+	return err
+}
+
+// requirePattern fails the command unless a pattern was given, not every subcommand of cmd targets nodes
+func requirePattern(ccmd *cobra.Command, args []string) error {
+	if !ccmd.Flags().Changed("pattern") {
+		return errors.New(`required flag(s) "pattern" not set`)
+	}
+	return nil
+}
+
 func getNodes() *cobra.Command {
 	ccmd := &cobra.Command{
 		Use:     "get-nodes",
 		Aliases: []string{"gn"},
 		Short:   "Use a pattern to find nodes",
+		PreRunE: requirePattern,
 		RunE: func(ccmd *cobra.Command, args []string) error {
-      fmt.Printf("getting nodes that match pattern: %s\n", cmdf.Pattern)
-      var client commander.Client
-      if err := client.NewClient(
-        ccmd.Context(),
-        client.WithCoordinationServer(cmdf.CoordinationServer),
-        ); err != nil {
-          return err
-        }
-
-			if err := client.ConnectCmd(ccmd.Context(),
-				client.WithAuthKey(gf.AuthKey),
-				client.WithOauth(gf.ClientId, gf.ClientSecret),
-				client.WithHostname(gf.Hostname),
-				client.WithTags("tag:tailsys"),
-				client.WithScopes("devices", "logs:read", "routes:read"),
-				client.WithPort(gf.Port),
-				client.WithConfigDir(gf.ConfigDirectory),
-			); err != nil {
+			logger.Debug("getting nodes", "pattern", cmdf.Pattern)
+			client, err := newCommanderClient(ccmd.Context(), cmdf.CoordinationServer)
+			if err != nil {
 				return err
 			}
 
-      if err := client.GetNodes(ccmd.Context(), cmdf.Pattern); err != nil {
-        fmt.Println(fmt.Errorf("error getting nodes %w", err))
-      }
-      return nil
+			return client.GetNodes(ccmd.Context(), cmdf.Pattern)
 		},
 	}
 	return ccmd
@@ -306,41 +499,212 @@ func sendCommandToNodes() *cobra.Command {
 		Use:     "send-command",
 		Aliases: []string{"sc"},
 		Short:   "Use a pattern to send command to nodes",
+		PreRunE: requirePattern,
 		RunE: func(ccmd *cobra.Command, args []string) error {
-      fmt.Printf("sending command: %s\n that match pattern %s\n", cmdf.Cmd, cmdf.Pattern)
-      var client commander.Client
-      if err := client.NewClient(
-        ccmd.Context(),
-        client.WithCoordinationServer(cmdf.CoordinationServer),
-        ); err != nil {
-          return err
-        }
-
-			if err := client.ConnectCmd(ccmd.Context(),
-				client.WithAuthKey(gf.AuthKey),
-				client.WithOauth(gf.ClientId, gf.ClientSecret),
-				client.WithHostname(gf.Hostname),
-				client.WithTags("tag:tailsys"),
-				client.WithScopes("devices", "logs:read", "routes:read"),
-				client.WithPort(gf.Port),
-				client.WithConfigDir(gf.ConfigDirectory),
-			); err != nil {
+			req, err := commanderRequest()
+			if err != nil {
+				return err
+			}
+
+			client, err := newCommanderClient(ccmd.Context(), cmdf.CoordinationServer)
+			if err != nil {
+				return err
+			}
+			if cmdf.DryRun {
+				return client.GetNodes(ccmd.Context(), cmdf.Pattern)
+			}
+
+			logger.Debug("sending command", "command", cmdf.Cmd, "pattern", cmdf.Pattern)
+
+			return runErr(ccmd, client.SendCommand(ccmd.Context(), req, cmdf.FailFast))
+		},
+	}
+	addCommandFlags(ccmd)
+	ccmd.Flags().BoolVar(&cmdf.FailFast, "fail-fast", false, "cancel the command on the other nodes as soon as it fails on one")
+
+	return ccmd
+}
+
+// addCommandFlags adds the flags describing the command to run on each node
+func addCommandFlags(ccmd *cobra.Command) {
+	ccmd.Flags().StringVar(&cmdf.Cmd, "command", "", "command to send to nodes")
+	ccmd.Flags().StringArrayVar(&cmdf.Argv, "argv", nil, "argument vector to run without any splitting, repeat for each argument")
+	ccmd.MarkFlagsMutuallyExclusive("command", "argv")
+	ccmd.Flags().DurationVar(&cmdf.Timeout, "timeout", 0, "kill the command on each node if it runs longer than this, e.g. 30s")
+	ccmd.Flags().BoolVar(&cmdf.Shell, "shell", false, "run the command through a shell so quoting, pipes and redirects work")
+	ccmd.Flags().StringVar(&cmdf.Interpreter, "interpreter", "", "interpreter used in shell mode (default \"/bin/sh -c\")")
+	ccmd.Flags().StringVar(&cmdf.Cwd, "cwd", "", "working directory to run the command in")
+	ccmd.Flags().StringArrayVar(&cmdf.Env, "env", nil, "extra environment variable in the form KEY=VALUE, repeat for each variable")
+	ccmd.Flags().StringVar(&cmdf.RunAs, "run-as", "", "user to run the command as")
+	ccmd.Flags().StringVar(&cmdf.Stdin, "stdin", "", "file to send to the command on stdin, - reads from this process's stdin")
+	ccmd.Flags().BoolVar(&cmdf.DryRun, "dry-run", false, "list the nodes the command would be sent to without sending it")
+}
+
+func commandHistory() *cobra.Command {
+	var limit int
+	ccmd := &cobra.Command{
+		Use:     "history",
+		Aliases: []string{"hist"},
+		Short:   "List the most recent jobs sent to nodes",
+		RunE: func(ccmd *cobra.Command, args []string) error {
+			client, err := newCommanderClient(ccmd.Context(), cmdf.CoordinationServer)
+			if err != nil {
 				return err
 			}
+			return client.History(ccmd.Context(), limit)
+		},
+	}
+	ccmd.Flags().IntVar(&limit, "limit", 25, "maximum number of jobs to list")
+	return ccmd
+}
 
-      if err := client.SendCommand(ccmd.Context(), cmdf.Cmd, cmdf.Pattern); err != nil {
-        fmt.Println(fmt.Errorf("error getting nodes %w", err))
-      }
+func showJob() *cobra.Command {
+	ccmd := &cobra.Command{
+		Use:   "show <job-id>",
+		Short: "Show the result of a job on every node it ran on",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(ccmd *cobra.Command, args []string) error {
+			client, err := newCommanderClient(ccmd.Context(), cmdf.CoordinationServer)
+			if err != nil {
+				return err
+			}
+			return client.ShowJob(ccmd.Context(), args[0])
+		},
+	}
+	return ccmd
+}
 
-      return nil
+func jobCommand() *cobra.Command {
+	ccmd := &cobra.Command{
+		Use:   "job",
+		Short: "Run commands on nodes in the background and follow their progress",
+	}
+	ccmd.AddCommand(submitJob())
+	ccmd.AddCommand(getJob())
+	ccmd.AddCommand(watchJob())
+	ccmd.AddCommand(cancelJob())
+	return ccmd
+}
+
+func submitJob() *cobra.Command {
+	ccmd := &cobra.Command{
+		Use:     "submit",
+		Short:   "Start a command on the nodes matching a pattern and print the job id without waiting for it",
+		PreRunE: requirePattern,
+		RunE: func(ccmd *cobra.Command, args []string) error {
+			req, err := commanderRequest()
+			if err != nil {
+				return err
+			}
+			client, err := newCommanderClient(ccmd.Context(), cmdf.CoordinationServer)
+			if err != nil {
+				return err
+			}
+			if cmdf.DryRun {
+				return client.GetNodes(ccmd.Context(), cmdf.Pattern)
+			}
+			return client.SubmitJob(ccmd.Context(), req)
 		},
 	}
-  ccmd.Flags().StringVar(&cmdf.Cmd, "command", "c", "command to send to nodes")
-  ccmd.MarkFlagRequired("command")
+	addCommandFlags(ccmd)
+	return ccmd
+}
 
+func getJob() *cobra.Command {
+	ccmd := &cobra.Command{
+		Use:   "get <job-id>",
+		Short: "Show the progress of a job on every node",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(ccmd *cobra.Command, args []string) error {
+			client, err := newCommanderClient(ccmd.Context(), cmdf.CoordinationServer)
+			if err != nil {
+				return err
+			}
+			return client.ShowJob(ccmd.Context(), args[0])
+		},
+	}
+	return ccmd
+}
+
+func watchJob() *cobra.Command {
+	ccmd := &cobra.Command{
+		Use:   "watch <job-id>",
+		Short: "Follow the output of a job until it finishes",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(ccmd *cobra.Command, args []string) error {
+			client, err := newCommanderClient(ccmd.Context(), cmdf.CoordinationServer)
+			if err != nil {
+				return err
+			}
+			return runErr(ccmd, client.WatchJob(ccmd.Context(), args[0]))
+		},
+	}
+	return ccmd
+}
+
+func cancelJob() *cobra.Command {
+	ccmd := &cobra.Command{
+		Use:   "cancel <job-id>",
+		Short: "Stop a job, pending nodes are skipped and running commands are interrupted",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(ccmd *cobra.Command, args []string) error {
+			client, err := newCommanderClient(ccmd.Context(), cmdf.CoordinationServer)
+			if err != nil {
+				return err
+			}
+			return client.CancelJob(ccmd.Context(), args[0])
+		},
+	}
 	return ccmd
 }
 
+// commanderRequest builds the request to the coordinator from the send-command flags
+func commanderRequest() (*pb.CommanderRequest, error) {
+	if cmdf.Cmd == "" && len(cmdf.Argv) == 0 {
+		return nil, errors.New("one of --command or --argv is required")
+	}
+	req := &pb.CommanderRequest{
+		Pattern:     cmdf.Pattern,
+		Command:     cmdf.Cmd,
+		Argv:        cmdf.Argv,
+		Shell:       cmdf.Shell,
+		Interpreter: cmdf.Interpreter,
+		Cwd:         cmdf.Cwd,
+		RunAs:       cmdf.RunAs,
+	}
+	if cmdf.Timeout > 0 {
+		req.Timeout = durationpb.New(cmdf.Timeout)
+	}
+
+	if len(cmdf.Env) > 0 {
+		req.Env = make(map[string]string, len(cmdf.Env))
+		for _, kv := range cmdf.Env {
+			k, v, ok := strings.Cut(kv, "=")
+			if !ok || k == "" {
+				return nil, fmt.Errorf("environment variable %s must be in the form KEY=VALUE", kv)
+			}
+			req.Env[k] = v
+		}
+	}
+
+	switch cmdf.Stdin {
+	case "":
+	case "-":
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, err
+		}
+		req.Stdin = data
+	default:
+		data, err := os.ReadFile(cmdf.Stdin)
+		if err != nil {
+			return nil, err
+		}
+		req.Stdin = data
+	}
+	return req, nil
+}
+
 func getConfigDirectory() string {
 	ddir := ""
 	if Check() {
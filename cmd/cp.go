@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	pb "github.com/charles-d-burton/tailsys/commands"
+	"github.com/spf13/cobra"
+)
+
+type cpFlags struct {
+	Mode     string
+	Owner    string
+	Group    string
+	MakeDirs bool
+}
+
+var cpf = cpFlags{}
+
+func copyCommand() *cobra.Command {
+	ccmd := &cobra.Command{
+		Use:   "cp <local> <pattern>:<remote>",
+		Short: "Copy a local file to the nodes matching the pattern",
+		Example: `  tailsys cp nginx.conf "web-*:/etc/nginx/nginx.conf" --mode 0644 --owner root
+  tailsys cp motd "tag:prod and os=ubuntu:/etc/motd"`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(ccmd *cobra.Command, args []string) error {
+			pattern, remote, err := splitRemote(args[1])
+			if err != nil {
+				return err
+			}
+			header := &pb.FileHeader{
+				Path:     remote,
+				Owner:    cpf.Owner,
+				Group:    cpf.Group,
+				Makedirs: cpf.MakeDirs,
+			}
+			if cpf.Mode != "" {
+				m, err := strconv.ParseUint(cpf.Mode, 8, 32)
+				if err != nil || m > 0o7777 {
+					return fmt.Errorf("invalid mode %q, use an octal mode like 0644", cpf.Mode)
+				}
+				header.Mode = uint32(m)
+			}
+
+			f, err := os.Open(args[0])
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			fi, err := f.Stat()
+			if err != nil {
+				return err
+			}
+			if !fi.Mode().IsRegular() {
+				return fmt.Errorf("%s is not a regular file", args[0])
+			}
+			sum := sha256.New()
+			size, err := io.Copy(sum, f)
+			if err != nil {
+				return fmt.Errorf("unable to read %s: %w", args[0], err)
+			}
+			header.Size = uint64(size)
+			header.Sha256 = sum.Sum(nil)
+
+			client, err := newCommanderClient(ccmd.Context(), cmdf.CoordinationServer)
+			if err != nil {
+				return err
+			}
+			logger.Debug("copying file", "file", args[0], "pattern", pattern, "path", remote, "size", size)
+			return runErr(ccmd, client.SendFile(ccmd.Context(), pattern, header, f))
+		},
+	}
+	ccmd.Flags().StringVar(&cmdf.CoordinationServer, "coordination-server", "", "Hostname of the coordination server")
+	ccmd.Flags().StringVar(&cpf.Mode, "mode", "", "octal permissions of the file, those of the file being replaced or 0644 when not set")
+	ccmd.Flags().StringVar(&cpf.Owner, "owner", "", "user owning the file on the nodes")
+	ccmd.Flags().StringVar(&cpf.Group, "group", "", "group owning the file on the nodes")
+	ccmd.Flags().BoolVar(&cpf.MakeDirs, "makedirs", false, "create the missing directories of the remote path")
+	return ccmd
+}
+
+// splitRemote splits the <pattern>:<remote> argument of cp and fetch. The remote path starts at the first :/ so
+// patterns like tag:prod keep their colon.
+func splitRemote(arg string) (string, string, error) {
+	i := strings.Index(arg, ":/")
+	if i <= 0 {
+		return "", "", fmt.Errorf("%q has to be <pattern>:<absolute path>", arg)
+	}
+	return arg[:i], arg[i+1:], nil
+}
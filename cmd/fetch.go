@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	pb "github.com/charles-d-burton/tailsys/commands"
+	"github.com/spf13/cobra"
+)
+
+type fetchFlags struct {
+	MaxSize uint64
+}
+
+var ff = fetchFlags{}
+
+func fetchCommand() *cobra.Command {
+	ccmd := &cobra.Command{
+		Use:   "fetch <pattern>:<remote> <local-dir>",
+		Short: "Fetch a file or directory from the nodes matching the pattern into <local-dir>/<hostname>",
+		Example: `  tailsys fetch "web-*:/var/log/nginx" ./logs
+  tailsys fetch "tag:prod:/etc/nginx/nginx.conf" ./configs`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(ccmd *cobra.Command, args []string) error {
+			pattern, remote, err := splitRemote(args[0])
+			if err != nil {
+				return err
+			}
+			req := &pb.FileFetchRequest{
+				Pattern: pattern,
+				Path:    remote,
+				MaxSize: ff.MaxSize,
+			}
+
+			client, err := newCommanderClient(ccmd.Context(), cmdf.CoordinationServer)
+			if err != nil {
+				return err
+			}
+			logger.Debug("fetching file", "pattern", pattern, "path", remote, "dir", args[1])
+			return runErr(ccmd, client.FetchFile(ccmd.Context(), req, args[1]))
+		},
+	}
+	ccmd.Flags().StringVar(&cmdf.CoordinationServer, "coordination-server", "", "Hostname of the coordination server")
+	ccmd.Flags().Uint64Var(&ff.MaxSize, "max-size", 0, "most bytes each node may send, a directory counts as its tar archive (default 100MiB)")
+	return ccmd
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/charles-d-burton/tailsys/services"
+	"github.com/spf13/cobra"
+)
+
+func identityCommand() *cobra.Command {
+	ccmd := &cobra.Command{
+		Use:   "identity",
+		Short: "Manage the identity this system is known by",
+	}
+	ccmd.AddCommand(showIdentity())
+	ccmd.AddCommand(initIdentity())
+	return ccmd
+}
+
+func showIdentity() *cobra.Command {
+	ccmd := &cobra.Command{
+		Use:   "show",
+		Short: "Show the id and public key kept in the data directory",
+		Args:  cobra.NoArgs,
+		RunE: func(ccmd *cobra.Command, args []string) error {
+			id, err := services.ReadIdentity(gf.ConfigDirectory)
+			if errors.Is(err, services.ErrNoIdentity) {
+				return fmt.Errorf("%w, create one with identity init", err)
+			}
+			if err != nil {
+				return err
+			}
+			printIdentity(id)
+			return nil
+		},
+	}
+	return ccmd
+}
+
+func initIdentity() *cobra.Command {
+	ccmd := &cobra.Command{
+		Use:   "init",
+		Short: "Create the identity kept in the data directory, or show it if it already exists",
+		Args:  cobra.NoArgs,
+		RunE: func(ccmd *cobra.Command, args []string) error {
+			id, err := services.LoadIdentity(gf.ConfigDirectory, logger)
+			if err != nil {
+				return err
+			}
+			printIdentity(id)
+			return nil
+		},
+	}
+	return ccmd
+}
+
+func printIdentity(id *services.NodeIdentity) {
+	fmt.Printf("id:         %s\n", id.ID)
+	fmt.Printf("public key: %s\n", id.EncodedPublicKey())
+	fmt.Printf("key file:   %s\n", id.Path)
+}
@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	pb "github.com/charles-d-burton/tailsys/commands"
+	"github.com/spf13/cobra"
+)
+
+type keysFlags struct {
+	CoordinationServer string
+	Status             string
+}
+
+var kf = keysFlags{}
+
+func keysCommand() *cobra.Command {
+	ccmd := &cobra.Command{
+		Use:   "keys",
+		Short: "Accept, reject and delete the nodes registered with the coordination server",
+	}
+	ccmd.PersistentFlags().StringVar(&kf.CoordinationServer, "coordination-server", "", "Hostname of the coordination server")
+	ccmd.PersistentFlags().StringVar(&kf.Status, "status", "", "only act on keys with this status: pending, accepted, rejected or revoked")
+
+	ccmd.AddCommand(listKeys())
+	ccmd.AddCommand(acceptKeys())
+	ccmd.AddCommand(rejectKeys())
+	ccmd.AddCommand(deleteKeys())
+	return ccmd
+}
+
+func listKeys() *cobra.Command {
+	ccmd := &cobra.Command{
+		Use:   "list [glob]",
+		Short: "List the nodes matching the glob, every node when no glob is given",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(ccmd *cobra.Command, args []string) error {
+			query, err := keyQuery(args)
+			if err != nil {
+				return err
+			}
+			client, err := newCommanderClient(ccmd.Context(), kf.CoordinationServer)
+			if err != nil {
+				return err
+			}
+			return client.ListKeys(ccmd.Context(), query)
+		},
+	}
+	return ccmd
+}
+
+func acceptKeys() *cobra.Command {
+	ccmd := &cobra.Command{
+		Use:   "accept <glob>",
+		Short: "Allow the pending nodes matching the glob to receive commands, use --status to accept rejected or revoked nodes",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(ccmd *cobra.Command, args []string) error {
+			query, err := keyQuery(args)
+			if err != nil {
+				return err
+			}
+			client, err := newCommanderClient(ccmd.Context(), kf.CoordinationServer)
+			if err != nil {
+				return err
+			}
+			return client.AcceptKeys(ccmd.Context(), query)
+		},
+	}
+	return ccmd
+}
+
+func rejectKeys() *cobra.Command {
+	ccmd := &cobra.Command{
+		Use:   "reject <glob>",
+		Short: "Reject the pending nodes and revoke the accepted nodes matching the glob",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(ccmd *cobra.Command, args []string) error {
+			query, err := keyQuery(args)
+			if err != nil {
+				return err
+			}
+			client, err := newCommanderClient(ccmd.Context(), kf.CoordinationServer)
+			if err != nil {
+				return err
+			}
+			return client.RejectKeys(ccmd.Context(), query)
+		},
+	}
+	return ccmd
+}
+
+func deleteKeys() *cobra.Command {
+	ccmd := &cobra.Command{
+		Use:   "delete <glob>",
+		Short: "Forget the nodes matching the glob, they have to be accepted again after they re-register",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(ccmd *cobra.Command, args []string) error {
+			query, err := keyQuery(args)
+			if err != nil {
+				return err
+			}
+			client, err := newCommanderClient(ccmd.Context(), kf.CoordinationServer)
+			if err != nil {
+				return err
+			}
+			return client.DeleteKeys(ccmd.Context(), query)
+		},
+	}
+	return ccmd
+}
+
+// keyQuery builds the query from the glob argument and the status flag
+func keyQuery(args []string) (*pb.KeyQuery, error) {
+	query := &pb.KeyQuery{}
+	if len(args) > 0 {
+		query.Pattern = args[0]
+	}
+	if kf.Status != "" {
+		status, ok := pb.KeyStatus_value["KEY_"+strings.ToUpper(kf.Status)]
+		if !ok || status == int32(pb.KeyStatus_KEY_STATUS_UNSPECIFIED) {
+			return nil, fmt.Errorf("unknown key status %s, must be one of pending, accepted, rejected or revoked", kf.Status)
+		}
+		query.Status = pb.KeyStatus(status)
+	}
+	return query, nil
+}
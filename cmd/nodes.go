@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	pb "github.com/charles-d-burton/tailsys/commands"
+	"github.com/spf13/cobra"
+)
+
+type nodesFlags struct {
+	CoordinationServer string
+	Refresh            bool
+	Long               bool
+	All                bool
+	History            int
+}
+
+var nf = nodesFlags{}
+
+func nodesCommand() *cobra.Command {
+	ccmd := &cobra.Command{
+		Use:   "nodes",
+		Short: "Look at the nodes registered with the coordination server",
+	}
+	ccmd.PersistentFlags().StringVar(&nf.CoordinationServer, "coordination-server", "", "Hostname of the coordination server")
+
+	ccmd.AddCommand(nodeInventory())
+	ccmd.AddCommand(discoverNodes())
+	ccmd.AddCommand(nodeStatus())
+	return ccmd
+}
+
+func nodeInventory() *cobra.Command {
+	ccmd := &cobra.Command{
+		Use:   "inventory [pattern]",
+		Short: "Show the inventory of the accepted nodes matching the pattern, every node when no pattern is given",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(ccmd *cobra.Command, args []string) error {
+			query := &pb.InventoryQuery{Refresh: nf.Refresh}
+			if len(args) > 0 {
+				query.Pattern = args[0]
+			}
+			client, err := newCommanderClient(ccmd.Context(), nf.CoordinationServer)
+			if err != nil {
+				return err
+			}
+			return client.GetInventory(ccmd.Context(), query, nf.Long)
+		},
+	}
+	ccmd.Flags().BoolVar(&nf.Refresh, "refresh", false, "ask the nodes for their inventory instead of showing what was collected last")
+	ccmd.Flags().BoolVarP(&nf.Long, "long", "l", false, "show disks and network interfaces too")
+	return ccmd
+}
+
+func discoverNodes() *cobra.Command {
+	ccmd := &cobra.Command{
+		Use:   "discover",
+		Short: "Show tagged tailnet devices that haven't registered and accepted nodes that are gone from the tailnet",
+		Args:  cobra.NoArgs,
+		RunE: func(ccmd *cobra.Command, args []string) error {
+			client, err := newCommanderClient(ccmd.Context(), nf.CoordinationServer)
+			if err != nil {
+				return err
+			}
+			return client.DiscoverNodes(ccmd.Context(), nf.Refresh, nf.All)
+		},
+	}
+	ccmd.Flags().BoolVar(&nf.Refresh, "refresh", false, "list the tailnet now instead of showing the last discovery")
+	ccmd.Flags().BoolVarP(&nf.All, "all", "a", false, "show the registered devices too")
+	return ccmd
+}
+
+func nodeStatus() *cobra.Command {
+	ccmd := &cobra.Command{
+		Use:   "status [pattern]",
+		Short: "Show whether the accepted nodes matching the pattern are online, every node when no pattern is given",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(ccmd *cobra.Command, args []string) error {
+			pattern := ""
+			if len(args) > 0 {
+				pattern = args[0]
+			}
+			client, err := newCommanderClient(ccmd.Context(), nf.CoordinationServer)
+			if err != nil {
+				return err
+			}
+			return client.NodeStatus(ccmd.Context(), pattern, nf.History)
+		},
+	}
+	ccmd.Flags().IntVar(&nf.History, "history", 0, "show this many of the latest pings of each node")
+	return ccmd
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	pb "github.com/charles-d-burton/tailsys/commands"
+	"github.com/spf13/cobra"
+)
+
+type shellFlags struct {
+	User string
+}
+
+var shf = shellFlags{}
+
+func shellCommand() *cobra.Command {
+	ccmd := &cobra.Command{
+		Use:   "shell <host> [-- command...]",
+		Short: "Open an interactive shell on a node, the session is recorded on the coordination server",
+		Example: `  tailsys shell web-1
+  tailsys shell web-1 --user deploy
+  tailsys shell db-1 -- top`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(ccmd *cobra.Command, args []string) error {
+			//^C belongs to the remote shell, only being terminated or hung up ends the session
+			ctx, stop := signal.NotifyContext(context.WithoutCancel(ccmd.Context()), syscall.SIGTERM, syscall.SIGHUP)
+			defer stop()
+			open := &pb.ShellOpen{
+				Host:    args[0],
+				User:    shf.User,
+				Command: args[1:],
+			}
+			client, err := newCommanderClient(ctx, cmdf.CoordinationServer)
+			if err != nil {
+				return err
+			}
+			logger.Debug("opening shell", "host", args[0], "user", shf.User)
+			exit, err := client.Shell(ctx, open)
+			if err != nil {
+				return err
+			}
+			switch {
+			case exit.Error != "":
+				fmt.Fprintf(os.Stderr, "shell failed: %s\n", exit.Error)
+			case exit.Signal != "":
+				fmt.Fprintf(os.Stderr, "shell killed by %s\n", exit.Signal)
+			case exit.ExitCode != 0:
+				fmt.Fprintf(os.Stderr, "shell exited with status %d\n", exit.ExitCode)
+			}
+			return nil
+		},
+	}
+	ccmd.Flags().StringVar(&cmdf.CoordinationServer, "coordination-server", "", "Hostname of the coordination server")
+	ccmd.Flags().StringVar(&shf.User, "user", "", "user the shell runs as, the user of the node when not set")
+	return ccmd
+}
+
+func shellSessions() *cobra.Command {
+	var limit int
+	ccmd := &cobra.Command{
+		Use:   "sessions",
+		Short: "List the most recent shell sessions opened on nodes",
+		RunE: func(ccmd *cobra.Command, args []string) error {
+			client, err := newCommanderClient(ccmd.Context(), cmdf.CoordinationServer)
+			if err != nil {
+				return err
+			}
+			return client.ShellSessions(ccmd.Context(), limit)
+		},
+	}
+	ccmd.Flags().IntVar(&limit, "limit", 25, "maximum number of sessions to list")
+	return ccmd
+}
+
+func shellTranscript() *cobra.Command {
+	ccmd := &cobra.Command{
+		Use:   "session <session-id>",
+		Short: "Print the asciicast recording of a shell session, play it with asciinema play",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(ccmd *cobra.Command, args []string) error {
+			client, err := newCommanderClient(ccmd.Context(), cmdf.CoordinationServer)
+			if err != nil {
+				return err
+			}
+			return client.ShellTranscript(ccmd.Context(), args[0], os.Stdout)
+		},
+	}
+	return ccmd
+}
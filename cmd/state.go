@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	pb "github.com/charles-d-burton/tailsys/commands"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+type stateFlags struct {
+	Test    bool
+	Timeout time.Duration
+}
+
+var sf = stateFlags{}
+
+func stateCommand() *cobra.Command {
+	ccmd := &cobra.Command{
+		Use:   "state",
+		Short: "Apply declarative states to nodes",
+	}
+	ccmd.AddCommand(applyState())
+	return ccmd
+}
+
+func applyState() *cobra.Command {
+	ccmd := &cobra.Command{
+		Use:     "apply <state-file>",
+		Short:   "Apply the state file to the nodes matching the pattern, - reads it from stdin",
+		Args:    cobra.ExactArgs(1),
+		PreRunE: requirePattern,
+		RunE: func(ccmd *cobra.Command, args []string) error {
+			var data []byte
+			var err error
+			if args[0] == "-" {
+				data, err = io.ReadAll(os.Stdin)
+			} else {
+				data, err = os.ReadFile(args[0])
+			}
+			if err != nil {
+				return fmt.Errorf("unable to read state: %w", err)
+			}
+			req := &pb.StateApplyRequest{
+				Pattern: cmdf.Pattern,
+				State:   data,
+				Test:    sf.Test,
+			}
+			if sf.Timeout > 0 {
+				req.Timeout = durationpb.New(sf.Timeout)
+			}
+
+			client, err := newCommanderClient(ccmd.Context(), cmdf.CoordinationServer)
+			if err != nil {
+				return err
+			}
+			logger.Debug("applying state", "file", args[0], "pattern", cmdf.Pattern, "test", sf.Test)
+			return runErr(ccmd, client.ApplyState(ccmd.Context(), req))
+		},
+	}
+	ccmd.Flags().BoolVar(&sf.Test, "test", false, "only show what would change on each node without changing anything")
+	ccmd.Flags().DurationVar(&sf.Timeout, "timeout", 0, "how long each node may take to apply the state (default 10m)")
+	return ccmd
+}
@@ -19,7 +19,8 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	CommandRunner_Command_FullMethodName = "/tailsys.CommandRunner/Command"
+	CommandRunner_Command_FullMethodName       = "/tailsys.CommandRunner/Command"
+	CommandRunner_CommandStream_FullMethodName = "/tailsys.CommandRunner/CommandStream"
 )
 
 // CommandRunnerClient is the client API for CommandRunner service.
@@ -27,6 +28,7 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type CommandRunnerClient interface {
 	Command(ctx context.Context, in *CommandRequest, opts ...grpc.CallOption) (*CommandResponse, error)
+	CommandStream(ctx context.Context, in *CommandRequest, opts ...grpc.CallOption) (CommandRunner_CommandStreamClient, error)
 }
 
 type commandRunnerClient struct {
@@ -46,11 +48,44 @@ func (c *commandRunnerClient) Command(ctx context.Context, in *CommandRequest, o
 	return out, nil
 }
 
+func (c *commandRunnerClient) CommandStream(ctx context.Context, in *CommandRequest, opts ...grpc.CallOption) (CommandRunner_CommandStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &CommandRunner_ServiceDesc.Streams[0], CommandRunner_CommandStream_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &commandRunnerCommandStreamClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type CommandRunner_CommandStreamClient interface {
+	Recv() (*CommandStreamResponse, error)
+	grpc.ClientStream
+}
+
+type commandRunnerCommandStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *commandRunnerCommandStreamClient) Recv() (*CommandStreamResponse, error) {
+	m := new(CommandStreamResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // CommandRunnerServer is the server API for CommandRunner service.
 // All implementations must embed UnimplementedCommandRunnerServer
 // for forward compatibility
 type CommandRunnerServer interface {
 	Command(context.Context, *CommandRequest) (*CommandResponse, error)
+	CommandStream(*CommandRequest, CommandRunner_CommandStreamServer) error
 	mustEmbedUnimplementedCommandRunnerServer()
 }
 
@@ -61,6 +96,9 @@ type UnimplementedCommandRunnerServer struct {
 func (UnimplementedCommandRunnerServer) Command(context.Context, *CommandRequest) (*CommandResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Command not implemented")
 }
+func (UnimplementedCommandRunnerServer) CommandStream(*CommandRequest, CommandRunner_CommandStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method CommandStream not implemented")
+}
 func (UnimplementedCommandRunnerServer) mustEmbedUnimplementedCommandRunnerServer() {}
 
 // UnsafeCommandRunnerServer may be embedded to opt out of forward compatibility for this service.
@@ -92,6 +130,27 @@ func _CommandRunner_Command_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CommandRunner_CommandStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(CommandRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CommandRunnerServer).CommandStream(m, &commandRunnerCommandStreamServer{stream})
+}
+
+type CommandRunner_CommandStreamServer interface {
+	Send(*CommandStreamResponse) error
+	grpc.ServerStream
+}
+
+type commandRunnerCommandStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *commandRunnerCommandStreamServer) Send(m *CommandStreamResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // CommandRunner_ServiceDesc is the grpc.ServiceDesc for CommandRunner service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -104,7 +163,13 @@ var CommandRunner_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _CommandRunner_Command_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "CommandStream",
+			Handler:       _CommandRunner_CommandStream_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "command.proto",
 }
 
@@ -112,6 +177,24 @@ const (
 	CommandManager_GetNodes_FullMethodName                 = "/tailsys.CommandManager/GetNodes"
 	CommandManager_SendCommandToNodes_FullMethodName       = "/tailsys.CommandManager/SendCommandToNodes"
 	CommandManager_SendCommandToNodesStream_FullMethodName = "/tailsys.CommandManager/SendCommandToNodesStream"
+	CommandManager_ListJobs_FullMethodName                 = "/tailsys.CommandManager/ListJobs"
+	CommandManager_GetJob_FullMethodName                   = "/tailsys.CommandManager/GetJob"
+	CommandManager_SubmitJob_FullMethodName                = "/tailsys.CommandManager/SubmitJob"
+	CommandManager_WatchJob_FullMethodName                 = "/tailsys.CommandManager/WatchJob"
+	CommandManager_CancelJob_FullMethodName                = "/tailsys.CommandManager/CancelJob"
+	CommandManager_ListKeys_FullMethodName                 = "/tailsys.CommandManager/ListKeys"
+	CommandManager_AcceptKeys_FullMethodName               = "/tailsys.CommandManager/AcceptKeys"
+	CommandManager_RejectKeys_FullMethodName               = "/tailsys.CommandManager/RejectKeys"
+	CommandManager_DeleteKeys_FullMethodName               = "/tailsys.CommandManager/DeleteKeys"
+	CommandManager_GetInventory_FullMethodName             = "/tailsys.CommandManager/GetInventory"
+	CommandManager_DiscoverNodes_FullMethodName            = "/tailsys.CommandManager/DiscoverNodes"
+	CommandManager_GetNodeStatus_FullMethodName            = "/tailsys.CommandManager/GetNodeStatus"
+	CommandManager_ApplyState_FullMethodName               = "/tailsys.CommandManager/ApplyState"
+	CommandManager_SendFile_FullMethodName                 = "/tailsys.CommandManager/SendFile"
+	CommandManager_FetchFile_FullMethodName                = "/tailsys.CommandManager/FetchFile"
+	CommandManager_Shell_FullMethodName                    = "/tailsys.CommandManager/Shell"
+	CommandManager_ListShellSessions_FullMethodName        = "/tailsys.CommandManager/ListShellSessions"
+	CommandManager_GetShellSession_FullMethodName          = "/tailsys.CommandManager/GetShellSession"
 )
 
 // CommandManagerClient is the client API for CommandManager service.
@@ -121,6 +204,24 @@ type CommandManagerClient interface {
 	GetNodes(ctx context.Context, in *NodeQuery, opts ...grpc.CallOption) (*NodeQueryResponse, error)
 	SendCommandToNodes(ctx context.Context, in *CommanderRequest, opts ...grpc.CallOption) (*AggregateResponses, error)
 	SendCommandToNodesStream(ctx context.Context, in *CommanderRequest, opts ...grpc.CallOption) (CommandManager_SendCommandToNodesStreamClient, error)
+	ListJobs(ctx context.Context, in *JobQuery, opts ...grpc.CallOption) (*JobList, error)
+	GetJob(ctx context.Context, in *JobID, opts ...grpc.CallOption) (*Job, error)
+	SubmitJob(ctx context.Context, in *CommanderRequest, opts ...grpc.CallOption) (*JobID, error)
+	WatchJob(ctx context.Context, in *JobID, opts ...grpc.CallOption) (CommandManager_WatchJobClient, error)
+	CancelJob(ctx context.Context, in *JobID, opts ...grpc.CallOption) (*Job, error)
+	ListKeys(ctx context.Context, in *KeyQuery, opts ...grpc.CallOption) (*KeyList, error)
+	AcceptKeys(ctx context.Context, in *KeyQuery, opts ...grpc.CallOption) (*KeyList, error)
+	RejectKeys(ctx context.Context, in *KeyQuery, opts ...grpc.CallOption) (*KeyList, error)
+	DeleteKeys(ctx context.Context, in *KeyQuery, opts ...grpc.CallOption) (*KeyList, error)
+	GetInventory(ctx context.Context, in *InventoryQuery, opts ...grpc.CallOption) (*InventoryList, error)
+	DiscoverNodes(ctx context.Context, in *DiscoveryQuery, opts ...grpc.CallOption) (*DiscoveryList, error)
+	GetNodeStatus(ctx context.Context, in *NodeStatusQuery, opts ...grpc.CallOption) (*NodeStatusList, error)
+	ApplyState(ctx context.Context, in *StateApplyRequest, opts ...grpc.CallOption) (CommandManager_ApplyStateClient, error)
+	SendFile(ctx context.Context, opts ...grpc.CallOption) (CommandManager_SendFileClient, error)
+	FetchFile(ctx context.Context, in *FileFetchRequest, opts ...grpc.CallOption) (CommandManager_FetchFileClient, error)
+	Shell(ctx context.Context, opts ...grpc.CallOption) (CommandManager_ShellClient, error)
+	ListShellSessions(ctx context.Context, in *ShellSessionQuery, opts ...grpc.CallOption) (*ShellSessionList, error)
+	GetShellSession(ctx context.Context, in *ShellSessionID, opts ...grpc.CallOption) (*ShellSession, error)
 }
 
 type commandManagerClient struct {
@@ -165,7 +266,7 @@ func (c *commandManagerClient) SendCommandToNodesStream(ctx context.Context, in
 }
 
 type CommandManager_SendCommandToNodesStreamClient interface {
-	Recv() (*CommandResponse, error)
+	Recv() (*CommandStreamResponse, error)
 	grpc.ClientStream
 }
 
@@ -173,14 +274,289 @@ type commandManagerSendCommandToNodesStreamClient struct {
 	grpc.ClientStream
 }
 
-func (x *commandManagerSendCommandToNodesStreamClient) Recv() (*CommandResponse, error) {
-	m := new(CommandResponse)
+func (x *commandManagerSendCommandToNodesStreamClient) Recv() (*CommandStreamResponse, error) {
+	m := new(CommandStreamResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *commandManagerClient) ListJobs(ctx context.Context, in *JobQuery, opts ...grpc.CallOption) (*JobList, error) {
+	out := new(JobList)
+	err := c.cc.Invoke(ctx, CommandManager_ListJobs_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commandManagerClient) GetJob(ctx context.Context, in *JobID, opts ...grpc.CallOption) (*Job, error) {
+	out := new(Job)
+	err := c.cc.Invoke(ctx, CommandManager_GetJob_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commandManagerClient) SubmitJob(ctx context.Context, in *CommanderRequest, opts ...grpc.CallOption) (*JobID, error) {
+	out := new(JobID)
+	err := c.cc.Invoke(ctx, CommandManager_SubmitJob_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commandManagerClient) WatchJob(ctx context.Context, in *JobID, opts ...grpc.CallOption) (CommandManager_WatchJobClient, error) {
+	stream, err := c.cc.NewStream(ctx, &CommandManager_ServiceDesc.Streams[1], CommandManager_WatchJob_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &commandManagerWatchJobClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type CommandManager_WatchJobClient interface {
+	Recv() (*JobEvent, error)
+	grpc.ClientStream
+}
+
+type commandManagerWatchJobClient struct {
+	grpc.ClientStream
+}
+
+func (x *commandManagerWatchJobClient) Recv() (*JobEvent, error) {
+	m := new(JobEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *commandManagerClient) CancelJob(ctx context.Context, in *JobID, opts ...grpc.CallOption) (*Job, error) {
+	out := new(Job)
+	err := c.cc.Invoke(ctx, CommandManager_CancelJob_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commandManagerClient) ListKeys(ctx context.Context, in *KeyQuery, opts ...grpc.CallOption) (*KeyList, error) {
+	out := new(KeyList)
+	err := c.cc.Invoke(ctx, CommandManager_ListKeys_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commandManagerClient) AcceptKeys(ctx context.Context, in *KeyQuery, opts ...grpc.CallOption) (*KeyList, error) {
+	out := new(KeyList)
+	err := c.cc.Invoke(ctx, CommandManager_AcceptKeys_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commandManagerClient) RejectKeys(ctx context.Context, in *KeyQuery, opts ...grpc.CallOption) (*KeyList, error) {
+	out := new(KeyList)
+	err := c.cc.Invoke(ctx, CommandManager_RejectKeys_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commandManagerClient) DeleteKeys(ctx context.Context, in *KeyQuery, opts ...grpc.CallOption) (*KeyList, error) {
+	out := new(KeyList)
+	err := c.cc.Invoke(ctx, CommandManager_DeleteKeys_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commandManagerClient) GetInventory(ctx context.Context, in *InventoryQuery, opts ...grpc.CallOption) (*InventoryList, error) {
+	out := new(InventoryList)
+	err := c.cc.Invoke(ctx, CommandManager_GetInventory_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commandManagerClient) DiscoverNodes(ctx context.Context, in *DiscoveryQuery, opts ...grpc.CallOption) (*DiscoveryList, error) {
+	out := new(DiscoveryList)
+	err := c.cc.Invoke(ctx, CommandManager_DiscoverNodes_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commandManagerClient) GetNodeStatus(ctx context.Context, in *NodeStatusQuery, opts ...grpc.CallOption) (*NodeStatusList, error) {
+	out := new(NodeStatusList)
+	err := c.cc.Invoke(ctx, CommandManager_GetNodeStatus_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commandManagerClient) ApplyState(ctx context.Context, in *StateApplyRequest, opts ...grpc.CallOption) (CommandManager_ApplyStateClient, error) {
+	stream, err := c.cc.NewStream(ctx, &CommandManager_ServiceDesc.Streams[2], CommandManager_ApplyState_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &commandManagerApplyStateClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type CommandManager_ApplyStateClient interface {
+	Recv() (*StateResponse, error)
+	grpc.ClientStream
+}
+
+type commandManagerApplyStateClient struct {
+	grpc.ClientStream
+}
+
+func (x *commandManagerApplyStateClient) Recv() (*StateResponse, error) {
+	m := new(StateResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *commandManagerClient) SendFile(ctx context.Context, opts ...grpc.CallOption) (CommandManager_SendFileClient, error) {
+	stream, err := c.cc.NewStream(ctx, &CommandManager_ServiceDesc.Streams[3], CommandManager_SendFile_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &commandManagerSendFileClient{stream}
+	return x, nil
+}
+
+type CommandManager_SendFileClient interface {
+	Send(*FileSend) error
+	Recv() (*FileUploadResponse, error)
+	grpc.ClientStream
+}
+
+type commandManagerSendFileClient struct {
+	grpc.ClientStream
+}
+
+func (x *commandManagerSendFileClient) Send(m *FileSend) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *commandManagerSendFileClient) Recv() (*FileUploadResponse, error) {
+	m := new(FileUploadResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *commandManagerClient) FetchFile(ctx context.Context, in *FileFetchRequest, opts ...grpc.CallOption) (CommandManager_FetchFileClient, error) {
+	stream, err := c.cc.NewStream(ctx, &CommandManager_ServiceDesc.Streams[4], CommandManager_FetchFile_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &commandManagerFetchFileClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type CommandManager_FetchFileClient interface {
+	Recv() (*FileFetchResponse, error)
+	grpc.ClientStream
+}
+
+type commandManagerFetchFileClient struct {
+	grpc.ClientStream
+}
+
+func (x *commandManagerFetchFileClient) Recv() (*FileFetchResponse, error) {
+	m := new(FileFetchResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *commandManagerClient) Shell(ctx context.Context, opts ...grpc.CallOption) (CommandManager_ShellClient, error) {
+	stream, err := c.cc.NewStream(ctx, &CommandManager_ServiceDesc.Streams[5], CommandManager_Shell_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &commandManagerShellClient{stream}
+	return x, nil
+}
+
+type CommandManager_ShellClient interface {
+	Send(*ShellInput) error
+	Recv() (*ShellOutput, error)
+	grpc.ClientStream
+}
+
+type commandManagerShellClient struct {
+	grpc.ClientStream
+}
+
+func (x *commandManagerShellClient) Send(m *ShellInput) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *commandManagerShellClient) Recv() (*ShellOutput, error) {
+	m := new(ShellOutput)
 	if err := x.ClientStream.RecvMsg(m); err != nil {
 		return nil, err
 	}
 	return m, nil
 }
 
+func (c *commandManagerClient) ListShellSessions(ctx context.Context, in *ShellSessionQuery, opts ...grpc.CallOption) (*ShellSessionList, error) {
+	out := new(ShellSessionList)
+	err := c.cc.Invoke(ctx, CommandManager_ListShellSessions_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commandManagerClient) GetShellSession(ctx context.Context, in *ShellSessionID, opts ...grpc.CallOption) (*ShellSession, error) {
+	out := new(ShellSession)
+	err := c.cc.Invoke(ctx, CommandManager_GetShellSession_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // CommandManagerServer is the server API for CommandManager service.
 // All implementations must embed UnimplementedCommandManagerServer
 // for forward compatibility
@@ -188,6 +564,24 @@ type CommandManagerServer interface {
 	GetNodes(context.Context, *NodeQuery) (*NodeQueryResponse, error)
 	SendCommandToNodes(context.Context, *CommanderRequest) (*AggregateResponses, error)
 	SendCommandToNodesStream(*CommanderRequest, CommandManager_SendCommandToNodesStreamServer) error
+	ListJobs(context.Context, *JobQuery) (*JobList, error)
+	GetJob(context.Context, *JobID) (*Job, error)
+	SubmitJob(context.Context, *CommanderRequest) (*JobID, error)
+	WatchJob(*JobID, CommandManager_WatchJobServer) error
+	CancelJob(context.Context, *JobID) (*Job, error)
+	ListKeys(context.Context, *KeyQuery) (*KeyList, error)
+	AcceptKeys(context.Context, *KeyQuery) (*KeyList, error)
+	RejectKeys(context.Context, *KeyQuery) (*KeyList, error)
+	DeleteKeys(context.Context, *KeyQuery) (*KeyList, error)
+	GetInventory(context.Context, *InventoryQuery) (*InventoryList, error)
+	DiscoverNodes(context.Context, *DiscoveryQuery) (*DiscoveryList, error)
+	GetNodeStatus(context.Context, *NodeStatusQuery) (*NodeStatusList, error)
+	ApplyState(*StateApplyRequest, CommandManager_ApplyStateServer) error
+	SendFile(CommandManager_SendFileServer) error
+	FetchFile(*FileFetchRequest, CommandManager_FetchFileServer) error
+	Shell(CommandManager_ShellServer) error
+	ListShellSessions(context.Context, *ShellSessionQuery) (*ShellSessionList, error)
+	GetShellSession(context.Context, *ShellSessionID) (*ShellSession, error)
 	mustEmbedUnimplementedCommandManagerServer()
 }
 
@@ -204,6 +598,60 @@ func (UnimplementedCommandManagerServer) SendCommandToNodes(context.Context, *Co
 func (UnimplementedCommandManagerServer) SendCommandToNodesStream(*CommanderRequest, CommandManager_SendCommandToNodesStreamServer) error {
 	return status.Errorf(codes.Unimplemented, "method SendCommandToNodesStream not implemented")
 }
+func (UnimplementedCommandManagerServer) ListJobs(context.Context, *JobQuery) (*JobList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListJobs not implemented")
+}
+func (UnimplementedCommandManagerServer) GetJob(context.Context, *JobID) (*Job, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetJob not implemented")
+}
+func (UnimplementedCommandManagerServer) SubmitJob(context.Context, *CommanderRequest) (*JobID, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitJob not implemented")
+}
+func (UnimplementedCommandManagerServer) WatchJob(*JobID, CommandManager_WatchJobServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchJob not implemented")
+}
+func (UnimplementedCommandManagerServer) CancelJob(context.Context, *JobID) (*Job, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelJob not implemented")
+}
+func (UnimplementedCommandManagerServer) ListKeys(context.Context, *KeyQuery) (*KeyList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListKeys not implemented")
+}
+func (UnimplementedCommandManagerServer) AcceptKeys(context.Context, *KeyQuery) (*KeyList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AcceptKeys not implemented")
+}
+func (UnimplementedCommandManagerServer) RejectKeys(context.Context, *KeyQuery) (*KeyList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RejectKeys not implemented")
+}
+func (UnimplementedCommandManagerServer) DeleteKeys(context.Context, *KeyQuery) (*KeyList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteKeys not implemented")
+}
+func (UnimplementedCommandManagerServer) GetInventory(context.Context, *InventoryQuery) (*InventoryList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetInventory not implemented")
+}
+func (UnimplementedCommandManagerServer) DiscoverNodes(context.Context, *DiscoveryQuery) (*DiscoveryList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DiscoverNodes not implemented")
+}
+func (UnimplementedCommandManagerServer) GetNodeStatus(context.Context, *NodeStatusQuery) (*NodeStatusList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetNodeStatus not implemented")
+}
+func (UnimplementedCommandManagerServer) ApplyState(*StateApplyRequest, CommandManager_ApplyStateServer) error {
+	return status.Errorf(codes.Unimplemented, "method ApplyState not implemented")
+}
+func (UnimplementedCommandManagerServer) SendFile(CommandManager_SendFileServer) error {
+	return status.Errorf(codes.Unimplemented, "method SendFile not implemented")
+}
+func (UnimplementedCommandManagerServer) FetchFile(*FileFetchRequest, CommandManager_FetchFileServer) error {
+	return status.Errorf(codes.Unimplemented, "method FetchFile not implemented")
+}
+func (UnimplementedCommandManagerServer) Shell(CommandManager_ShellServer) error {
+	return status.Errorf(codes.Unimplemented, "method Shell not implemented")
+}
+func (UnimplementedCommandManagerServer) ListShellSessions(context.Context, *ShellSessionQuery) (*ShellSessionList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListShellSessions not implemented")
+}
+func (UnimplementedCommandManagerServer) GetShellSession(context.Context, *ShellSessionID) (*ShellSession, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetShellSession not implemented")
+}
 func (UnimplementedCommandManagerServer) mustEmbedUnimplementedCommandManagerServer() {}
 
 // UnsafeCommandManagerServer may be embedded to opt out of forward compatibility for this service.
@@ -262,7 +710,7 @@ func _CommandManager_SendCommandToNodesStream_Handler(srv interface{}, stream gr
 }
 
 type CommandManager_SendCommandToNodesStreamServer interface {
-	Send(*CommandResponse) error
+	Send(*CommandStreamResponse) error
 	grpc.ServerStream
 }
 
@@ -270,14 +718,363 @@ type commandManagerSendCommandToNodesStreamServer struct {
 	grpc.ServerStream
 }
 
-func (x *commandManagerSendCommandToNodesStreamServer) Send(m *CommandResponse) error {
+func (x *commandManagerSendCommandToNodesStreamServer) Send(m *CommandStreamResponse) error {
 	return x.ServerStream.SendMsg(m)
 }
 
-// CommandManager_ServiceDesc is the grpc.ServiceDesc for CommandManager service.
-// It's only intended for direct use with grpc.RegisterService,
-// and not to be introspected or modified (even as a copy)
-var CommandManager_ServiceDesc = grpc.ServiceDesc{
+func _CommandManager_ListJobs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JobQuery)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommandManagerServer).ListJobs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CommandManager_ListJobs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommandManagerServer).ListJobs(ctx, req.(*JobQuery))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CommandManager_GetJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JobID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommandManagerServer).GetJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CommandManager_GetJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommandManagerServer).GetJob(ctx, req.(*JobID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CommandManager_SubmitJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CommanderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommandManagerServer).SubmitJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CommandManager_SubmitJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommandManagerServer).SubmitJob(ctx, req.(*CommanderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CommandManager_WatchJob_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(JobID)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CommandManagerServer).WatchJob(m, &commandManagerWatchJobServer{stream})
+}
+
+type CommandManager_WatchJobServer interface {
+	Send(*JobEvent) error
+	grpc.ServerStream
+}
+
+type commandManagerWatchJobServer struct {
+	grpc.ServerStream
+}
+
+func (x *commandManagerWatchJobServer) Send(m *JobEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _CommandManager_CancelJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JobID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommandManagerServer).CancelJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CommandManager_CancelJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommandManagerServer).CancelJob(ctx, req.(*JobID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CommandManager_ListKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KeyQuery)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommandManagerServer).ListKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CommandManager_ListKeys_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommandManagerServer).ListKeys(ctx, req.(*KeyQuery))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CommandManager_AcceptKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KeyQuery)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommandManagerServer).AcceptKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CommandManager_AcceptKeys_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommandManagerServer).AcceptKeys(ctx, req.(*KeyQuery))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CommandManager_RejectKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KeyQuery)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommandManagerServer).RejectKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CommandManager_RejectKeys_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommandManagerServer).RejectKeys(ctx, req.(*KeyQuery))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CommandManager_DeleteKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KeyQuery)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommandManagerServer).DeleteKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CommandManager_DeleteKeys_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommandManagerServer).DeleteKeys(ctx, req.(*KeyQuery))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CommandManager_GetInventory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InventoryQuery)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommandManagerServer).GetInventory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CommandManager_GetInventory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommandManagerServer).GetInventory(ctx, req.(*InventoryQuery))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CommandManager_DiscoverNodes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DiscoveryQuery)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommandManagerServer).DiscoverNodes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CommandManager_DiscoverNodes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommandManagerServer).DiscoverNodes(ctx, req.(*DiscoveryQuery))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CommandManager_GetNodeStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NodeStatusQuery)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommandManagerServer).GetNodeStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CommandManager_GetNodeStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommandManagerServer).GetNodeStatus(ctx, req.(*NodeStatusQuery))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CommandManager_ApplyState_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StateApplyRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CommandManagerServer).ApplyState(m, &commandManagerApplyStateServer{stream})
+}
+
+type CommandManager_ApplyStateServer interface {
+	Send(*StateResponse) error
+	grpc.ServerStream
+}
+
+type commandManagerApplyStateServer struct {
+	grpc.ServerStream
+}
+
+func (x *commandManagerApplyStateServer) Send(m *StateResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _CommandManager_SendFile_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(CommandManagerServer).SendFile(&commandManagerSendFileServer{stream})
+}
+
+type CommandManager_SendFileServer interface {
+	Send(*FileUploadResponse) error
+	Recv() (*FileSend, error)
+	grpc.ServerStream
+}
+
+type commandManagerSendFileServer struct {
+	grpc.ServerStream
+}
+
+func (x *commandManagerSendFileServer) Send(m *FileUploadResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *commandManagerSendFileServer) Recv() (*FileSend, error) {
+	m := new(FileSend)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _CommandManager_FetchFile_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(FileFetchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CommandManagerServer).FetchFile(m, &commandManagerFetchFileServer{stream})
+}
+
+type CommandManager_FetchFileServer interface {
+	Send(*FileFetchResponse) error
+	grpc.ServerStream
+}
+
+type commandManagerFetchFileServer struct {
+	grpc.ServerStream
+}
+
+func (x *commandManagerFetchFileServer) Send(m *FileFetchResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _CommandManager_Shell_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(CommandManagerServer).Shell(&commandManagerShellServer{stream})
+}
+
+type CommandManager_ShellServer interface {
+	Send(*ShellOutput) error
+	Recv() (*ShellInput, error)
+	grpc.ServerStream
+}
+
+type commandManagerShellServer struct {
+	grpc.ServerStream
+}
+
+func (x *commandManagerShellServer) Send(m *ShellOutput) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *commandManagerShellServer) Recv() (*ShellInput, error) {
+	m := new(ShellInput)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _CommandManager_ListShellSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShellSessionQuery)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommandManagerServer).ListShellSessions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CommandManager_ListShellSessions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommandManagerServer).ListShellSessions(ctx, req.(*ShellSessionQuery))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CommandManager_GetShellSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShellSessionID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommandManagerServer).GetShellSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CommandManager_GetShellSession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommandManagerServer).GetShellSession(ctx, req.(*ShellSessionID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// CommandManager_ServiceDesc is the grpc.ServiceDesc for CommandManager service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var CommandManager_ServiceDesc = grpc.ServiceDesc{
 	ServiceName: "tailsys.CommandManager",
 	HandlerType: (*CommandManagerServer)(nil),
 	Methods: []grpc.MethodDesc{
@@ -289,6 +1086,58 @@ var CommandManager_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SendCommandToNodes",
 			Handler:    _CommandManager_SendCommandToNodes_Handler,
 		},
+		{
+			MethodName: "ListJobs",
+			Handler:    _CommandManager_ListJobs_Handler,
+		},
+		{
+			MethodName: "GetJob",
+			Handler:    _CommandManager_GetJob_Handler,
+		},
+		{
+			MethodName: "SubmitJob",
+			Handler:    _CommandManager_SubmitJob_Handler,
+		},
+		{
+			MethodName: "CancelJob",
+			Handler:    _CommandManager_CancelJob_Handler,
+		},
+		{
+			MethodName: "ListKeys",
+			Handler:    _CommandManager_ListKeys_Handler,
+		},
+		{
+			MethodName: "AcceptKeys",
+			Handler:    _CommandManager_AcceptKeys_Handler,
+		},
+		{
+			MethodName: "RejectKeys",
+			Handler:    _CommandManager_RejectKeys_Handler,
+		},
+		{
+			MethodName: "DeleteKeys",
+			Handler:    _CommandManager_DeleteKeys_Handler,
+		},
+		{
+			MethodName: "GetInventory",
+			Handler:    _CommandManager_GetInventory_Handler,
+		},
+		{
+			MethodName: "DiscoverNodes",
+			Handler:    _CommandManager_DiscoverNodes_Handler,
+		},
+		{
+			MethodName: "GetNodeStatus",
+			Handler:    _CommandManager_GetNodeStatus_Handler,
+		},
+		{
+			MethodName: "ListShellSessions",
+			Handler:    _CommandManager_ListShellSessions_Handler,
+		},
+		{
+			MethodName: "GetShellSession",
+			Handler:    _CommandManager_GetShellSession_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -296,6 +1145,33 @@ var CommandManager_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _CommandManager_SendCommandToNodesStream_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "WatchJob",
+			Handler:       _CommandManager_WatchJob_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ApplyState",
+			Handler:       _CommandManager_ApplyState_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SendFile",
+			Handler:       _CommandManager_SendFile_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "FetchFile",
+			Handler:       _CommandManager_FetchFile_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Shell",
+			Handler:       _CommandManager_Shell_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 	},
 	Metadata: "command.proto",
 }
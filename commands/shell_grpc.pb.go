@@ -0,0 +1,141 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v3.12.4
+// source: shell.proto
+
+package commands
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	Shell_Session_FullMethodName = "/tailsys.Shell/Session"
+)
+
+// ShellClient is the client API for Shell service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ShellClient interface {
+	Session(ctx context.Context, opts ...grpc.CallOption) (Shell_SessionClient, error)
+}
+
+type shellClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewShellClient(cc grpc.ClientConnInterface) ShellClient {
+	return &shellClient{cc}
+}
+
+func (c *shellClient) Session(ctx context.Context, opts ...grpc.CallOption) (Shell_SessionClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Shell_ServiceDesc.Streams[0], Shell_Session_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &shellSessionClient{stream}
+	return x, nil
+}
+
+type Shell_SessionClient interface {
+	Send(*ShellInput) error
+	Recv() (*ShellOutput, error)
+	grpc.ClientStream
+}
+
+type shellSessionClient struct {
+	grpc.ClientStream
+}
+
+func (x *shellSessionClient) Send(m *ShellInput) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *shellSessionClient) Recv() (*ShellOutput, error) {
+	m := new(ShellOutput)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ShellServer is the server API for Shell service.
+// All implementations must embed UnimplementedShellServer
+// for forward compatibility
+type ShellServer interface {
+	Session(Shell_SessionServer) error
+	mustEmbedUnimplementedShellServer()
+}
+
+// UnimplementedShellServer must be embedded to have forward compatible implementations.
+type UnimplementedShellServer struct {
+}
+
+func (UnimplementedShellServer) Session(Shell_SessionServer) error {
+	return status.Errorf(codes.Unimplemented, "method Session not implemented")
+}
+func (UnimplementedShellServer) mustEmbedUnimplementedShellServer() {}
+
+// UnsafeShellServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ShellServer will
+// result in compilation errors.
+type UnsafeShellServer interface {
+	mustEmbedUnimplementedShellServer()
+}
+
+func RegisterShellServer(s grpc.ServiceRegistrar, srv ShellServer) {
+	s.RegisterService(&Shell_ServiceDesc, srv)
+}
+
+func _Shell_Session_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(ShellServer).Session(&shellSessionServer{stream})
+}
+
+type Shell_SessionServer interface {
+	Send(*ShellOutput) error
+	Recv() (*ShellInput, error)
+	grpc.ServerStream
+}
+
+type shellSessionServer struct {
+	grpc.ServerStream
+}
+
+func (x *shellSessionServer) Send(m *ShellOutput) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *shellSessionServer) Recv() (*ShellInput, error) {
+	m := new(ShellInput)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Shell_ServiceDesc is the grpc.ServiceDesc for Shell service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Shell_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "tailsys.Shell",
+	HandlerType: (*ShellServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Session",
+			Handler:       _Shell_Session_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "shell.proto",
+}
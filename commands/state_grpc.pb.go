@@ -0,0 +1,109 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v3.12.4
+// source: state.proto
+
+package commands
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	StateRunner_ApplyState_FullMethodName = "/tailsys.StateRunner/ApplyState"
+)
+
+// StateRunnerClient is the client API for StateRunner service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type StateRunnerClient interface {
+	ApplyState(ctx context.Context, in *StateRequest, opts ...grpc.CallOption) (*StateResponse, error)
+}
+
+type stateRunnerClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewStateRunnerClient(cc grpc.ClientConnInterface) StateRunnerClient {
+	return &stateRunnerClient{cc}
+}
+
+func (c *stateRunnerClient) ApplyState(ctx context.Context, in *StateRequest, opts ...grpc.CallOption) (*StateResponse, error) {
+	out := new(StateResponse)
+	err := c.cc.Invoke(ctx, StateRunner_ApplyState_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// StateRunnerServer is the server API for StateRunner service.
+// All implementations must embed UnimplementedStateRunnerServer
+// for forward compatibility
+type StateRunnerServer interface {
+	ApplyState(context.Context, *StateRequest) (*StateResponse, error)
+	mustEmbedUnimplementedStateRunnerServer()
+}
+
+// UnimplementedStateRunnerServer must be embedded to have forward compatible implementations.
+type UnimplementedStateRunnerServer struct {
+}
+
+func (UnimplementedStateRunnerServer) ApplyState(context.Context, *StateRequest) (*StateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApplyState not implemented")
+}
+func (UnimplementedStateRunnerServer) mustEmbedUnimplementedStateRunnerServer() {}
+
+// UnsafeStateRunnerServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to StateRunnerServer will
+// result in compilation errors.
+type UnsafeStateRunnerServer interface {
+	mustEmbedUnimplementedStateRunnerServer()
+}
+
+func RegisterStateRunnerServer(s grpc.ServiceRegistrar, srv StateRunnerServer) {
+	s.RegisterService(&StateRunner_ServiceDesc, srv)
+}
+
+func _StateRunner_ApplyState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StateRunnerServer).ApplyState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StateRunner_ApplyState_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StateRunnerServer).ApplyState(ctx, req.(*StateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// StateRunner_ServiceDesc is the grpc.ServiceDesc for StateRunner service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var StateRunner_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "tailsys.StateRunner",
+	HandlerType: (*StateRunnerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ApplyState",
+			Handler:    _StateRunner_ApplyState_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "state.proto",
+}
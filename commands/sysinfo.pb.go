@@ -10,6 +10,7 @@ import (
 	timestamp "github.com/golang/protobuf/ptypes/timestamp"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
 	reflect "reflect"
 	sync "sync"
 )
@@ -127,11 +128,12 @@ type SysInfo struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Hostname string               `protobuf:"bytes,1,opt,name=hostname,proto3" json:"hostname,omitempty"`
-	Type     OSType               `protobuf:"varint,2,opt,name=type,proto3,enum=tailsys.OSType" json:"type,omitempty"`
-	Ip       string               `protobuf:"bytes,3,opt,name=ip,proto3" json:"ip,omitempty"`
-	LastSeen *timestamp.Timestamp `protobuf:"bytes,4,opt,name=lastSeen,proto3" json:"lastSeen,omitempty"`
-	Port     string               `protobuf:"bytes,5,opt,name=port,proto3" json:"port,omitempty"`
+	Hostname  string               `protobuf:"bytes,1,opt,name=hostname,proto3" json:"hostname,omitempty"`
+	Type      OSType               `protobuf:"varint,2,opt,name=type,proto3,enum=tailsys.OSType" json:"type,omitempty"`
+	Ip        string               `protobuf:"bytes,3,opt,name=ip,proto3" json:"ip,omitempty"`
+	LastSeen  *timestamp.Timestamp `protobuf:"bytes,4,opt,name=lastSeen,proto3" json:"lastSeen,omitempty"`
+	Port      string               `protobuf:"bytes,5,opt,name=port,proto3" json:"port,omitempty"`
+	Inventory *Inventory           `protobuf:"bytes,6,opt,name=inventory,proto3" json:"inventory,omitempty"`
 }
 
 func (x *SysInfo) Reset() {
@@ -201,6 +203,347 @@ func (x *SysInfo) GetPort() string {
 	return ""
 }
 
+func (x *SysInfo) GetInventory() *Inventory {
+	if x != nil {
+		return x.Inventory
+	}
+	return nil
+}
+
+type Disk struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Device     string `protobuf:"bytes,1,opt,name=device,proto3" json:"device,omitempty"`
+	Mountpoint string `protobuf:"bytes,2,opt,name=mountpoint,proto3" json:"mountpoint,omitempty"`
+	Fstype     string `protobuf:"bytes,3,opt,name=fstype,proto3" json:"fstype,omitempty"`
+	TotalBytes uint64 `protobuf:"varint,4,opt,name=totalBytes,proto3" json:"totalBytes,omitempty"`
+	FreeBytes  uint64 `protobuf:"varint,5,opt,name=freeBytes,proto3" json:"freeBytes,omitempty"`
+}
+
+func (x *Disk) Reset() {
+	*x = Disk{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sysinfo_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Disk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Disk) ProtoMessage() {}
+
+func (x *Disk) ProtoReflect() protoreflect.Message {
+	mi := &file_sysinfo_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Disk.ProtoReflect.Descriptor instead.
+func (*Disk) Descriptor() ([]byte, []int) {
+	return file_sysinfo_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Disk) GetDevice() string {
+	if x != nil {
+		return x.Device
+	}
+	return ""
+}
+
+func (x *Disk) GetMountpoint() string {
+	if x != nil {
+		return x.Mountpoint
+	}
+	return ""
+}
+
+func (x *Disk) GetFstype() string {
+	if x != nil {
+		return x.Fstype
+	}
+	return ""
+}
+
+func (x *Disk) GetTotalBytes() uint64 {
+	if x != nil {
+		return x.TotalBytes
+	}
+	return 0
+}
+
+func (x *Disk) GetFreeBytes() uint64 {
+	if x != nil {
+		return x.FreeBytes
+	}
+	return 0
+}
+
+type NetworkInterface struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name      string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Mac       string   `protobuf:"bytes,2,opt,name=mac,proto3" json:"mac,omitempty"`
+	Addresses []string `protobuf:"bytes,3,rep,name=addresses,proto3" json:"addresses,omitempty"`
+	Mtu       int32    `protobuf:"varint,4,opt,name=mtu,proto3" json:"mtu,omitempty"`
+	Up        bool     `protobuf:"varint,5,opt,name=up,proto3" json:"up,omitempty"`
+}
+
+func (x *NetworkInterface) Reset() {
+	*x = NetworkInterface{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sysinfo_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NetworkInterface) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NetworkInterface) ProtoMessage() {}
+
+func (x *NetworkInterface) ProtoReflect() protoreflect.Message {
+	mi := &file_sysinfo_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NetworkInterface.ProtoReflect.Descriptor instead.
+func (*NetworkInterface) Descriptor() ([]byte, []int) {
+	return file_sysinfo_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *NetworkInterface) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *NetworkInterface) GetMac() string {
+	if x != nil {
+		return x.Mac
+	}
+	return ""
+}
+
+func (x *NetworkInterface) GetAddresses() []string {
+	if x != nil {
+		return x.Addresses
+	}
+	return nil
+}
+
+func (x *NetworkInterface) GetMtu() int32 {
+	if x != nil {
+		return x.Mtu
+	}
+	return 0
+}
+
+func (x *NetworkInterface) GetUp() bool {
+	if x != nil {
+		return x.Up
+	}
+	return false
+}
+
+// Inventory is what a node reports about itself, collected from /proc, /sys and /etc/os-release on linux
+type Inventory struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Os                   string               `protobuf:"bytes,1,opt,name=os,proto3" json:"os,omitempty"`
+	Distro               string               `protobuf:"bytes,2,opt,name=distro,proto3" json:"distro,omitempty"`
+	DistroVersion        string               `protobuf:"bytes,3,opt,name=distroVersion,proto3" json:"distroVersion,omitempty"`
+	DistroName           string               `protobuf:"bytes,4,opt,name=distroName,proto3" json:"distroName,omitempty"`
+	Kernel               string               `protobuf:"bytes,5,opt,name=kernel,proto3" json:"kernel,omitempty"`
+	Arch                 string               `protobuf:"bytes,6,opt,name=arch,proto3" json:"arch,omitempty"`
+	CpuCount             int32                `protobuf:"varint,7,opt,name=cpuCount,proto3" json:"cpuCount,omitempty"`
+	CpuModel             string               `protobuf:"bytes,8,opt,name=cpuModel,proto3" json:"cpuModel,omitempty"`
+	MemoryTotalBytes     uint64               `protobuf:"varint,9,opt,name=memoryTotalBytes,proto3" json:"memoryTotalBytes,omitempty"`
+	MemoryAvailableBytes uint64               `protobuf:"varint,10,opt,name=memoryAvailableBytes,proto3" json:"memoryAvailableBytes,omitempty"`
+	Disks                []*Disk              `protobuf:"bytes,11,rep,name=disks,proto3" json:"disks,omitempty"`
+	Interfaces           []*NetworkInterface  `protobuf:"bytes,12,rep,name=interfaces,proto3" json:"interfaces,omitempty"`
+	TailscaleIps         []string             `protobuf:"bytes,13,rep,name=tailscaleIps,proto3" json:"tailscaleIps,omitempty"`
+	TailscaleTags        []string             `protobuf:"bytes,14,rep,name=tailscaleTags,proto3" json:"tailscaleTags,omitempty"`
+	UptimeSeconds        int64                `protobuf:"varint,15,opt,name=uptimeSeconds,proto3" json:"uptimeSeconds,omitempty"`
+	TailsysVersion       string               `protobuf:"bytes,16,opt,name=tailsysVersion,proto3" json:"tailsysVersion,omitempty"`
+	Collected            *timestamp.Timestamp `protobuf:"bytes,17,opt,name=collected,proto3" json:"collected,omitempty"`
+}
+
+func (x *Inventory) Reset() {
+	*x = Inventory{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sysinfo_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Inventory) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Inventory) ProtoMessage() {}
+
+func (x *Inventory) ProtoReflect() protoreflect.Message {
+	mi := &file_sysinfo_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Inventory.ProtoReflect.Descriptor instead.
+func (*Inventory) Descriptor() ([]byte, []int) {
+	return file_sysinfo_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Inventory) GetOs() string {
+	if x != nil {
+		return x.Os
+	}
+	return ""
+}
+
+func (x *Inventory) GetDistro() string {
+	if x != nil {
+		return x.Distro
+	}
+	return ""
+}
+
+func (x *Inventory) GetDistroVersion() string {
+	if x != nil {
+		return x.DistroVersion
+	}
+	return ""
+}
+
+func (x *Inventory) GetDistroName() string {
+	if x != nil {
+		return x.DistroName
+	}
+	return ""
+}
+
+func (x *Inventory) GetKernel() string {
+	if x != nil {
+		return x.Kernel
+	}
+	return ""
+}
+
+func (x *Inventory) GetArch() string {
+	if x != nil {
+		return x.Arch
+	}
+	return ""
+}
+
+func (x *Inventory) GetCpuCount() int32 {
+	if x != nil {
+		return x.CpuCount
+	}
+	return 0
+}
+
+func (x *Inventory) GetCpuModel() string {
+	if x != nil {
+		return x.CpuModel
+	}
+	return ""
+}
+
+func (x *Inventory) GetMemoryTotalBytes() uint64 {
+	if x != nil {
+		return x.MemoryTotalBytes
+	}
+	return 0
+}
+
+func (x *Inventory) GetMemoryAvailableBytes() uint64 {
+	if x != nil {
+		return x.MemoryAvailableBytes
+	}
+	return 0
+}
+
+func (x *Inventory) GetDisks() []*Disk {
+	if x != nil {
+		return x.Disks
+	}
+	return nil
+}
+
+func (x *Inventory) GetInterfaces() []*NetworkInterface {
+	if x != nil {
+		return x.Interfaces
+	}
+	return nil
+}
+
+func (x *Inventory) GetTailscaleIps() []string {
+	if x != nil {
+		return x.TailscaleIps
+	}
+	return nil
+}
+
+func (x *Inventory) GetTailscaleTags() []string {
+	if x != nil {
+		return x.TailscaleTags
+	}
+	return nil
+}
+
+func (x *Inventory) GetUptimeSeconds() int64 {
+	if x != nil {
+		return x.UptimeSeconds
+	}
+	return 0
+}
+
+func (x *Inventory) GetTailsysVersion() string {
+	if x != nil {
+		return x.TailsysVersion
+	}
+	return ""
+}
+
+func (x *Inventory) GetCollected() *timestamp.Timestamp {
+	if x != nil {
+		return x.Collected
+	}
+	return nil
+}
+
 type Key struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -212,7 +555,7 @@ type Key struct {
 func (x *Key) Reset() {
 	*x = Key{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_sysinfo_proto_msgTypes[1]
+		mi := &file_sysinfo_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -225,7 +568,345 @@ func (x *Key) String() string {
 func (*Key) ProtoMessage() {}
 
 func (x *Key) ProtoReflect() protoreflect.Message {
-	mi := &file_sysinfo_proto_msgTypes[1]
+	mi := &file_sysinfo_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Key.ProtoReflect.Descriptor instead.
+func (*Key) Descriptor() ([]byte, []int) {
+	return file_sysinfo_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Key) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+type NodeRegistrationRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Info       *SysInfo   `protobuf:"bytes,1,opt,name=info,proto3" json:"info,omitempty"`
+	Key        *Key       `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	SystemType SystemType `protobuf:"varint,3,opt,name=systemType,proto3,enum=tailsys.SystemType" json:"systemType,omitempty"`
+	Accepted   bool       `protobuf:"varint,4,opt,name=accepted,proto3" json:"accepted,omitempty"`
+	// private keys no longer leave the node, the csr is signed by the coordinator instead
+	//
+	// Deprecated: Marked as deprecated in sysinfo.proto.
+	Tlskey string `protobuf:"bytes,5,opt,name=tlskey,proto3" json:"tlskey,omitempty"`
+	// Deprecated: Marked as deprecated in sysinfo.proto.
+	Tlscert string `protobuf:"bytes,6,opt,name=tlscert,proto3" json:"tlscert,omitempty"`
+	Csr     []byte `protobuf:"bytes,7,opt,name=csr,proto3" json:"csr,omitempty"`
+}
+
+func (x *NodeRegistrationRequest) Reset() {
+	*x = NodeRegistrationRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sysinfo_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NodeRegistrationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeRegistrationRequest) ProtoMessage() {}
+
+func (x *NodeRegistrationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sysinfo_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeRegistrationRequest.ProtoReflect.Descriptor instead.
+func (*NodeRegistrationRequest) Descriptor() ([]byte, []int) {
+	return file_sysinfo_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *NodeRegistrationRequest) GetInfo() *SysInfo {
+	if x != nil {
+		return x.Info
+	}
+	return nil
+}
+
+func (x *NodeRegistrationRequest) GetKey() *Key {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+func (x *NodeRegistrationRequest) GetSystemType() SystemType {
+	if x != nil {
+		return x.SystemType
+	}
+	return SystemType_COORDINATION_SERVER
+}
+
+func (x *NodeRegistrationRequest) GetAccepted() bool {
+	if x != nil {
+		return x.Accepted
+	}
+	return false
+}
+
+// Deprecated: Marked as deprecated in sysinfo.proto.
+func (x *NodeRegistrationRequest) GetTlskey() string {
+	if x != nil {
+		return x.Tlskey
+	}
+	return ""
+}
+
+// Deprecated: Marked as deprecated in sysinfo.proto.
+func (x *NodeRegistrationRequest) GetTlscert() string {
+	if x != nil {
+		return x.Tlscert
+	}
+	return ""
+}
+
+func (x *NodeRegistrationRequest) GetCsr() []byte {
+	if x != nil {
+		return x.Csr
+	}
+	return nil
+}
+
+type Certificate struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Cert string `protobuf:"bytes,1,opt,name=cert,proto3" json:"cert,omitempty"`
+	Ca   string `protobuf:"bytes,2,opt,name=ca,proto3" json:"ca,omitempty"`
+	Crl  []byte `protobuf:"bytes,3,opt,name=crl,proto3" json:"crl,omitempty"`
+}
+
+func (x *Certificate) Reset() {
+	*x = Certificate{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sysinfo_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Certificate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Certificate) ProtoMessage() {}
+
+func (x *Certificate) ProtoReflect() protoreflect.Message {
+	mi := &file_sysinfo_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Certificate.ProtoReflect.Descriptor instead.
+func (*Certificate) Descriptor() ([]byte, []int) {
+	return file_sysinfo_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Certificate) GetCert() string {
+	if x != nil {
+		return x.Cert
+	}
+	return ""
+}
+
+func (x *Certificate) GetCa() string {
+	if x != nil {
+		return x.Ca
+	}
+	return ""
+}
+
+func (x *Certificate) GetCrl() []byte {
+	if x != nil {
+		return x.Crl
+	}
+	return nil
+}
+
+type NodeRegistrationResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Accepted    bool         `protobuf:"varint,1,opt,name=accepted,proto3" json:"accepted,omitempty"`
+	Key         *Key         `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	Hostname    string       `protobuf:"bytes,3,opt,name=hostname,proto3" json:"hostname,omitempty"`
+	Certificate *Certificate `protobuf:"bytes,4,opt,name=certificate,proto3" json:"certificate,omitempty"`
+	// ed25519 public key the coordination server signs command requests with
+	SigningKey []byte `protobuf:"bytes,5,opt,name=signingKey,proto3" json:"signingKey,omitempty"`
+}
+
+func (x *NodeRegistrationResponse) Reset() {
+	*x = NodeRegistrationResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sysinfo_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NodeRegistrationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeRegistrationResponse) ProtoMessage() {}
+
+func (x *NodeRegistrationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_sysinfo_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeRegistrationResponse.ProtoReflect.Descriptor instead.
+func (*NodeRegistrationResponse) Descriptor() ([]byte, []int) {
+	return file_sysinfo_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *NodeRegistrationResponse) GetAccepted() bool {
+	if x != nil {
+		return x.Accepted
+	}
+	return false
+}
+
+func (x *NodeRegistrationResponse) GetKey() *Key {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+func (x *NodeRegistrationResponse) GetHostname() string {
+	if x != nil {
+		return x.Hostname
+	}
+	return ""
+}
+
+func (x *NodeRegistrationResponse) GetCertificate() *Certificate {
+	if x != nil {
+		return x.Certificate
+	}
+	return nil
+}
+
+func (x *NodeRegistrationResponse) GetSigningKey() []byte {
+	if x != nil {
+		return x.SigningKey
+	}
+	return nil
+}
+
+type RenewRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Csr []byte `protobuf:"bytes,1,opt,name=csr,proto3" json:"csr,omitempty"`
+}
+
+func (x *RenewRequest) Reset() {
+	*x = RenewRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sysinfo_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RenewRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenewRequest) ProtoMessage() {}
+
+func (x *RenewRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sysinfo_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenewRequest.ProtoReflect.Descriptor instead.
+func (*RenewRequest) Descriptor() ([]byte, []int) {
+	return file_sysinfo_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *RenewRequest) GetCsr() []byte {
+	if x != nil {
+		return x.Csr
+	}
+	return nil
+}
+
+type PingRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Ping *timestamp.Timestamp `protobuf:"bytes,1,opt,name=ping,proto3" json:"ping,omitempty"`
+}
+
+func (x *PingRequest) Reset() {
+	*x = PingRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sysinfo_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PingRequest) ProtoMessage() {}
+
+func (x *PingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sysinfo_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -236,48 +917,45 @@ func (x *Key) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Key.ProtoReflect.Descriptor instead.
-func (*Key) Descriptor() ([]byte, []int) {
-	return file_sysinfo_proto_rawDescGZIP(), []int{1}
+// Deprecated: Use PingRequest.ProtoReflect.Descriptor instead.
+func (*PingRequest) Descriptor() ([]byte, []int) {
+	return file_sysinfo_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *Key) GetKey() string {
+func (x *PingRequest) GetPing() *timestamp.Timestamp {
 	if x != nil {
-		return x.Key
+		return x.Ping
 	}
-	return ""
+	return nil
 }
 
-type NodeRegistrationRequest struct {
+type PongResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Info       *SysInfo   `protobuf:"bytes,1,opt,name=info,proto3" json:"info,omitempty"`
-	Key        *Key       `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
-	SystemType SystemType `protobuf:"varint,3,opt,name=systemType,proto3,enum=tailsys.SystemType" json:"systemType,omitempty"`
-	Accepted   bool       `protobuf:"varint,4,opt,name=accepted,proto3" json:"accepted,omitempty"`
-	Tlskey     string     `protobuf:"bytes,5,opt,name=tlskey,proto3" json:"tlskey,omitempty"`
-	Tlscert    string     `protobuf:"bytes,6,opt,name=tlscert,proto3" json:"tlscert,omitempty"`
+	Ping           *timestamp.Timestamp `protobuf:"bytes,1,opt,name=ping,proto3" json:"ping,omitempty"`
+	InboundLatency float32              `protobuf:"fixed32,2,opt,name=inboundLatency,proto3" json:"inboundLatency,omitempty"`
+	Key            *Key                 `protobuf:"bytes,3,opt,name=key,proto3" json:"key,omitempty"`
 }
 
-func (x *NodeRegistrationRequest) Reset() {
-	*x = NodeRegistrationRequest{}
+func (x *PongResponse) Reset() {
+	*x = PongResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_sysinfo_proto_msgTypes[2]
+		mi := &file_sysinfo_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *NodeRegistrationRequest) String() string {
+func (x *PongResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*NodeRegistrationRequest) ProtoMessage() {}
+func (*PongResponse) ProtoMessage() {}
 
-func (x *NodeRegistrationRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_sysinfo_proto_msgTypes[2]
+func (x *PongResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_sysinfo_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -288,80 +966,55 @@ func (x *NodeRegistrationRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use NodeRegistrationRequest.ProtoReflect.Descriptor instead.
-func (*NodeRegistrationRequest) Descriptor() ([]byte, []int) {
-	return file_sysinfo_proto_rawDescGZIP(), []int{2}
-}
-
-func (x *NodeRegistrationRequest) GetInfo() *SysInfo {
-	if x != nil {
-		return x.Info
-	}
-	return nil
+// Deprecated: Use PongResponse.ProtoReflect.Descriptor instead.
+func (*PongResponse) Descriptor() ([]byte, []int) {
+	return file_sysinfo_proto_rawDescGZIP(), []int{10}
 }
 
-func (x *NodeRegistrationRequest) GetKey() *Key {
+func (x *PongResponse) GetPing() *timestamp.Timestamp {
 	if x != nil {
-		return x.Key
+		return x.Ping
 	}
 	return nil
 }
 
-func (x *NodeRegistrationRequest) GetSystemType() SystemType {
-	if x != nil {
-		return x.SystemType
-	}
-	return SystemType_COORDINATION_SERVER
-}
-
-func (x *NodeRegistrationRequest) GetAccepted() bool {
-	if x != nil {
-		return x.Accepted
-	}
-	return false
-}
-
-func (x *NodeRegistrationRequest) GetTlskey() string {
+func (x *PongResponse) GetInboundLatency() float32 {
 	if x != nil {
-		return x.Tlskey
+		return x.InboundLatency
 	}
-	return ""
+	return 0
 }
 
-func (x *NodeRegistrationRequest) GetTlscert() string {
+func (x *PongResponse) GetKey() *Key {
 	if x != nil {
-		return x.Tlscert
+		return x.Key
 	}
-	return ""
+	return nil
 }
 
-type NodeRegistrationResponse struct {
+type SysInfoRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
-
-	Accepted bool   `protobuf:"varint,1,opt,name=accepted,proto3" json:"accepted,omitempty"`
-	Key      *Key   `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
-	Hostname string `protobuf:"bytes,3,opt,name=hostname,proto3" json:"hostname,omitempty"`
 }
 
-func (x *NodeRegistrationResponse) Reset() {
-	*x = NodeRegistrationResponse{}
+func (x *SysInfoRequest) Reset() {
+	*x = SysInfoRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_sysinfo_proto_msgTypes[3]
+		mi := &file_sysinfo_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *NodeRegistrationResponse) String() string {
+func (x *SysInfoRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*NodeRegistrationResponse) ProtoMessage() {}
+func (*SysInfoRequest) ProtoMessage() {}
 
-func (x *NodeRegistrationResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_sysinfo_proto_msgTypes[3]
+func (x *SysInfoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sysinfo_proto_msgTypes[11]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -372,57 +1025,38 @@ func (x *NodeRegistrationResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use NodeRegistrationResponse.ProtoReflect.Descriptor instead.
-func (*NodeRegistrationResponse) Descriptor() ([]byte, []int) {
-	return file_sysinfo_proto_rawDescGZIP(), []int{3}
-}
-
-func (x *NodeRegistrationResponse) GetAccepted() bool {
-	if x != nil {
-		return x.Accepted
-	}
-	return false
-}
-
-func (x *NodeRegistrationResponse) GetKey() *Key {
-	if x != nil {
-		return x.Key
-	}
-	return nil
-}
-
-func (x *NodeRegistrationResponse) GetHostname() string {
-	if x != nil {
-		return x.Hostname
-	}
-	return ""
+// Deprecated: Use SysInfoRequest.ProtoReflect.Descriptor instead.
+func (*SysInfoRequest) Descriptor() ([]byte, []int) {
+	return file_sysinfo_proto_rawDescGZIP(), []int{11}
 }
 
-type PingRequest struct {
+type Heartbeat struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Ping *timestamp.Timestamp `protobuf:"bytes,1,opt,name=ping,proto3" json:"ping,omitempty"`
+	Sent *timestamp.Timestamp `protobuf:"bytes,1,opt,name=sent,proto3" json:"sent,omitempty"`
+	// interval is how often the node sends heartbeats, the tunnel is dropped when three in a row are missed
+	Interval *durationpb.Duration `protobuf:"bytes,2,opt,name=interval,proto3" json:"interval,omitempty"`
 }
 
-func (x *PingRequest) Reset() {
-	*x = PingRequest{}
+func (x *Heartbeat) Reset() {
+	*x = Heartbeat{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_sysinfo_proto_msgTypes[4]
+		mi := &file_sysinfo_proto_msgTypes[12]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *PingRequest) String() string {
+func (x *Heartbeat) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PingRequest) ProtoMessage() {}
+func (*Heartbeat) ProtoMessage() {}
 
-func (x *PingRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_sysinfo_proto_msgTypes[4]
+func (x *Heartbeat) ProtoReflect() protoreflect.Message {
+	mi := &file_sysinfo_proto_msgTypes[12]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -433,45 +1067,54 @@ func (x *PingRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PingRequest.ProtoReflect.Descriptor instead.
-func (*PingRequest) Descriptor() ([]byte, []int) {
-	return file_sysinfo_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use Heartbeat.ProtoReflect.Descriptor instead.
+func (*Heartbeat) Descriptor() ([]byte, []int) {
+	return file_sysinfo_proto_rawDescGZIP(), []int{12}
 }
 
-func (x *PingRequest) GetPing() *timestamp.Timestamp {
+func (x *Heartbeat) GetSent() *timestamp.Timestamp {
 	if x != nil {
-		return x.Ping
+		return x.Sent
 	}
 	return nil
 }
 
-type PongResponse struct {
+func (x *Heartbeat) GetInterval() *durationpb.Duration {
+	if x != nil {
+		return x.Interval
+	}
+	return nil
+}
+
+type TunnelFrame struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Ping           *timestamp.Timestamp `protobuf:"bytes,1,opt,name=ping,proto3" json:"ping,omitempty"`
-	InboundLatency float32              `protobuf:"fixed32,2,opt,name=inboundLatency,proto3" json:"inboundLatency,omitempty"`
-	Key            *Key                 `protobuf:"bytes,3,opt,name=key,proto3" json:"key,omitempty"`
+	// Types that are assignable to Frame:
+	//	*TunnelFrame_Data
+	//	*TunnelFrame_Heartbeat
+	//	*TunnelFrame_Inventory
+	Frame isTunnelFrame_Frame `protobuf_oneof:"frame"`
 }
 
-func (x *PongResponse) Reset() {
-	*x = PongResponse{}
+func (x *TunnelFrame) Reset() {
+	*x = TunnelFrame{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_sysinfo_proto_msgTypes[5]
+		mi := &file_sysinfo_proto_msgTypes[13]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *PongResponse) String() string {
+func (x *TunnelFrame) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PongResponse) ProtoMessage() {}
+func (*TunnelFrame) ProtoMessage() {}
 
-func (x *PongResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_sysinfo_proto_msgTypes[5]
+func (x *TunnelFrame) ProtoReflect() protoreflect.Message {
+	mi := &file_sysinfo_proto_msgTypes[13]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -482,39 +1125,73 @@ func (x *PongResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PongResponse.ProtoReflect.Descriptor instead.
-func (*PongResponse) Descriptor() ([]byte, []int) {
-	return file_sysinfo_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use TunnelFrame.ProtoReflect.Descriptor instead.
+func (*TunnelFrame) Descriptor() ([]byte, []int) {
+	return file_sysinfo_proto_rawDescGZIP(), []int{13}
 }
 
-func (x *PongResponse) GetPing() *timestamp.Timestamp {
-	if x != nil {
-		return x.Ping
+func (m *TunnelFrame) GetFrame() isTunnelFrame_Frame {
+	if m != nil {
+		return m.Frame
 	}
 	return nil
 }
 
-func (x *PongResponse) GetInboundLatency() float32 {
-	if x != nil {
-		return x.InboundLatency
+func (x *TunnelFrame) GetData() []byte {
+	if x, ok := x.GetFrame().(*TunnelFrame_Data); ok {
+		return x.Data
 	}
-	return 0
+	return nil
 }
 
-func (x *PongResponse) GetKey() *Key {
-	if x != nil {
-		return x.Key
+func (x *TunnelFrame) GetHeartbeat() *Heartbeat {
+	if x, ok := x.GetFrame().(*TunnelFrame_Heartbeat); ok {
+		return x.Heartbeat
+	}
+	return nil
+}
+
+func (x *TunnelFrame) GetInventory() *Inventory {
+	if x, ok := x.GetFrame().(*TunnelFrame_Inventory); ok {
+		return x.Inventory
 	}
 	return nil
 }
 
+type isTunnelFrame_Frame interface {
+	isTunnelFrame_Frame()
+}
+
+type TunnelFrame_Data struct {
+	// data carries the gRPC connection the coordination server calls the node over
+	Data []byte `protobuf:"bytes,1,opt,name=data,proto3,oneof"`
+}
+
+type TunnelFrame_Heartbeat struct {
+	// heartbeats are sent by the node and echoed back by the coordination server
+	Heartbeat *Heartbeat `protobuf:"bytes,2,opt,name=heartbeat,proto3,oneof"`
+}
+
+type TunnelFrame_Inventory struct {
+	// inventory is sent by the node when it connects and whenever it changed
+	Inventory *Inventory `protobuf:"bytes,3,opt,name=inventory,proto3,oneof"`
+}
+
+func (*TunnelFrame_Data) isTunnelFrame_Frame() {}
+
+func (*TunnelFrame_Heartbeat) isTunnelFrame_Frame() {}
+
+func (*TunnelFrame_Inventory) isTunnelFrame_Frame() {}
+
 var File_sysinfo_proto protoreflect.FileDescriptor
 
 var file_sysinfo_proto_rawDesc = []byte{
 	0x0a, 0x0d, 0x73, 0x79, 0x73, 0x69, 0x6e, 0x66, 0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12,
-	0x07, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x79, 0x73, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x07, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x79, 0x73, 0x1a, 0x1e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
 	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74,
-	0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xa6, 0x01, 0x0a, 0x07, 0x53, 0x79,
+	0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xd8, 0x01, 0x0a, 0x07, 0x53, 0x79,
 	0x73, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x1a, 0x0a, 0x08, 0x68, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d,
 	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x68, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d,
 	0x65, 0x12, 0x23, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32,
@@ -525,62 +1202,165 @@ var file_sysinfo_proto_rawDesc = []byte{
 	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73,
 	0x74, 0x61, 0x6d, 0x70, 0x52, 0x08, 0x6c, 0x61, 0x73, 0x74, 0x53, 0x65, 0x65, 0x6e, 0x12, 0x12,
 	0x0a, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x6f,
-	0x72, 0x74, 0x22, 0x17, 0x0a, 0x03, 0x4b, 0x65, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x22, 0xe2, 0x01, 0x0a, 0x17,
-	0x4e, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x24, 0x0a, 0x04, 0x69, 0x6e, 0x66, 0x6f, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x79, 0x73, 0x2e,
-	0x53, 0x79, 0x73, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x04, 0x69, 0x6e, 0x66, 0x6f, 0x12, 0x1e, 0x0a,
+	0x72, 0x74, 0x12, 0x30, 0x0a, 0x09, 0x69, 0x6e, 0x76, 0x65, 0x6e, 0x74, 0x6f, 0x72, 0x79, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x79, 0x73, 0x2e,
+	0x49, 0x6e, 0x76, 0x65, 0x6e, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x09, 0x69, 0x6e, 0x76, 0x65, 0x6e,
+	0x74, 0x6f, 0x72, 0x79, 0x22, 0x94, 0x01, 0x0a, 0x04, 0x44, 0x69, 0x73, 0x6b, 0x12, 0x16, 0x0a,
+	0x06, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x64,
+	0x65, 0x76, 0x69, 0x63, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x70, 0x6f,
+	0x69, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6d, 0x6f, 0x75, 0x6e, 0x74,
+	0x70, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x73, 0x74, 0x79, 0x70, 0x65, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x66, 0x73, 0x74, 0x79, 0x70, 0x65, 0x12, 0x1e, 0x0a,
+	0x0a, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x42, 0x79, 0x74, 0x65, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x0a, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x1c, 0x0a,
+	0x09, 0x66, 0x72, 0x65, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x09, 0x66, 0x72, 0x65, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x22, 0x78, 0x0a, 0x10, 0x4e,
+	0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x12,
+	0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x6d, 0x61, 0x63, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x03, 0x6d, 0x61, 0x63, 0x12, 0x1c, 0x0a, 0x09, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73,
+	0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73,
+	0x73, 0x65, 0x73, 0x12, 0x10, 0x0a, 0x03, 0x6d, 0x74, 0x75, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x03, 0x6d, 0x74, 0x75, 0x12, 0x0e, 0x0a, 0x02, 0x75, 0x70, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x02, 0x75, 0x70, 0x22, 0xef, 0x04, 0x0a, 0x09, 0x49, 0x6e, 0x76, 0x65, 0x6e, 0x74,
+	0x6f, 0x72, 0x79, 0x12, 0x0e, 0x0a, 0x02, 0x6f, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x02, 0x6f, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x69, 0x73, 0x74, 0x72, 0x6f, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x06, 0x64, 0x69, 0x73, 0x74, 0x72, 0x6f, 0x12, 0x24, 0x0a, 0x0d, 0x64,
+	0x69, 0x73, 0x74, 0x72, 0x6f, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0d, 0x64, 0x69, 0x73, 0x74, 0x72, 0x6f, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x12, 0x1e, 0x0a, 0x0a, 0x64, 0x69, 0x73, 0x74, 0x72, 0x6f, 0x4e, 0x61, 0x6d, 0x65, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x64, 0x69, 0x73, 0x74, 0x72, 0x6f, 0x4e, 0x61, 0x6d,
+	0x65, 0x12, 0x16, 0x0a, 0x06, 0x6b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x6b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x12, 0x12, 0x0a, 0x04, 0x61, 0x72, 0x63,
+	0x68, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x61, 0x72, 0x63, 0x68, 0x12, 0x1a, 0x0a,
+	0x08, 0x63, 0x70, 0x75, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x08, 0x63, 0x70, 0x75, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x70, 0x75,
+	0x4d, 0x6f, 0x64, 0x65, 0x6c, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x63, 0x70, 0x75,
+	0x4d, 0x6f, 0x64, 0x65, 0x6c, 0x12, 0x2a, 0x0a, 0x10, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x54,
+	0x6f, 0x74, 0x61, 0x6c, 0x42, 0x79, 0x74, 0x65, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x10, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x42, 0x79, 0x74, 0x65,
+	0x73, 0x12, 0x32, 0x0a, 0x14, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x41, 0x76, 0x61, 0x69, 0x6c,
+	0x61, 0x62, 0x6c, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x14, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x41, 0x76, 0x61, 0x69, 0x6c, 0x61, 0x62, 0x6c, 0x65,
+	0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x23, 0x0a, 0x05, 0x64, 0x69, 0x73, 0x6b, 0x73, 0x18, 0x0b,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x79, 0x73, 0x2e, 0x44,
+	0x69, 0x73, 0x6b, 0x52, 0x05, 0x64, 0x69, 0x73, 0x6b, 0x73, 0x12, 0x39, 0x0a, 0x0a, 0x69, 0x6e,
+	0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x73, 0x18, 0x0c, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19,
+	0x2e, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x79, 0x73, 0x2e, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b,
+	0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x52, 0x0a, 0x69, 0x6e, 0x74, 0x65, 0x72,
+	0x66, 0x61, 0x63, 0x65, 0x73, 0x12, 0x22, 0x0a, 0x0c, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x63, 0x61,
+	0x6c, 0x65, 0x49, 0x70, 0x73, 0x18, 0x0d, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0c, 0x74, 0x61, 0x69,
+	0x6c, 0x73, 0x63, 0x61, 0x6c, 0x65, 0x49, 0x70, 0x73, 0x12, 0x24, 0x0a, 0x0d, 0x74, 0x61, 0x69,
+	0x6c, 0x73, 0x63, 0x61, 0x6c, 0x65, 0x54, 0x61, 0x67, 0x73, 0x18, 0x0e, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x0d, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x63, 0x61, 0x6c, 0x65, 0x54, 0x61, 0x67, 0x73, 0x12,
+	0x24, 0x0a, 0x0d, 0x75, 0x70, 0x74, 0x69, 0x6d, 0x65, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73,
+	0x18, 0x0f, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x75, 0x70, 0x74, 0x69, 0x6d, 0x65, 0x53, 0x65,
+	0x63, 0x6f, 0x6e, 0x64, 0x73, 0x12, 0x26, 0x0a, 0x0e, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x79, 0x73,
+	0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x10, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x74,
+	0x61, 0x69, 0x6c, 0x73, 0x79, 0x73, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x38, 0x0a,
+	0x09, 0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x65, 0x64, 0x18, 0x11, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x63, 0x6f,
+	0x6c, 0x6c, 0x65, 0x63, 0x74, 0x65, 0x64, 0x22, 0x17, 0x0a, 0x03, 0x4b, 0x65, 0x79, 0x12, 0x10,
+	0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79,
+	0x22, 0xfc, 0x01, 0x0a, 0x17, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x24, 0x0a, 0x04,
+	0x69, 0x6e, 0x66, 0x6f, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x74, 0x61, 0x69,
+	0x6c, 0x73, 0x79, 0x73, 0x2e, 0x53, 0x79, 0x73, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x04, 0x69, 0x6e,
+	0x66, 0x6f, 0x12, 0x1e, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x0c, 0x2e, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x79, 0x73, 0x2e, 0x4b, 0x65, 0x79, 0x52, 0x03, 0x6b,
+	0x65, 0x79, 0x12, 0x33, 0x0a, 0x0a, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x54, 0x79, 0x70, 0x65,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x13, 0x2e, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x79, 0x73,
+	0x2e, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x54, 0x79, 0x70, 0x65, 0x52, 0x0a, 0x73, 0x79, 0x73,
+	0x74, 0x65, 0x6d, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x61, 0x63, 0x63, 0x65, 0x70,
+	0x74, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x61, 0x63, 0x63, 0x65, 0x70,
+	0x74, 0x65, 0x64, 0x12, 0x1a, 0x0a, 0x06, 0x74, 0x6c, 0x73, 0x6b, 0x65, 0x79, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x09, 0x42, 0x02, 0x18, 0x01, 0x52, 0x06, 0x74, 0x6c, 0x73, 0x6b, 0x65, 0x79, 0x12,
+	0x1c, 0x0a, 0x07, 0x74, 0x6c, 0x73, 0x63, 0x65, 0x72, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09,
+	0x42, 0x02, 0x18, 0x01, 0x52, 0x07, 0x74, 0x6c, 0x73, 0x63, 0x65, 0x72, 0x74, 0x12, 0x10, 0x0a,
+	0x03, 0x63, 0x73, 0x72, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x03, 0x63, 0x73, 0x72, 0x22,
+	0x43, 0x0a, 0x0b, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x12, 0x12,
+	0x0a, 0x04, 0x63, 0x65, 0x72, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x63, 0x65,
+	0x72, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x63, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02,
+	0x63, 0x61, 0x12, 0x10, 0x0a, 0x03, 0x63, 0x72, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x03, 0x63, 0x72, 0x6c, 0x22, 0xca, 0x01, 0x0a, 0x18, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x67,
+	0x69, 0x73, 0x74, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x1a, 0x0a, 0x08, 0x61, 0x63, 0x63, 0x65, 0x70, 0x74, 0x65, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x08, 0x61, 0x63, 0x63, 0x65, 0x70, 0x74, 0x65, 0x64, 0x12, 0x1e, 0x0a,
 	0x03, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x74, 0x61, 0x69,
-	0x6c, 0x73, 0x79, 0x73, 0x2e, 0x4b, 0x65, 0x79, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x33, 0x0a,
-	0x0a, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x54, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
-	0x0e, 0x32, 0x13, 0x2e, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x79, 0x73, 0x2e, 0x53, 0x79, 0x73, 0x74,
-	0x65, 0x6d, 0x54, 0x79, 0x70, 0x65, 0x52, 0x0a, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x54, 0x79,
-	0x70, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x61, 0x63, 0x63, 0x65, 0x70, 0x74, 0x65, 0x64, 0x18, 0x04,
-	0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x61, 0x63, 0x63, 0x65, 0x70, 0x74, 0x65, 0x64, 0x12, 0x16,
-	0x0a, 0x06, 0x74, 0x6c, 0x73, 0x6b, 0x65, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
-	0x74, 0x6c, 0x73, 0x6b, 0x65, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x74, 0x6c, 0x73, 0x63, 0x65, 0x72,
-	0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x74, 0x6c, 0x73, 0x63, 0x65, 0x72, 0x74,
-	0x22, 0x72, 0x0a, 0x18, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x61,
-	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1a, 0x0a, 0x08,
-	0x61, 0x63, 0x63, 0x65, 0x70, 0x74, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08,
-	0x61, 0x63, 0x63, 0x65, 0x70, 0x74, 0x65, 0x64, 0x12, 0x1e, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x79, 0x73, 0x2e,
-	0x4b, 0x65, 0x79, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x1a, 0x0a, 0x08, 0x68, 0x6f, 0x73, 0x74,
-	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x68, 0x6f, 0x73, 0x74,
-	0x6e, 0x61, 0x6d, 0x65, 0x22, 0x3d, 0x0a, 0x0b, 0x50, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x12, 0x2e, 0x0a, 0x04, 0x70, 0x69, 0x6e, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x6c, 0x73, 0x79, 0x73, 0x2e, 0x4b, 0x65, 0x79, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x1a, 0x0a,
+	0x08, 0x68, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x68, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x36, 0x0a, 0x0b, 0x63, 0x65, 0x72,
+	0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14,
+	0x2e, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x79, 0x73, 0x2e, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69,
+	0x63, 0x61, 0x74, 0x65, 0x52, 0x0b, 0x63, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74,
+	0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x73, 0x69, 0x67, 0x6e, 0x69, 0x6e, 0x67, 0x4b, 0x65, 0x79, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x73, 0x69, 0x67, 0x6e, 0x69, 0x6e, 0x67, 0x4b, 0x65,
+	0x79, 0x22, 0x20, 0x0a, 0x0c, 0x52, 0x65, 0x6e, 0x65, 0x77, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x10, 0x0a, 0x03, 0x63, 0x73, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x03,
+	0x63, 0x73, 0x72, 0x22, 0x3d, 0x0a, 0x0b, 0x50, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x2e, 0x0a, 0x04, 0x70, 0x69, 0x6e, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x04, 0x70, 0x69,
+	0x6e, 0x67, 0x22, 0x86, 0x01, 0x0a, 0x0c, 0x50, 0x6f, 0x6e, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x2e, 0x0a, 0x04, 0x70, 0x69, 0x6e, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28,
 	0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
 	0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x04, 0x70,
-	0x69, 0x6e, 0x67, 0x22, 0x86, 0x01, 0x0a, 0x0c, 0x50, 0x6f, 0x6e, 0x67, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2e, 0x0a, 0x04, 0x70, 0x69, 0x6e, 0x67, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x04,
-	0x70, 0x69, 0x6e, 0x67, 0x12, 0x26, 0x0a, 0x0e, 0x69, 0x6e, 0x62, 0x6f, 0x75, 0x6e, 0x64, 0x4c,
-	0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x02, 0x52, 0x0e, 0x69, 0x6e,
-	0x62, 0x6f, 0x75, 0x6e, 0x64, 0x4c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x12, 0x1e, 0x0a, 0x03,
-	0x6b, 0x65, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x74, 0x61, 0x69, 0x6c,
-	0x73, 0x79, 0x73, 0x2e, 0x4b, 0x65, 0x79, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x2a, 0x44, 0x0a, 0x06,
-	0x4f, 0x53, 0x54, 0x79, 0x70, 0x65, 0x12, 0x17, 0x0a, 0x13, 0x4f, 0x53, 0x5f, 0x54, 0x59, 0x50,
-	0x45, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12,
-	0x09, 0x0a, 0x05, 0x4c, 0x49, 0x4e, 0x55, 0x58, 0x10, 0x01, 0x12, 0x09, 0x0a, 0x05, 0x4d, 0x41,
-	0x43, 0x4f, 0x53, 0x10, 0x02, 0x12, 0x0b, 0x0a, 0x07, 0x57, 0x49, 0x4e, 0x44, 0x4f, 0x57, 0x53,
-	0x10, 0x03, 0x2a, 0x3a, 0x0a, 0x0a, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x54, 0x79, 0x70, 0x65,
-	0x12, 0x17, 0x0a, 0x13, 0x43, 0x4f, 0x4f, 0x52, 0x44, 0x49, 0x4e, 0x41, 0x54, 0x49, 0x4f, 0x4e,
-	0x5f, 0x53, 0x45, 0x52, 0x56, 0x45, 0x52, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x43, 0x4c, 0x49,
-	0x45, 0x4e, 0x54, 0x10, 0x01, 0x12, 0x07, 0x0a, 0x03, 0x43, 0x4c, 0x49, 0x10, 0x02, 0x32, 0x61,
-	0x0a, 0x0c, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x51,
-	0x0a, 0x08, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x12, 0x20, 0x2e, 0x74, 0x61, 0x69,
-	0x6c, 0x73, 0x79, 0x73, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72,
-	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x21, 0x2e, 0x74,
-	0x61, 0x69, 0x6c, 0x73, 0x79, 0x73, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x67, 0x69, 0x73,
-	0x74, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
-	0x00, 0x32, 0x3f, 0x0a, 0x06, 0x50, 0x69, 0x6e, 0x67, 0x65, 0x72, 0x12, 0x35, 0x0a, 0x04, 0x50,
-	0x69, 0x6e, 0x67, 0x12, 0x14, 0x2e, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x79, 0x73, 0x2e, 0x50, 0x69,
-	0x6e, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e, 0x74, 0x61, 0x69, 0x6c,
-	0x73, 0x79, 0x73, 0x2e, 0x50, 0x6f, 0x6e, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x22, 0x00, 0x42, 0x0c, 0x5a, 0x0a, 0x2e, 0x2f, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x73,
-	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x69, 0x6e, 0x67, 0x12, 0x26, 0x0a, 0x0e, 0x69, 0x6e, 0x62, 0x6f, 0x75, 0x6e, 0x64, 0x4c, 0x61,
+	0x74, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x02, 0x52, 0x0e, 0x69, 0x6e, 0x62,
+	0x6f, 0x75, 0x6e, 0x64, 0x4c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x12, 0x1e, 0x0a, 0x03, 0x6b,
+	0x65, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x74, 0x61, 0x69, 0x6c, 0x73,
+	0x79, 0x73, 0x2e, 0x4b, 0x65, 0x79, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x22, 0x10, 0x0a, 0x0e, 0x53,
+	0x79, 0x73, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x72, 0x0a,
+	0x09, 0x48, 0x65, 0x61, 0x72, 0x74, 0x62, 0x65, 0x61, 0x74, 0x12, 0x2e, 0x0a, 0x04, 0x73, 0x65,
+	0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x52, 0x04, 0x73, 0x65, 0x6e, 0x74, 0x12, 0x35, 0x0a, 0x08, 0x69, 0x6e,
+	0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44,
+	0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61,
+	0x6c, 0x22, 0x94, 0x01, 0x0a, 0x0b, 0x54, 0x75, 0x6e, 0x6e, 0x65, 0x6c, 0x46, 0x72, 0x61, 0x6d,
+	0x65, 0x12, 0x14, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x48,
+	0x00, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x32, 0x0a, 0x09, 0x68, 0x65, 0x61, 0x72, 0x74,
+	0x62, 0x65, 0x61, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x74, 0x61, 0x69,
+	0x6c, 0x73, 0x79, 0x73, 0x2e, 0x48, 0x65, 0x61, 0x72, 0x74, 0x62, 0x65, 0x61, 0x74, 0x48, 0x00,
+	0x52, 0x09, 0x68, 0x65, 0x61, 0x72, 0x74, 0x62, 0x65, 0x61, 0x74, 0x12, 0x32, 0x0a, 0x09, 0x69,
+	0x6e, 0x76, 0x65, 0x6e, 0x74, 0x6f, 0x72, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12,
+	0x2e, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x79, 0x73, 0x2e, 0x49, 0x6e, 0x76, 0x65, 0x6e, 0x74, 0x6f,
+	0x72, 0x79, 0x48, 0x00, 0x52, 0x09, 0x69, 0x6e, 0x76, 0x65, 0x6e, 0x74, 0x6f, 0x72, 0x79, 0x42,
+	0x07, 0x0a, 0x05, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x2a, 0x44, 0x0a, 0x06, 0x4f, 0x53, 0x54, 0x79,
+	0x70, 0x65, 0x12, 0x17, 0x0a, 0x13, 0x4f, 0x53, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x55, 0x4e,
+	0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x09, 0x0a, 0x05, 0x4c,
+	0x49, 0x4e, 0x55, 0x58, 0x10, 0x01, 0x12, 0x09, 0x0a, 0x05, 0x4d, 0x41, 0x43, 0x4f, 0x53, 0x10,
+	0x02, 0x12, 0x0b, 0x0a, 0x07, 0x57, 0x49, 0x4e, 0x44, 0x4f, 0x57, 0x53, 0x10, 0x03, 0x2a, 0x3a,
+	0x0a, 0x0a, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x54, 0x79, 0x70, 0x65, 0x12, 0x17, 0x0a, 0x13,
+	0x43, 0x4f, 0x4f, 0x52, 0x44, 0x49, 0x4e, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x53, 0x45, 0x52,
+	0x56, 0x45, 0x52, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x43, 0x4c, 0x49, 0x45, 0x4e, 0x54, 0x10,
+	0x01, 0x12, 0x07, 0x0a, 0x03, 0x43, 0x4c, 0x49, 0x10, 0x02, 0x32, 0x99, 0x01, 0x0a, 0x0c, 0x52,
+	0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x51, 0x0a, 0x08, 0x52,
+	0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x12, 0x20, 0x2e, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x79,
+	0x73, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x21, 0x2e, 0x74, 0x61, 0x69, 0x6c,
+	0x73, 0x79, 0x73, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x36,
+	0x0a, 0x05, 0x52, 0x65, 0x6e, 0x65, 0x77, 0x12, 0x15, 0x2e, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x79,
+	0x73, 0x2e, 0x52, 0x65, 0x6e, 0x65, 0x77, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14,
+	0x2e, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x79, 0x73, 0x2e, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69,
+	0x63, 0x61, 0x74, 0x65, 0x22, 0x00, 0x32, 0x3f, 0x0a, 0x06, 0x50, 0x69, 0x6e, 0x67, 0x65, 0x72,
+	0x12, 0x35, 0x0a, 0x04, 0x50, 0x69, 0x6e, 0x67, 0x12, 0x14, 0x2e, 0x74, 0x61, 0x69, 0x6c, 0x73,
+	0x79, 0x73, 0x2e, 0x50, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x15,
+	0x2e, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x79, 0x73, 0x2e, 0x50, 0x6f, 0x6e, 0x67, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x32, 0x44, 0x0a, 0x0a, 0x53, 0x79, 0x73, 0x74, 0x65,
+	0x6d, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x36, 0x0a, 0x07, 0x53, 0x79, 0x73, 0x49, 0x6e, 0x66, 0x6f,
+	0x12, 0x17, 0x2e, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x79, 0x73, 0x2e, 0x53, 0x79, 0x73, 0x49, 0x6e,
+	0x66, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x10, 0x2e, 0x74, 0x61, 0x69, 0x6c,
+	0x73, 0x79, 0x73, 0x2e, 0x53, 0x79, 0x73, 0x49, 0x6e, 0x66, 0x6f, 0x22, 0x00, 0x32, 0x45, 0x0a,
+	0x06, 0x54, 0x75, 0x6e, 0x6e, 0x65, 0x6c, 0x12, 0x3b, 0x0a, 0x07, 0x43, 0x6f, 0x6e, 0x6e, 0x65,
+	0x63, 0x74, 0x12, 0x14, 0x2e, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x79, 0x73, 0x2e, 0x54, 0x75, 0x6e,
+	0x6e, 0x65, 0x6c, 0x46, 0x72, 0x61, 0x6d, 0x65, 0x1a, 0x14, 0x2e, 0x74, 0x61, 0x69, 0x6c, 0x73,
+	0x79, 0x73, 0x2e, 0x54, 0x75, 0x6e, 0x6e, 0x65, 0x6c, 0x46, 0x72, 0x61, 0x6d, 0x65, 0x22, 0x00,
+	0x28, 0x01, 0x30, 0x01, 0x42, 0x0c, 0x5a, 0x0a, 0x2e, 0x2f, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e,
+	0x64, 0x73, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -596,37 +1376,61 @@ func file_sysinfo_proto_rawDescGZIP() []byte {
 }
 
 var file_sysinfo_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_sysinfo_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_sysinfo_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
 var file_sysinfo_proto_goTypes = []interface{}{
 	(OSType)(0),                      // 0: tailsys.OSType
 	(SystemType)(0),                  // 1: tailsys.SystemType
 	(*SysInfo)(nil),                  // 2: tailsys.SysInfo
-	(*Key)(nil),                      // 3: tailsys.Key
-	(*NodeRegistrationRequest)(nil),  // 4: tailsys.NodeRegistrationRequest
-	(*NodeRegistrationResponse)(nil), // 5: tailsys.NodeRegistrationResponse
-	(*PingRequest)(nil),              // 6: tailsys.PingRequest
-	(*PongResponse)(nil),             // 7: tailsys.PongResponse
-	(*timestamp.Timestamp)(nil),      // 8: google.protobuf.Timestamp
+	(*Disk)(nil),                     // 3: tailsys.Disk
+	(*NetworkInterface)(nil),         // 4: tailsys.NetworkInterface
+	(*Inventory)(nil),                // 5: tailsys.Inventory
+	(*Key)(nil),                      // 6: tailsys.Key
+	(*NodeRegistrationRequest)(nil),  // 7: tailsys.NodeRegistrationRequest
+	(*Certificate)(nil),              // 8: tailsys.Certificate
+	(*NodeRegistrationResponse)(nil), // 9: tailsys.NodeRegistrationResponse
+	(*RenewRequest)(nil),             // 10: tailsys.RenewRequest
+	(*PingRequest)(nil),              // 11: tailsys.PingRequest
+	(*PongResponse)(nil),             // 12: tailsys.PongResponse
+	(*SysInfoRequest)(nil),           // 13: tailsys.SysInfoRequest
+	(*Heartbeat)(nil),                // 14: tailsys.Heartbeat
+	(*TunnelFrame)(nil),              // 15: tailsys.TunnelFrame
+	(*timestamp.Timestamp)(nil),      // 16: google.protobuf.Timestamp
+	(*durationpb.Duration)(nil),      // 17: google.protobuf.Duration
 }
 var file_sysinfo_proto_depIdxs = []int32{
 	0,  // 0: tailsys.SysInfo.type:type_name -> tailsys.OSType
-	8,  // 1: tailsys.SysInfo.lastSeen:type_name -> google.protobuf.Timestamp
-	2,  // 2: tailsys.NodeRegistrationRequest.info:type_name -> tailsys.SysInfo
-	3,  // 3: tailsys.NodeRegistrationRequest.key:type_name -> tailsys.Key
-	1,  // 4: tailsys.NodeRegistrationRequest.systemType:type_name -> tailsys.SystemType
-	3,  // 5: tailsys.NodeRegistrationResponse.key:type_name -> tailsys.Key
-	8,  // 6: tailsys.PingRequest.ping:type_name -> google.protobuf.Timestamp
-	8,  // 7: tailsys.PongResponse.ping:type_name -> google.protobuf.Timestamp
-	3,  // 8: tailsys.PongResponse.key:type_name -> tailsys.Key
-	4,  // 9: tailsys.Registration.Register:input_type -> tailsys.NodeRegistrationRequest
-	6,  // 10: tailsys.Pinger.Ping:input_type -> tailsys.PingRequest
-	5,  // 11: tailsys.Registration.Register:output_type -> tailsys.NodeRegistrationResponse
-	7,  // 12: tailsys.Pinger.Ping:output_type -> tailsys.PongResponse
-	11, // [11:13] is the sub-list for method output_type
-	9,  // [9:11] is the sub-list for method input_type
-	9,  // [9:9] is the sub-list for extension type_name
-	9,  // [9:9] is the sub-list for extension extendee
-	0,  // [0:9] is the sub-list for field type_name
+	16, // 1: tailsys.SysInfo.lastSeen:type_name -> google.protobuf.Timestamp
+	5,  // 2: tailsys.SysInfo.inventory:type_name -> tailsys.Inventory
+	3,  // 3: tailsys.Inventory.disks:type_name -> tailsys.Disk
+	4,  // 4: tailsys.Inventory.interfaces:type_name -> tailsys.NetworkInterface
+	16, // 5: tailsys.Inventory.collected:type_name -> google.protobuf.Timestamp
+	2,  // 6: tailsys.NodeRegistrationRequest.info:type_name -> tailsys.SysInfo
+	6,  // 7: tailsys.NodeRegistrationRequest.key:type_name -> tailsys.Key
+	1,  // 8: tailsys.NodeRegistrationRequest.systemType:type_name -> tailsys.SystemType
+	6,  // 9: tailsys.NodeRegistrationResponse.key:type_name -> tailsys.Key
+	8,  // 10: tailsys.NodeRegistrationResponse.certificate:type_name -> tailsys.Certificate
+	16, // 11: tailsys.PingRequest.ping:type_name -> google.protobuf.Timestamp
+	16, // 12: tailsys.PongResponse.ping:type_name -> google.protobuf.Timestamp
+	6,  // 13: tailsys.PongResponse.key:type_name -> tailsys.Key
+	16, // 14: tailsys.Heartbeat.sent:type_name -> google.protobuf.Timestamp
+	17, // 15: tailsys.Heartbeat.interval:type_name -> google.protobuf.Duration
+	14, // 16: tailsys.TunnelFrame.heartbeat:type_name -> tailsys.Heartbeat
+	5,  // 17: tailsys.TunnelFrame.inventory:type_name -> tailsys.Inventory
+	7,  // 18: tailsys.Registration.Register:input_type -> tailsys.NodeRegistrationRequest
+	10, // 19: tailsys.Registration.Renew:input_type -> tailsys.RenewRequest
+	11, // 20: tailsys.Pinger.Ping:input_type -> tailsys.PingRequest
+	13, // 21: tailsys.SystemInfo.SysInfo:input_type -> tailsys.SysInfoRequest
+	15, // 22: tailsys.Tunnel.Connect:input_type -> tailsys.TunnelFrame
+	9,  // 23: tailsys.Registration.Register:output_type -> tailsys.NodeRegistrationResponse
+	8,  // 24: tailsys.Registration.Renew:output_type -> tailsys.Certificate
+	12, // 25: tailsys.Pinger.Ping:output_type -> tailsys.PongResponse
+	2,  // 26: tailsys.SystemInfo.SysInfo:output_type -> tailsys.SysInfo
+	15, // 27: tailsys.Tunnel.Connect:output_type -> tailsys.TunnelFrame
+	23, // [23:28] is the sub-list for method output_type
+	18, // [18:23] is the sub-list for method input_type
+	18, // [18:18] is the sub-list for extension type_name
+	18, // [18:18] is the sub-list for extension extendee
+	0,  // [0:18] is the sub-list for field type_name
 }
 
 func init() { file_sysinfo_proto_init() }
@@ -648,7 +1452,7 @@ func file_sysinfo_proto_init() {
 			}
 		}
 		file_sysinfo_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Key); i {
+			switch v := v.(*Disk); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -660,7 +1464,7 @@ func file_sysinfo_proto_init() {
 			}
 		}
 		file_sysinfo_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*NodeRegistrationRequest); i {
+			switch v := v.(*NetworkInterface); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -672,7 +1476,7 @@ func file_sysinfo_proto_init() {
 			}
 		}
 		file_sysinfo_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*NodeRegistrationResponse); i {
+			switch v := v.(*Inventory); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -684,7 +1488,7 @@ func file_sysinfo_proto_init() {
 			}
 		}
 		file_sysinfo_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PingRequest); i {
+			switch v := v.(*Key); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -696,6 +1500,66 @@ func file_sysinfo_proto_init() {
 			}
 		}
 		file_sysinfo_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NodeRegistrationRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_sysinfo_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Certificate); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_sysinfo_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NodeRegistrationResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_sysinfo_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RenewRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_sysinfo_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PingRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_sysinfo_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*PongResponse); i {
 			case 0:
 				return &v.state
@@ -707,6 +1571,47 @@ func file_sysinfo_proto_init() {
 				return nil
 			}
 		}
+		file_sysinfo_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SysInfoRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_sysinfo_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Heartbeat); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_sysinfo_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TunnelFrame); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_sysinfo_proto_msgTypes[13].OneofWrappers = []interface{}{
+		(*TunnelFrame_Data)(nil),
+		(*TunnelFrame_Heartbeat)(nil),
+		(*TunnelFrame_Inventory)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -714,9 +1619,9 @@ func file_sysinfo_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_sysinfo_proto_rawDesc,
 			NumEnums:      2,
-			NumMessages:   6,
+			NumMessages:   14,
 			NumExtensions: 0,
-			NumServices:   2,
+			NumServices:   4,
 		},
 		GoTypes:           file_sysinfo_proto_goTypes,
 		DependencyIndexes: file_sysinfo_proto_depIdxs,
@@ -20,6 +20,7 @@ const _ = grpc.SupportPackageIsVersion7
 
 const (
 	Registration_Register_FullMethodName = "/tailsys.Registration/Register"
+	Registration_Renew_FullMethodName    = "/tailsys.Registration/Renew"
 )
 
 // RegistrationClient is the client API for Registration service.
@@ -27,6 +28,8 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type RegistrationClient interface {
 	Register(ctx context.Context, in *NodeRegistrationRequest, opts ...grpc.CallOption) (*NodeRegistrationResponse, error)
+	// Renew signs a new certificate for the calling node, without a csr only the ca bundle and crl are returned
+	Renew(ctx context.Context, in *RenewRequest, opts ...grpc.CallOption) (*Certificate, error)
 }
 
 type registrationClient struct {
@@ -46,11 +49,22 @@ func (c *registrationClient) Register(ctx context.Context, in *NodeRegistrationR
 	return out, nil
 }
 
+func (c *registrationClient) Renew(ctx context.Context, in *RenewRequest, opts ...grpc.CallOption) (*Certificate, error) {
+	out := new(Certificate)
+	err := c.cc.Invoke(ctx, Registration_Renew_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // RegistrationServer is the server API for Registration service.
 // All implementations must embed UnimplementedRegistrationServer
 // for forward compatibility
 type RegistrationServer interface {
 	Register(context.Context, *NodeRegistrationRequest) (*NodeRegistrationResponse, error)
+	// Renew signs a new certificate for the calling node, without a csr only the ca bundle and crl are returned
+	Renew(context.Context, *RenewRequest) (*Certificate, error)
 	mustEmbedUnimplementedRegistrationServer()
 }
 
@@ -61,6 +75,9 @@ type UnimplementedRegistrationServer struct {
 func (UnimplementedRegistrationServer) Register(context.Context, *NodeRegistrationRequest) (*NodeRegistrationResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Register not implemented")
 }
+func (UnimplementedRegistrationServer) Renew(context.Context, *RenewRequest) (*Certificate, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Renew not implemented")
+}
 func (UnimplementedRegistrationServer) mustEmbedUnimplementedRegistrationServer() {}
 
 // UnsafeRegistrationServer may be embedded to opt out of forward compatibility for this service.
@@ -92,6 +109,24 @@ func _Registration_Register_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Registration_Renew_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenewRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RegistrationServer).Renew(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Registration_Renew_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RegistrationServer).Renew(ctx, req.(*RenewRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Registration_ServiceDesc is the grpc.ServiceDesc for Registration service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -103,6 +138,10 @@ var Registration_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Register",
 			Handler:    _Registration_Register_Handler,
 		},
+		{
+			MethodName: "Renew",
+			Handler:    _Registration_Renew_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "sysinfo.proto",
@@ -197,3 +236,221 @@ var Pinger_ServiceDesc = grpc.ServiceDesc{
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "sysinfo.proto",
 }
+
+const (
+	SystemInfo_SysInfo_FullMethodName = "/tailsys.SystemInfo/SysInfo"
+)
+
+// SystemInfoClient is the client API for SystemInfo service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type SystemInfoClient interface {
+	// SysInfo collects the inventory of the node again
+	SysInfo(ctx context.Context, in *SysInfoRequest, opts ...grpc.CallOption) (*SysInfo, error)
+}
+
+type systemInfoClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSystemInfoClient(cc grpc.ClientConnInterface) SystemInfoClient {
+	return &systemInfoClient{cc}
+}
+
+func (c *systemInfoClient) SysInfo(ctx context.Context, in *SysInfoRequest, opts ...grpc.CallOption) (*SysInfo, error) {
+	out := new(SysInfo)
+	err := c.cc.Invoke(ctx, SystemInfo_SysInfo_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SystemInfoServer is the server API for SystemInfo service.
+// All implementations must embed UnimplementedSystemInfoServer
+// for forward compatibility
+type SystemInfoServer interface {
+	// SysInfo collects the inventory of the node again
+	SysInfo(context.Context, *SysInfoRequest) (*SysInfo, error)
+	mustEmbedUnimplementedSystemInfoServer()
+}
+
+// UnimplementedSystemInfoServer must be embedded to have forward compatible implementations.
+type UnimplementedSystemInfoServer struct {
+}
+
+func (UnimplementedSystemInfoServer) SysInfo(context.Context, *SysInfoRequest) (*SysInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SysInfo not implemented")
+}
+func (UnimplementedSystemInfoServer) mustEmbedUnimplementedSystemInfoServer() {}
+
+// UnsafeSystemInfoServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SystemInfoServer will
+// result in compilation errors.
+type UnsafeSystemInfoServer interface {
+	mustEmbedUnimplementedSystemInfoServer()
+}
+
+func RegisterSystemInfoServer(s grpc.ServiceRegistrar, srv SystemInfoServer) {
+	s.RegisterService(&SystemInfo_ServiceDesc, srv)
+}
+
+func _SystemInfo_SysInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SysInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SystemInfoServer).SysInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SystemInfo_SysInfo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SystemInfoServer).SysInfo(ctx, req.(*SysInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// SystemInfo_ServiceDesc is the grpc.ServiceDesc for SystemInfo service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var SystemInfo_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "tailsys.SystemInfo",
+	HandlerType: (*SystemInfoServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SysInfo",
+			Handler:    _SystemInfo_SysInfo_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "sysinfo.proto",
+}
+
+const (
+	Tunnel_Connect_FullMethodName = "/tailsys.Tunnel/Connect"
+)
+
+// TunnelClient is the client API for Tunnel service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type TunnelClient interface {
+	// Connect is opened by nodes running in reverse mode, the coordination server calls them back through it
+	// instead of dialing them
+	Connect(ctx context.Context, opts ...grpc.CallOption) (Tunnel_ConnectClient, error)
+}
+
+type tunnelClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTunnelClient(cc grpc.ClientConnInterface) TunnelClient {
+	return &tunnelClient{cc}
+}
+
+func (c *tunnelClient) Connect(ctx context.Context, opts ...grpc.CallOption) (Tunnel_ConnectClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Tunnel_ServiceDesc.Streams[0], Tunnel_Connect_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &tunnelConnectClient{stream}
+	return x, nil
+}
+
+type Tunnel_ConnectClient interface {
+	Send(*TunnelFrame) error
+	Recv() (*TunnelFrame, error)
+	grpc.ClientStream
+}
+
+type tunnelConnectClient struct {
+	grpc.ClientStream
+}
+
+func (x *tunnelConnectClient) Send(m *TunnelFrame) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *tunnelConnectClient) Recv() (*TunnelFrame, error) {
+	m := new(TunnelFrame)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// TunnelServer is the server API for Tunnel service.
+// All implementations must embed UnimplementedTunnelServer
+// for forward compatibility
+type TunnelServer interface {
+	// Connect is opened by nodes running in reverse mode, the coordination server calls them back through it
+	// instead of dialing them
+	Connect(Tunnel_ConnectServer) error
+	mustEmbedUnimplementedTunnelServer()
+}
+
+// UnimplementedTunnelServer must be embedded to have forward compatible implementations.
+type UnimplementedTunnelServer struct {
+}
+
+func (UnimplementedTunnelServer) Connect(Tunnel_ConnectServer) error {
+	return status.Errorf(codes.Unimplemented, "method Connect not implemented")
+}
+func (UnimplementedTunnelServer) mustEmbedUnimplementedTunnelServer() {}
+
+// UnsafeTunnelServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TunnelServer will
+// result in compilation errors.
+type UnsafeTunnelServer interface {
+	mustEmbedUnimplementedTunnelServer()
+}
+
+func RegisterTunnelServer(s grpc.ServiceRegistrar, srv TunnelServer) {
+	s.RegisterService(&Tunnel_ServiceDesc, srv)
+}
+
+func _Tunnel_Connect_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(TunnelServer).Connect(&tunnelConnectServer{stream})
+}
+
+type Tunnel_ConnectServer interface {
+	Send(*TunnelFrame) error
+	Recv() (*TunnelFrame, error)
+	grpc.ServerStream
+}
+
+type tunnelConnectServer struct {
+	grpc.ServerStream
+}
+
+func (x *tunnelConnectServer) Send(m *TunnelFrame) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *tunnelConnectServer) Recv() (*TunnelFrame, error) {
+	m := new(TunnelFrame)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Tunnel_ServiceDesc is the grpc.ServiceDesc for Tunnel service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Tunnel_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "tailsys.Tunnel",
+	HandlerType: (*TunnelServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Connect",
+			Handler:       _Tunnel_Connect_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "sysinfo.proto",
+}
@@ -0,0 +1,207 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v3.12.4
+// source: transfer.proto
+
+package commands
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	FileTransfer_Upload_FullMethodName   = "/tailsys.FileTransfer/Upload"
+	FileTransfer_Download_FullMethodName = "/tailsys.FileTransfer/Download"
+)
+
+// FileTransferClient is the client API for FileTransfer service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type FileTransferClient interface {
+	Upload(ctx context.Context, opts ...grpc.CallOption) (FileTransfer_UploadClient, error)
+	Download(ctx context.Context, in *FileDownloadRequest, opts ...grpc.CallOption) (FileTransfer_DownloadClient, error)
+}
+
+type fileTransferClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewFileTransferClient(cc grpc.ClientConnInterface) FileTransferClient {
+	return &fileTransferClient{cc}
+}
+
+func (c *fileTransferClient) Upload(ctx context.Context, opts ...grpc.CallOption) (FileTransfer_UploadClient, error) {
+	stream, err := c.cc.NewStream(ctx, &FileTransfer_ServiceDesc.Streams[0], FileTransfer_Upload_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &fileTransferUploadClient{stream}
+	return x, nil
+}
+
+type FileTransfer_UploadClient interface {
+	Send(*FileUpload) error
+	CloseAndRecv() (*FileUploadResponse, error)
+	grpc.ClientStream
+}
+
+type fileTransferUploadClient struct {
+	grpc.ClientStream
+}
+
+func (x *fileTransferUploadClient) Send(m *FileUpload) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *fileTransferUploadClient) CloseAndRecv() (*FileUploadResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(FileUploadResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *fileTransferClient) Download(ctx context.Context, in *FileDownloadRequest, opts ...grpc.CallOption) (FileTransfer_DownloadClient, error) {
+	stream, err := c.cc.NewStream(ctx, &FileTransfer_ServiceDesc.Streams[1], FileTransfer_Download_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &fileTransferDownloadClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type FileTransfer_DownloadClient interface {
+	Recv() (*FileChunk, error)
+	grpc.ClientStream
+}
+
+type fileTransferDownloadClient struct {
+	grpc.ClientStream
+}
+
+func (x *fileTransferDownloadClient) Recv() (*FileChunk, error) {
+	m := new(FileChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// FileTransferServer is the server API for FileTransfer service.
+// All implementations must embed UnimplementedFileTransferServer
+// for forward compatibility
+type FileTransferServer interface {
+	Upload(FileTransfer_UploadServer) error
+	Download(*FileDownloadRequest, FileTransfer_DownloadServer) error
+	mustEmbedUnimplementedFileTransferServer()
+}
+
+// UnimplementedFileTransferServer must be embedded to have forward compatible implementations.
+type UnimplementedFileTransferServer struct {
+}
+
+func (UnimplementedFileTransferServer) Upload(FileTransfer_UploadServer) error {
+	return status.Errorf(codes.Unimplemented, "method Upload not implemented")
+}
+func (UnimplementedFileTransferServer) Download(*FileDownloadRequest, FileTransfer_DownloadServer) error {
+	return status.Errorf(codes.Unimplemented, "method Download not implemented")
+}
+func (UnimplementedFileTransferServer) mustEmbedUnimplementedFileTransferServer() {}
+
+// UnsafeFileTransferServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to FileTransferServer will
+// result in compilation errors.
+type UnsafeFileTransferServer interface {
+	mustEmbedUnimplementedFileTransferServer()
+}
+
+func RegisterFileTransferServer(s grpc.ServiceRegistrar, srv FileTransferServer) {
+	s.RegisterService(&FileTransfer_ServiceDesc, srv)
+}
+
+func _FileTransfer_Upload_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(FileTransferServer).Upload(&fileTransferUploadServer{stream})
+}
+
+type FileTransfer_UploadServer interface {
+	SendAndClose(*FileUploadResponse) error
+	Recv() (*FileUpload, error)
+	grpc.ServerStream
+}
+
+type fileTransferUploadServer struct {
+	grpc.ServerStream
+}
+
+func (x *fileTransferUploadServer) SendAndClose(m *FileUploadResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *fileTransferUploadServer) Recv() (*FileUpload, error) {
+	m := new(FileUpload)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _FileTransfer_Download_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(FileDownloadRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(FileTransferServer).Download(m, &fileTransferDownloadServer{stream})
+}
+
+type FileTransfer_DownloadServer interface {
+	Send(*FileChunk) error
+	grpc.ServerStream
+}
+
+type fileTransferDownloadServer struct {
+	grpc.ServerStream
+}
+
+func (x *fileTransferDownloadServer) Send(m *FileChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// FileTransfer_ServiceDesc is the grpc.ServiceDesc for FileTransfer service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var FileTransfer_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "tailsys.FileTransfer",
+	HandlerType: (*FileTransferServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Upload",
+			Handler:       _FileTransfer_Upload_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "Download",
+			Handler:       _FileTransfer_Download_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "transfer.proto",
+}
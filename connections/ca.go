@@ -0,0 +1,305 @@
+package connections
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Default lifetimes of the certificates issued by the ca
+const (
+	caLifetime           = time.Hour * 87660 //Ten years
+	SystemCertLifetime   = time.Hour * 24 * 30
+	OperatorCertLifetime = time.Hour * 24 * 365
+	crlLifetime          = time.Hour * 24 * 7
+)
+
+// CA is the certificate authority run by the coordination server. Every certificate it issues is kept under
+// issued/ so it can be revoked by name later, revoked certificates are published in crl.pem.
+type CA struct {
+	dir  string
+	mu   sync.Mutex
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  string
+}
+
+// LoadCA loads the ca from dir, creating a new one the first time
+func LoadCA(dir string) (*CA, error) {
+	ca := &CA{dir: dir}
+	certPem, err := os.ReadFile(filepath.Join(dir, "ca.crt"))
+	if errors.Is(err, os.ErrNotExist) {
+		return ca, ca.create()
+	}
+	if err != nil {
+		return nil, err
+	}
+	keyPem, err := os.ReadFile(filepath.Join(dir, "ca.key"))
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(certPem)
+	if block == nil {
+		return nil, fmt.Errorf("invalid ca certificate in %s", dir)
+	}
+	ca.cert, err = x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	block, _ = pem.Decode(keyPem)
+	if block == nil {
+		return nil, fmt.Errorf("invalid ca key in %s", dir)
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	ecKey, ok := key.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("unsupported ca key type %T", key)
+	}
+	ca.key = ecKey
+	ca.pem = string(certPem)
+	return ca, nil
+}
+
+func (ca *CA) create() error {
+	slog.Info("no certificate authority found, creating one", "path", ca.dir)
+	if err := os.MkdirAll(filepath.Join(ca.dir, "issued"), 0700); err != nil {
+		return err
+	}
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+	serial, err := serialNumber()
+	if err != nil {
+		return err
+	}
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: "tailsys ca", Organization: []string{"tailsys"}},
+		NotBefore:             time.Now().Add(-5 * time.Minute),
+		NotAfter:              time.Now().Add(caLifetime),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		return err
+	}
+	ca.cert, err = x509.ParseCertificate(der)
+	if err != nil {
+		return err
+	}
+	ca.key = key
+	ca.pem = string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+
+	keyDer, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(ca.dir, "ca.key"), pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDer}), 0600); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(ca.dir, "ca.crt"), []byte(ca.pem), 0644); err != nil {
+		return err
+	}
+	return ca.writeCRL(nil)
+}
+
+// Bundle returns the ca certificate that systems need to trust
+func (ca *CA) Bundle() string {
+	return ca.pem
+}
+
+// Sign issues a certificate for the key in the csr. The certificate is only valid for name, whatever the csr asks for.
+func (ca *CA) Sign(csrDer []byte, name, role string, lifetime time.Duration) (string, error) {
+	csr, err := x509.ParseCertificateRequest(csrDer)
+	if err != nil {
+		return "", fmt.Errorf("invalid certificate request: %w", err)
+	}
+	if err := csr.CheckSignature(); err != nil {
+		return "", fmt.Errorf("invalid certificate request signature: %w", err)
+	}
+	if csr.Subject.CommonName != name {
+		return "", fmt.Errorf("certificate request is for %s, not %s", csr.Subject.CommonName, name)
+	}
+
+	serial, err := serialNumber()
+	if err != nil {
+		return "", err
+	}
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: name, OrganizationalUnit: []string{role}, Organization: []string{"tailsys"}},
+		DNSNames:     []string{name},
+		NotBefore:    time.Now().Add(-5 * time.Minute),
+		NotAfter:     time.Now().Add(lifetime),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+
+	ca.mu.Lock()
+	defer ca.mu.Unlock()
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, csr.PublicKey, ca.key)
+	if err != nil {
+		return "", err
+	}
+	certPem := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	if err := os.WriteFile(filepath.Join(ca.dir, "issued", serial.String()+".pem"), certPem, 0644); err != nil {
+		return "", fmt.Errorf("unable to record issued certificate: %w", err)
+	}
+	slog.Info("issued certificate", "role", role, "serial", serial.String(), "name", name)
+	return string(certPem), nil
+}
+
+// Issue creates a new key and a certificate for it, for systems that can't create their own csr like the cli
+func (ca *CA) Issue(name, role string, lifetime time.Duration) (*TLSConfig, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject: pkix.Name{CommonName: name},
+	}, key)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := ca.Sign(csr, name, role, lifetime)
+	if err != nil {
+		return nil, err
+	}
+	keyDer, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	crl, err := ca.CRL()
+	if err != nil {
+		return nil, err
+	}
+	return &TLSConfig{
+		TLSKey:  string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDer})),
+		TLSCert: cert,
+		CA:      ca.pem,
+		CRL:     string(crl),
+	}, nil
+}
+
+// CRL returns the current list of revoked certificates
+func (ca *CA) CRL() ([]byte, error) {
+	return os.ReadFile(filepath.Join(ca.dir, "crl.pem"))
+}
+
+// RevokeName revokes every unexpired certificate issued to name for role, or for any role when role is empty,
+// and returns how many were revoked
+func (ca *CA) RevokeName(name, role string) (int, error) {
+	return ca.revoke(func(cert *x509.Certificate) bool {
+		return cert.Subject.CommonName == name && (role == "" || CertificateRole(cert) == role)
+	})
+}
+
+// RevokeSerial revokes the certificate with the serial number
+func (ca *CA) RevokeSerial(serial string) (int, error) {
+	return ca.revoke(func(cert *x509.Certificate) bool {
+		return cert.SerialNumber.String() == serial
+	})
+}
+
+func (ca *CA) revoke(match func(cert *x509.Certificate) bool) (int, error) {
+	ca.mu.Lock()
+	defer ca.mu.Unlock()
+
+	current, err := ca.revocations()
+	if err != nil {
+		return 0, err
+	}
+	revoked := make(map[string]struct{}, len(current))
+	for _, entry := range current {
+		revoked[entry.SerialNumber.String()] = struct{}{}
+	}
+
+	files, err := filepath.Glob(filepath.Join(ca.dir, "issued", "*.pem"))
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return count, err
+		}
+		block, _ := pem.Decode(data)
+		if block == nil {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return count, err
+		}
+		if _, ok := revoked[cert.SerialNumber.String()]; ok || time.Now().After(cert.NotAfter) || !match(cert) {
+			continue
+		}
+		current = append(current, x509.RevocationListEntry{
+			SerialNumber:   cert.SerialNumber,
+			RevocationTime: time.Now().UTC(),
+		})
+		slog.Info("revoked certificate", "serial", cert.SerialNumber.String(), "name", cert.Subject.CommonName)
+		count++
+	}
+	if count == 0 {
+		return 0, nil
+	}
+	return count, ca.writeCRL(current)
+}
+
+// revocations returns the entries in the current crl
+func (ca *CA) revocations() ([]x509.RevocationListEntry, error) {
+	data, err := ca.CRL()
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("invalid crl")
+	}
+	list, err := x509.ParseRevocationList(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	return list.RevokedCertificateEntries, nil
+}
+
+func (ca *CA) writeCRL(entries []x509.RevocationListEntry) error {
+	number, err := serialNumber()
+	if err != nil {
+		return err
+	}
+	der, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+		Number:                    number,
+		ThisUpdate:                time.Now(),
+		NextUpdate:                time.Now().Add(crlLifetime),
+		RevokedCertificateEntries: entries,
+	}, ca.cert, ca.key)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(ca.dir, "crl.pem"), pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: der}), 0644)
+}
+
+func serialNumber() (*big.Int, error) {
+	return rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+}
@@ -2,18 +2,14 @@ package connections
 
 import (
 	"context"
-	"crypto/ecdsa"
-	"crypto/elliptic"
-	"crypto/rand"
 	"crypto/tls"
-	"crypto/x509"
-	"encoding/pem"
 	"errors"
 	"fmt"
-	"math/big"
+	"log/slog"
 	"net"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/tailscale/tailscale-client-go/tailscale"
@@ -51,11 +47,16 @@ type Tailnet struct {
 	Listener       net.Listener
 	TailnetLogging bool
 	authType       AuthType
-}
-
-type TLSConfig struct {
-	TLSKey  string `yaml:"key"`
-	TLSCert string `yaml:"cert"`
+	tlsMu          sync.RWMutex
+	revoked        map[string]struct{}
+	methodRoles    MethodRoles
+	authorizer     Authorizer
+	deviceLister   DeviceLister
+	noListener     bool
+	drainTimeout   time.Duration
+	log            *slog.Logger
+	metricsAddr    string
+	metricsTailnet bool
 }
 
 // Option function to set different options on the tailnet config
@@ -70,7 +71,7 @@ func (tn *Tailnet) Connect(ctx context.Context, opts ...Option) error {
 		}
 	}
 
-	if err := tn.generateKeyPair(); err != nil {
+	if err := tn.generateKey(); err != nil {
 		return err
 	}
 
@@ -91,7 +92,7 @@ func (tn *Tailnet) Connect(ctx context.Context, opts ...Option) error {
 		Hostname:  tn.Hostname,
 		AuthKey:   tn.AuthKey,
 		Ephemeral: true,
-		Logf: nil, //func(string, ...any) {},
+		Logf:      tn.tsnetLogf,
 	}
 	tn.TSServer = srv
   tn.authType = tn.getAuthType()
@@ -130,7 +131,7 @@ func (tn *Tailnet) ConnectCmd(ctx context.Context, opts ...Option) error {
 		Hostname:  tn.Hostname,
 		AuthKey:   tn.AuthKey,
 		Ephemeral: true,
-		// Logf: func(string, ...any) {},
+		Logf:      tn.tsnetLogf,
 	}
 	tn.TSServer = srv
   tn.authType = tn.getAuthType()
@@ -138,30 +139,22 @@ func (tn *Tailnet) ConnectCmd(ctx context.Context, opts ...Option) error {
 	return nil
 }
 
-func (tn *Tailnet) DialContext(ctx context.Context, addr string, certs *TLSConfig) (*grpc.ClientConn, error) {
+// DialContext connects to the gRPC server at addr, trusting only servers with a certificate from our ca
+func (tn *Tailnet) DialContext(ctx context.Context, addr string) (*grpc.ClientConn, error) {
 	//Plain dialer if not on tsnet
 	//Pass in a cancelable context
-	pool := x509.NewCertPool()
-	pool.AppendCertsFromPEM([]byte(certs.TLSCert))
-	pair, err := tls.X509KeyPair([]byte(certs.TLSCert), []byte(certs.TLSKey))
+	tc, err := tn.dialCredentials()
 	if err != nil {
 		return nil, err
 	}
 
-	tc := credentials.NewTLS(&tls.Config{
-		Certificates: []tls.Certificate{pair},
-		ClientAuth:   tls.RequireAndVerifyClientCert,
-		ClientCAs:    pool,
-		RootCAs:      pool,
-	})
-
 	if tn.authType == NONE {
-    fmt.Printf("dialing %s with no tailscale configuration\n", addr)
+		tn.Logger().Debug("dialing without tailscale", "addr", addr)
 		conn, err := grpc.DialContext(ctx, addr, grpc.WithTransportCredentials(tc))
 		return conn, err
 	}
 
-  fmt.Printf("dialing %s with tailscale configuration\n", addr)
+	tn.Logger().Debug("dialing through tailscale", "addr", addr)
 	return grpc.DialContext(ctx, addr,
 		grpc.WithTransportCredentials(tc),
 		grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
@@ -181,7 +174,7 @@ func (tn *Tailnet) initClient(ctx context.Context) error {
 	topts = append(topts, tailscale.WithKeyExpiry(10*time.Second))
 
 	if tn.authType == OAUTH {
-		fmt.Println("connecting with oauth")
+		tn.Logger().Info("connecting to the tailnet with oauth")
 		client, err := tailscale.NewClient(
 			"",
 			"-",
@@ -199,7 +192,7 @@ func (tn *Tailnet) initClient(ctx context.Context) error {
 		tn.reapDeviceID(ctx)
 		return nil
 	} else if tn.authType == AUTHKEY {
-		fmt.Println("connecting with authkey")
+		tn.Logger().Info("connecting to the tailnet with an auth key")
 		client, err := tailscale.NewClient(tn.AuthKey, "-")
 		if err != nil {
 			return err
@@ -212,14 +205,13 @@ func (tn *Tailnet) initClient(ctx context.Context) error {
 
 func (tn *Tailnet) reapDeviceID(ctx context.Context) error {
 	devices, err := tn.Client.Devices(ctx)
-	fmt.Printf("FOUND %d DEVICES\n", len(devices))
 	if err != nil {
 		return err
 	}
+	tn.Logger().Debug("listed tailnet devices", "count", len(devices))
 	for _, device := range devices {
-		fmt.Println(device.Hostname)
 		if device.Hostname == tn.Hostname {
-			fmt.Printf("found device %s with same name %s\n\n", device.Hostname, tn.Hostname)
+			tn.Logger().Info("deleting stale device with our hostname", "device", device.ID, "hostname", device.Hostname)
 			err := tn.Client.DeleteDevice(ctx, device.ID)
 			if err != nil {
 				return err
@@ -230,49 +222,6 @@ func (tn *Tailnet) reapDeviceID(ctx context.Context) error {
 	return nil
 }
 
-func (tn *Tailnet) generateKeyPair() error {
-	if !tn.checkForKeys() {
-		fmt.Println("no keys found, generating new keys")
-		priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
-		if err != nil {
-			return err
-		}
-		privDer, err := x509.MarshalPKCS8PrivateKey(priv)
-		if err != nil {
-			return err
-		}
-		privPem := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privDer})
-
-		template := &x509.Certificate{
-			SerialNumber: new(big.Int),
-			NotAfter:     time.Now().Add(time.Hour * 87660), //Ten years
-			DNSNames:     []string{tn.Hostname},
-		}
-
-		certDer, err := x509.CreateCertificate(rand.Reader, template, template, priv.Public(), priv)
-		if err != nil {
-			return err
-		}
-		certPem := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDer})
-		tc := TLSConfig{}
-		tc.TLSCert = string(certPem)
-		tc.TLSKey = string(privPem)
-
-		d, err := yaml.Marshal(&tc)
-		if err != nil {
-			return err
-		}
-		err = os.MkdirAll(tn.ConfigDir+"/certs", 0750)
-		err = os.WriteFile(tn.ConfigDir+"/certs/certs.yaml", d, 0640)
-		if err != nil {
-			return err
-		}
-		tn.TLSConfig = &tc
-
-	}
-	return nil
-}
-
 func (tn *Tailnet) checkForKeys() bool {
 	tc := TLSConfig{}
 	c, err := os.ReadFile(tn.ConfigDir + "/certs/certs.yaml")
@@ -289,9 +238,44 @@ func (tn *Tailnet) checkForKeys() bool {
 
 // GetDevices returns a list of devices that are connected to the configured tailnet
 func (tn *Tailnet) GetDevices(ctx context.Context) ([]tailscale.Device, error) {
+	if tn.deviceLister != nil {
+		return tn.deviceLister.Devices(ctx)
+	}
+	if tn.Client == nil {
+		return nil, errors.New("no tailscale api client to list devices with")
+	}
 	return tn.Client.Devices(ctx)
 }
 
+// CanListDevices is true when GetDevices has something to list the devices with
+func (tn *Tailnet) CanListDevices() bool {
+	return tn.deviceLister != nil || tn.Client != nil
+}
+
+// TailnetAddresses returns the tailscale ips and tags of this system, both are empty when not on a tailnet
+func (tn *Tailnet) TailnetAddresses(ctx context.Context) ([]string, []string, error) {
+	if tn.authType == NONE || tn.TSServer == nil {
+		return nil, nil, nil
+	}
+	lc, err := tn.TSServer.LocalClient()
+	if err != nil {
+		return nil, nil, err
+	}
+	st, err := lc.StatusWithoutPeers(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	ips := make([]string, 0, len(st.TailscaleIPs))
+	for _, ip := range st.TailscaleIPs {
+		ips = append(ips, ip.String())
+	}
+	var tags []string
+	if st.Self != nil && st.Self.Tags != nil {
+		tags = st.Self.Tags.AsSlice()
+	}
+	return ips, tags, nil
+}
+
 // WithOauth sets up the tailnet connection using an oauth credential
 func (tn *Tailnet) WithOauth(clientId, clientSecret string) Option {
 	return func(tn *Tailnet) error {
@@ -358,6 +342,30 @@ func (tn *Tailnet) WithHostname(hostname string) Option {
 	}
 }
 
+// WithLogger sets the logger, slog.Default() is used without one
+func (tn *Tailnet) WithLogger(log *slog.Logger) Option {
+	return func(tn *Tailnet) error {
+		tn.log = log
+		return nil
+	}
+}
+
+// Logger returns the logger everything running on this tailnet connection logs to
+func (tn *Tailnet) Logger() *slog.Logger {
+	if tn.log == nil {
+		return slog.Default()
+	}
+	return tn.log
+}
+
+// tsnetLogf sends the logs of tsnet to our logger at debug level when tailnet logging is enabled
+func (tn *Tailnet) tsnetLogf(format string, args ...any) {
+	if !tn.TailnetLogging {
+		return
+	}
+	tn.Logger().Debug(strings.TrimSpace(fmt.Sprintf(format, args...)), "component", "tsnet")
+}
+
 // WithTailnetLogging Enable/Disable logging on the tailnet
 func (tn *Tailnet) WithTailnetLogging(enabled bool) Option {
 	return func(tn *Tailnet) error {
@@ -377,7 +385,7 @@ func (tn *Tailnet) WithPort(port string) Option {
 func (tn *Tailnet) WithConfigDir(dir string) Option {
 	return func(tn *Tailnet) error {
 		tn.ConfigDir = dir
-    fmt.Println("set config dir to: ", tn.ConfigDir)
+		tn.Logger().Debug("set config dir", "path", tn.ConfigDir)
 		return nil
 	}
 }
@@ -389,6 +397,10 @@ func (tn *Tailnet) createRPCServer() error {
 		if err := tn.TSServer.Start(); err != nil {
 			return err
 		}
+	}
+	if tn.noListener {
+		tn.Logger().Info("not listening for inbound connections")
+	} else if tn.authType != NONE {
 		if tn.Port == "" {
 			tn.Port = "6655"
 		}
@@ -408,20 +420,19 @@ func (tn *Tailnet) createRPCServer() error {
 		tn.Listener = ln
 	}
 
-	pool := x509.NewCertPool()
-	pool.AppendCertsFromPEM([]byte(tn.TLSConfig.TLSCert))
-	pair, err := tls.X509KeyPair([]byte(tn.TLSConfig.TLSCert), []byte(tn.TLSConfig.TLSKey))
-	if err != nil {
-		return err
-	}
+	//certificates are looked up on each handshake so they can be issued and rotated while the server is running
 	tc := credentials.NewTLS(&tls.Config{
-		Certificates: []tls.Certificate{pair},
-		ClientAuth:   tls.RequireAndVerifyClientCert,
-		ClientCAs:    pool,
-		RootCAs:      pool,
+		GetConfigForClient: tn.serverConfig,
 	})
 
-	s := grpc.NewServer(grpc.Creds(tc))
+	s := grpc.NewServer(
+		grpc.Creds(tc),
+		grpc.ChainUnaryInterceptor(tn.methodRoles.unaryInterceptor, tn.authorizeUnary),
+		grpc.ChainStreamInterceptor(tn.methodRoles.streamInterceptor, tn.authorizeStream),
+		//requests canceled on shutdown still get to clean up, a node kills the commands it was running
+		grpc.WaitForHandlers(true),
+		grpc.StatsHandler(connStats{}),
+	)
 	tn.GRPCServer = s
 
 	return nil
@@ -430,15 +441,15 @@ func (tn *Tailnet) createRPCServer() error {
 // getAuthType Determine the type of auth to connect to the tailnet
 func (tn *Tailnet) getAuthType() AuthType {
 	if tn.ClientID != "" && tn.ClientSecret != "" {
-    fmt.Println("auth type is OAUTH")
+		tn.Logger().Debug("auth type is oauth")
 		return OAUTH
 	}
 
 	if tn.AuthKey != "" {
-    fmt.Println("auth type is AUTHKEY")
+		tn.Logger().Debug("auth type is auth key")
 		return AUTHKEY
 
 	}
-  fmt.Println("auth type is NONE")
+	tn.Logger().Debug("auth type is none, not using tailscale")
 	return NONE
 }
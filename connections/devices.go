@@ -0,0 +1,96 @@
+package connections
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/tailscale/tailscale-client-go/tailscale"
+)
+
+// DeviceLister lists the devices of the tailnet, tailscale.Client is the real one
+type DeviceLister interface {
+	Devices(ctx context.Context) ([]tailscale.Device, error)
+}
+
+// DeviceFile lists devices from a JSON file in the format of the tailscale devices api, a stand-in for the api
+// when there is no tailnet to ask
+type DeviceFile string
+
+// Devices reads the devices from the file every time so it can be edited while running
+func (f DeviceFile) Devices(ctx context.Context) ([]tailscale.Device, error) {
+	data, err := os.ReadFile(string(f))
+	if err != nil {
+		return nil, err
+	}
+	res := struct {
+		Devices []tailscale.Device `json:"devices"`
+	}{}
+	if err := json.Unmarshal(data, &res); err != nil {
+		return nil, fmt.Errorf("invalid device file %s: %w", f, err)
+	}
+	return res.Devices, nil
+}
+
+// WithDeviceLister lists the tailnet devices with lister instead of the tailscale api
+func (tn *Tailnet) WithDeviceLister(lister DeviceLister) Option {
+	return func(tn *Tailnet) error {
+		tn.deviceLister = lister
+		return nil
+	}
+}
+
+// TailnetPeer is what the tailnet says about a device, unlike the inventory a node sends it can't be made up by the node
+type TailnetPeer struct {
+	Addresses []string
+	Tags      []string
+}
+
+// TailnetPeers returns the devices on the tailnet by their lower case hostname and the first label of their MagicDNS
+// name, which differ when tailscale had to make the name unique. Devices come from the device lister when there is
+// one, there are none without a tailnet.
+func (tn *Tailnet) TailnetPeers(ctx context.Context) (map[string]*TailnetPeer, error) {
+	peers := make(map[string]*TailnetPeer)
+	add := func(peer *TailnetPeer, names ...string) {
+		for _, name := range names {
+			name, _, _ = strings.Cut(name, ".")
+			if name != "" {
+				peers[strings.ToLower(name)] = peer
+			}
+		}
+	}
+	if tn.deviceLister != nil {
+		devices, err := tn.deviceLister.Devices(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, device := range devices {
+			add(&TailnetPeer{Addresses: device.Addresses, Tags: device.Tags}, device.Hostname, device.Name)
+		}
+		return peers, nil
+	}
+	if tn.authType == NONE || tn.TSServer == nil {
+		return peers, nil
+	}
+	lc, err := tn.TSServer.LocalClient()
+	if err != nil {
+		return nil, err
+	}
+	st, err := lc.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, ps := range st.Peer {
+		peer := &TailnetPeer{}
+		for _, ip := range ps.TailscaleIPs {
+			peer.Addresses = append(peer.Addresses, ip.String())
+		}
+		if ps.Tags != nil {
+			peer.Tags = ps.Tags.AsSlice()
+		}
+		add(peer, ps.HostName, ps.DNSName)
+	}
+	return peers, nil
+}
@@ -0,0 +1,143 @@
+package connections
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+)
+
+// Identity is who is on the other end of a gRPC call
+type Identity struct {
+	// LoginName is the tailscale user that owns the calling node, empty for tagged nodes
+	LoginName string
+	// Node is the name of the calling node on the tailnet
+	Node string
+	// Tags are the tailscale tags of the calling node
+	Tags []string
+}
+
+// String is how the caller is shown in logs and the job history
+func (id *Identity) String() string {
+	if id.LoginName != "" && id.LoginName != id.Node {
+		return fmt.Sprintf("%s (%s)", id.LoginName, id.Node)
+	}
+	if id.Node == "" {
+		return id.LoginName
+	}
+	return id.Node
+}
+
+// Authorizer decides whether the caller in ctx may call method with the request. Streaming methods are authorized
+// when their request is received, before the handler sees it.
+type Authorizer func(ctx context.Context, method string, req any) error
+
+type identityKey struct{}
+
+// IdentityFromContext returns the identity of the caller resolved by the gRPC server, nil when it couldn't be resolved
+func IdentityFromContext(ctx context.Context) *Identity {
+	id, _ := ctx.Value(identityKey{}).(*Identity)
+	return id
+}
+
+// ContextWithIdentity attaches the identity of the caller to ctx
+func ContextWithIdentity(ctx context.Context, id *Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, id)
+}
+
+// WithAuthorizer resolves the identity of every caller of the gRPC server and checks it with authorize
+func (tn *Tailnet) WithAuthorizer(authorize Authorizer) Option {
+	return func(tn *Tailnet) error {
+		tn.authorizer = authorize
+		return nil
+	}
+}
+
+// WhoIs looks up the caller of a gRPC call on the tailnet. Without a tailnet there is nobody to ask, so the
+// caller is identified by the name in its client certificate instead. Calls through a tunnel come from the server
+// the tunnel was dialed to, they are identified by certificate too.
+func (tn *Tailnet) WhoIs(ctx context.Context) (*Identity, error) {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return nil, errors.New("caller has no address")
+	}
+	if _, tunneled := p.Addr.(tunnelAddr); tn.authType == NONE || tunneled {
+		cert := PeerCertificate(ctx)
+		if cert == nil {
+			return nil, errors.New("caller has no client certificate")
+		}
+		return &Identity{LoginName: cert.Subject.CommonName, Node: cert.Subject.CommonName}, nil
+	}
+
+	lc, err := tn.TSServer.LocalClient()
+	if err != nil {
+		return nil, err
+	}
+	who, err := lc.WhoIs(ctx, p.Addr.String())
+	if err != nil {
+		return nil, fmt.Errorf("unable to look up %s on the tailnet: %w", p.Addr, err)
+	}
+	id := &Identity{}
+	if who.Node != nil {
+		id.Node = who.Node.ComputedName
+		id.Tags = who.Node.Tags
+		if !who.Node.IsTagged() && who.UserProfile != nil {
+			id.LoginName = who.UserProfile.LoginName
+		}
+	}
+	return id, nil
+}
+
+// identify attaches the identity of the caller to ctx, calls that can't be identified are left for the authorizer to turn away
+func (tn *Tailnet) identify(ctx context.Context, method string) context.Context {
+	id, err := tn.WhoIs(ctx)
+	if err != nil {
+		tn.Logger().Warn("unable to identify caller", "method", method, "err", err)
+		return ctx
+	}
+	return ContextWithIdentity(ctx, id)
+}
+
+func (tn *Tailnet) authorizeUnary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if tn.authorizer == nil {
+		return handler(ctx, req)
+	}
+	ctx = tn.identify(ctx, info.FullMethod)
+	if err := tn.authorizer(ctx, info.FullMethod, req); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (tn *Tailnet) authorizeStream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if tn.authorizer == nil {
+		return handler(srv, ss)
+	}
+	return handler(srv, &authorizedStream{
+		ServerStream: ss,
+		ctx:          tn.identify(ss.Context(), info.FullMethod),
+		method:       info.FullMethod,
+		authorize:    tn.authorizer,
+	})
+}
+
+// authorizedStream authorizes every request received on the stream and hands the identity to the handler
+type authorizedStream struct {
+	grpc.ServerStream
+	ctx       context.Context
+	method    string
+	authorize Authorizer
+}
+
+func (s *authorizedStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *authorizedStream) RecvMsg(m any) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	return s.authorize(s.ctx, s.method, m)
+}
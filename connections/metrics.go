@@ -0,0 +1,104 @@
+package connections
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/charles-d-burton/tailsys/metrics"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	grpcConnections = metrics.NewGaugeVec("tailsys_grpc_connections",
+		"gRPC connections open to this server.")
+	grpcRequests = metrics.NewCounterVec("tailsys_grpc_requests_total",
+		"gRPC requests handled by this server, by method and status code.", "method", "code")
+)
+
+// WithMetrics serves the metrics over http on addr, like localhost:9100. On the tailnet only the port of addr is used
+// and the metrics are only reachable by the devices of the tailnet.
+func (tn *Tailnet) WithMetrics(addr string, onTailnet bool) Option {
+	return func(tn *Tailnet) error {
+		tn.metricsAddr = addr
+		tn.metricsTailnet = onTailnet
+		return nil
+	}
+}
+
+// ServeMetrics serves the metrics at /metrics in the background until ctx is done, it does nothing when there is no
+// metrics address
+func (tn *Tailnet) ServeMetrics(ctx context.Context) error {
+	if tn.metricsAddr == "" {
+		return nil
+	}
+	var ln net.Listener
+	var err error
+	if tn.metricsTailnet {
+		if tn.TSServer == nil || tn.authType == NONE {
+			return errors.New("metrics can only be served on the tailnet when connected to one")
+		}
+		_, port, perr := net.SplitHostPort(tn.metricsAddr)
+		if perr != nil {
+			return perr
+		}
+		ln, err = tn.TSServer.Listen("tcp", ":"+port)
+	} else {
+		ln, err = net.Listen("tcp", tn.metricsAddr)
+	}
+	if err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Default.Handler())
+	srv := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		srv.Close()
+	}()
+	go func() {
+		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			tn.Logger().Error("metrics server stopped", "error", err)
+		}
+	}()
+	tn.Logger().Info("serving metrics", "addr", ln.Addr().String(), "tailnet", tn.metricsTailnet)
+	return nil
+}
+
+// connStats counts the open connections and the finished requests of the gRPC server
+type connStats struct{}
+
+type methodKey struct{}
+
+func (connStats) TagRPC(ctx context.Context, info *stats.RPCTagInfo) context.Context {
+	return context.WithValue(ctx, methodKey{}, info.FullMethodName)
+}
+
+func (connStats) HandleRPC(ctx context.Context, s stats.RPCStats) {
+	end, ok := s.(*stats.End)
+	if !ok {
+		return
+	}
+	method, _ := ctx.Value(methodKey{}).(string)
+	grpcRequests.With(method, status.Code(end.Error).String()).Inc()
+}
+
+func (connStats) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context {
+	return ctx
+}
+
+func (connStats) HandleConn(_ context.Context, s stats.ConnStats) {
+	switch s.(type) {
+	case *stats.ConnBegin:
+		grpcConnections.With().Inc()
+	case *stats.ConnEnd:
+		grpcConnections.With().Dec()
+	}
+}
@@ -0,0 +1,75 @@
+package connections
+
+import (
+	"context"
+	"net"
+	"time"
+)
+
+// DefaultShutdownTimeout is how long running requests get to finish on shutdown before they are canceled
+const DefaultShutdownTimeout = 30 * time.Second
+
+// logoutTimeout is how long logging out of the tailnet may take on shutdown
+const logoutTimeout = 5 * time.Second
+
+// WithShutdownTimeout sets how long running requests get to finish on shutdown before they are canceled
+func (tn *Tailnet) WithShutdownTimeout(d time.Duration) Option {
+	return func(tn *Tailnet) error {
+		tn.drainTimeout = d
+		return nil
+	}
+}
+
+// ShutdownTimeout is how long running requests get to finish on shutdown
+func (tn *Tailnet) ShutdownTimeout() time.Duration {
+	if tn.drainTimeout <= 0 {
+		return DefaultShutdownTimeout
+	}
+	return tn.drainTimeout
+}
+
+// Serve serves the gRPC server on lis until ctx is done. New requests are refused from then on and the running ones
+// get the shutdown timeout to finish, after that they are canceled and Serve returns once their handlers returned.
+func (tn *Tailnet) Serve(ctx context.Context, lis net.Listener) error {
+	errc := make(chan error, 1)
+	go func() {
+		errc <- tn.GRPCServer.Serve(lis)
+	}()
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+
+	timeout := tn.ShutdownTimeout()
+	tn.Logger().Info("shutting down, waiting for running requests", "timeout", timeout)
+	stopped := make(chan struct{})
+	go func() {
+		tn.GRPCServer.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(timeout):
+		tn.Logger().Warn("requests still running after the shutdown timeout, canceling them")
+		tn.GRPCServer.Stop()
+		<-stopped
+	}
+	return <-errc
+}
+
+// Close leaves the tailnet. The node is ephemeral so it is logged out first, that removes the device right away
+// instead of leaving it behind until the control server notices it is gone.
+func (tn *Tailnet) Close() error {
+	if tn.authType == NONE || tn.TSServer == nil {
+		return nil
+	}
+	if lc, err := tn.TSServer.LocalClient(); err == nil {
+		ctx, cancel := context.WithTimeout(context.Background(), logoutTimeout)
+		defer cancel()
+		if err := lc.Logout(ctx); err != nil {
+			tn.Logger().Error("unable to log out of the tailnet", "err", err)
+		}
+	}
+	return tn.TSServer.Close()
+}
@@ -0,0 +1,330 @@
+package connections
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"gopkg.in/yaml.v3"
+)
+
+// TLSConfig is the key and certificate a system identifies itself with, along with the ca it trusts
+// and the certificates that ca has revoked
+type TLSConfig struct {
+	TLSKey  string `yaml:"key"`
+	TLSCert string `yaml:"cert,omitempty"`
+	CA      string `yaml:"ca,omitempty"`
+	CRL     string `yaml:"crl,omitempty"`
+}
+
+// Roles a certificate can be issued for, the role is stored as the organizational unit of the certificate
+const (
+	RoleCoordinator = "coordinator"
+	RoleNode        = "node"
+	RoleOperator    = "operator"
+	// RolePublic marks a method that can be called without a certificate
+	RolePublic = "public"
+)
+
+// MethodRoles maps a full gRPC method name, a service prefix like /tailsys.CommandManager/ or "" for every other
+// method to the roles allowed to call it. Methods with no match can be called with any valid certificate.
+type MethodRoles map[string][]string
+
+func (mr MethodRoles) roles(method string) []string {
+	if roles, ok := mr[method]; ok {
+		return roles
+	}
+	if i := strings.LastIndex(method, "/"); i > 0 {
+		if roles, ok := mr[method[:i+1]]; ok {
+			return roles
+		}
+	}
+	return mr[""]
+}
+
+func (mr MethodRoles) authorize(ctx context.Context, method string) error {
+	roles := mr.roles(method)
+	for _, role := range roles {
+		if role == RolePublic {
+			return nil
+		}
+	}
+	cert := PeerCertificate(ctx)
+	if cert == nil {
+		return status.Errorf(codes.Unauthenticated, "%s requires a client certificate", method)
+	}
+	if len(roles) == 0 {
+		return nil
+	}
+	for _, role := range roles {
+		if CertificateRole(cert) == role {
+			return nil
+		}
+	}
+	return status.Errorf(codes.PermissionDenied, "%s can't be called by a %s certificate", method, CertificateRole(cert))
+}
+
+func (mr MethodRoles) unaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if err := mr.authorize(ctx, info.FullMethod); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (mr MethodRoles) streamInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := mr.authorize(ss.Context(), info.FullMethod); err != nil {
+		return err
+	}
+	return handler(srv, ss)
+}
+
+// PeerCertificate returns the verified certificate the caller connected with, if any
+func PeerCertificate(ctx context.Context) *x509.Certificate {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(info.State.VerifiedChains) == 0 || len(info.State.VerifiedChains[0]) == 0 {
+		return nil
+	}
+	return info.State.VerifiedChains[0][0]
+}
+
+// CertificateRole returns the role the certificate was issued for
+func CertificateRole(cert *x509.Certificate) string {
+	if len(cert.Subject.OrganizationalUnit) == 0 {
+		return ""
+	}
+	return cert.Subject.OrganizationalUnit[0]
+}
+
+// WithMethodRoles restricts which certificate roles can call each method on the gRPC server
+func (tn *Tailnet) WithMethodRoles(roles MethodRoles) Option {
+	return func(tn *Tailnet) error {
+		tn.methodRoles = roles
+		return nil
+	}
+}
+
+// tlsState returns a copy of the current TLS configuration, it can be replaced at any time by a renewal
+func (tn *Tailnet) tlsState() TLSConfig {
+	tn.tlsMu.RLock()
+	defer tn.tlsMu.RUnlock()
+	if tn.TLSConfig == nil {
+		return TLSConfig{}
+	}
+	return *tn.TLSConfig
+}
+
+// caPool returns the pool of certificates trusted to sign the certificate of the other side of a connection
+func (tc TLSConfig) caPool() (*x509.CertPool, error) {
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM([]byte(tc.CA)) {
+		return nil, errors.New("no certificate authority configured")
+	}
+	return pool, nil
+}
+
+// serverConfig is resolved on every handshake so renewed certificates and crls are picked up right away.
+// Clients without a certificate are let through here and turned away by the method roles.
+func (tn *Tailnet) serverConfig(*tls.ClientHelloInfo) (*tls.Config, error) {
+	tc := tn.tlsState()
+	pair, err := tls.X509KeyPair([]byte(tc.TLSCert), []byte(tc.TLSKey))
+	if err != nil {
+		return nil, fmt.Errorf("no usable server certificate: %w", err)
+	}
+	pool, err := tc.caPool()
+	if err != nil {
+		return nil, err
+	}
+	return &tls.Config{
+		Certificates:          []tls.Certificate{pair},
+		ClientAuth:            tls.VerifyClientCertIfGiven,
+		ClientCAs:             pool,
+		MinVersion:            tls.VersionTLS12,
+		VerifyPeerCertificate: tn.checkRevoked,
+	}, nil
+}
+
+// dialCredentials trusts the configured ca and presents our certificate when we have one
+func (tn *Tailnet) dialCredentials() (credentials.TransportCredentials, error) {
+	tc := tn.tlsState()
+	pool, err := tc.caPool()
+	if err != nil {
+		return nil, err
+	}
+	return credentials.NewTLS(&tls.Config{
+		RootCAs:    pool,
+		MinVersion: tls.VersionTLS12,
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			tc := tn.tlsState()
+			if tc.TLSCert == "" {
+				//not registered yet, only public methods can be called
+				return &tls.Certificate{}, nil
+			}
+			pair, err := tls.X509KeyPair([]byte(tc.TLSCert), []byte(tc.TLSKey))
+			if err != nil {
+				return nil, err
+			}
+			return &pair, nil
+		},
+		VerifyPeerCertificate: tn.checkRevoked,
+	}), nil
+}
+
+// checkRevoked fails the handshake if the verified certificate of the other side is on the crl
+func (tn *Tailnet) checkRevoked(rawCerts [][]byte, chains [][]*x509.Certificate) error {
+	tn.tlsMu.RLock()
+	defer tn.tlsMu.RUnlock()
+	for _, chain := range chains {
+		for _, cert := range chain {
+			if _, ok := tn.revoked[cert.SerialNumber.String()]; ok {
+				return fmt.Errorf("certificate %s for %s has been revoked", cert.SerialNumber, cert.Subject.CommonName)
+			}
+		}
+	}
+	return nil
+}
+
+// generateKey creates the private key this system identifies itself with, it never leaves the system
+func (tn *Tailnet) generateKey() error {
+	if tn.checkForKeys() && tn.TLSConfig.TLSKey != "" {
+		return tn.SetCRL([]byte(tn.TLSConfig.CRL))
+	}
+	tn.Logger().Info("no keys found, generating new keys")
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+	privDer, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		return err
+	}
+	tn.TLSConfig = &TLSConfig{
+		TLSKey: string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privDer})),
+	}
+	return tn.saveKeys()
+}
+
+func (tn *Tailnet) saveKeys() error {
+	d, err := yaml.Marshal(tn.TLSConfig)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(tn.ConfigDir+"/certs", 0750); err != nil {
+		return err
+	}
+	return os.WriteFile(tn.ConfigDir+"/certs/certs.yaml", d, 0600)
+}
+
+// CertificateRequest creates a csr for our key to be signed by the coordination server
+func (tn *Tailnet) CertificateRequest() ([]byte, error) {
+	tc := tn.tlsState()
+	block, _ := pem.Decode([]byte(tc.TLSKey))
+	if block == nil {
+		return nil, errors.New("no private key to create a certificate request with")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	return x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject:  pkix.Name{CommonName: tn.Hostname},
+		DNSNames: []string{tn.Hostname},
+	}, key)
+}
+
+// SetCertificate replaces our certificate, the ca bundle and the crl and saves them. Empty values keep what is
+// already configured, so a crl refresh doesn't need a new certificate.
+func (tn *Tailnet) SetCertificate(cert, ca string, crl []byte) error {
+	tn.tlsMu.Lock()
+	tc := *tn.TLSConfig
+	if cert != "" {
+		if _, err := tls.X509KeyPair([]byte(cert), []byte(tc.TLSKey)); err != nil {
+			tn.tlsMu.Unlock()
+			return fmt.Errorf("certificate does not match our key: %w", err)
+		}
+		tc.TLSCert = cert
+	}
+	if ca != "" {
+		tc.CA = ca
+	}
+	tn.TLSConfig = &tc
+	tn.tlsMu.Unlock()
+
+	if len(crl) > 0 {
+		if err := tn.SetCRL(crl); err != nil {
+			return err
+		}
+	}
+	tn.tlsMu.RLock()
+	defer tn.tlsMu.RUnlock()
+	return tn.saveKeys()
+}
+
+// SetCRL replaces the list of revoked certificates, the crl must be signed by the configured ca
+func (tn *Tailnet) SetCRL(crl []byte) error {
+	if len(crl) == 0 {
+		return nil
+	}
+	der := crl
+	if block, _ := pem.Decode(crl); block != nil {
+		der = block.Bytes
+	}
+	list, err := x509.ParseRevocationList(der)
+	if err != nil {
+		return fmt.Errorf("unable to parse crl: %w", err)
+	}
+
+	tn.tlsMu.Lock()
+	defer tn.tlsMu.Unlock()
+	if block, _ := pem.Decode([]byte(tn.TLSConfig.CA)); block != nil {
+		ca, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return err
+		}
+		if err := list.CheckSignatureFrom(ca); err != nil {
+			return fmt.Errorf("crl is not signed by our ca: %w", err)
+		}
+	}
+	revoked := make(map[string]struct{}, len(list.RevokedCertificateEntries))
+	for _, entry := range list.RevokedCertificateEntries {
+		revoked[entry.SerialNumber.String()] = struct{}{}
+	}
+	tn.revoked = revoked
+	tc := *tn.TLSConfig
+	tc.CRL = string(pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: der}))
+	tn.TLSConfig = &tc
+	return nil
+}
+
+// CertificateExpiring reports whether we have no certificate or it has less than a third of its lifetime left
+func (tn *Tailnet) CertificateExpiring() bool {
+	tc := tn.tlsState()
+	block, _ := pem.Decode([]byte(tc.TLSCert))
+	if block == nil {
+		return true
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return true
+	}
+	lifetime := cert.NotAfter.Sub(cert.NotBefore)
+	return time.Until(cert.NotAfter) < lifetime/3
+}
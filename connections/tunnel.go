@@ -0,0 +1,218 @@
+package connections
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// TunnelConn is a net.Conn carried over the frames of a gRPC stream, it lets a gRPC connection run in the opposite
+// direction of the stream. Bytes written are handed to send, bytes received on the stream are passed to Deliver.
+type TunnelConn struct {
+	send   func([]byte) error
+	local  net.Addr
+	remote net.Addr
+
+	mu       sync.Mutex
+	cond     *sync.Cond
+	buf      []byte
+	err      error
+	deadline time.Time
+	timer    *time.Timer
+}
+
+// NewTunnelConn creates a connection that writes with send, local and remote are the addresses of the stream
+func NewTunnelConn(send func([]byte) error, local, remote net.Addr) *TunnelConn {
+	c := &TunnelConn{send: send, local: local, remote: remote}
+	c.cond = sync.NewCond(&c.mu)
+	return c
+}
+
+// Deliver queues bytes received on the stream to be read
+func (c *TunnelConn) Deliver(data []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err != nil {
+		return
+	}
+	c.buf = append(c.buf, data...)
+	c.cond.Broadcast()
+}
+
+// CloseWithError closes the connection, reads return err once everything delivered was read
+func (c *TunnelConn) CloseWithError(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err == nil {
+		c.err = err
+	}
+	if c.timer != nil {
+		c.timer.Stop()
+	}
+	c.cond.Broadcast()
+}
+
+func (c *TunnelConn) Read(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for len(c.buf) == 0 {
+		if c.err != nil {
+			return 0, c.err
+		}
+		if !c.deadline.IsZero() && !time.Now().Before(c.deadline) {
+			return 0, os.ErrDeadlineExceeded
+		}
+		c.cond.Wait()
+	}
+	n := copy(p, c.buf)
+	c.buf = c.buf[n:]
+	return n, nil
+}
+
+func (c *TunnelConn) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	err := c.err
+	c.mu.Unlock()
+	if err != nil {
+		return 0, err
+	}
+	//the stream may hold on to the slice after send returns
+	data := make([]byte, len(p))
+	copy(data, p)
+	if err := c.send(data); err != nil {
+		c.CloseWithError(err)
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (c *TunnelConn) Close() error {
+	c.CloseWithError(net.ErrClosed)
+	return nil
+}
+
+func (c *TunnelConn) LocalAddr() net.Addr  { return c.local }
+func (c *TunnelConn) RemoteAddr() net.Addr { return c.remote }
+
+func (c *TunnelConn) SetDeadline(t time.Time) error {
+	return c.SetReadDeadline(t)
+}
+
+// SetReadDeadline wakes up blocked reads at t. Writes go straight to the stream so they have no deadline.
+func (c *TunnelConn) SetReadDeadline(t time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.deadline = t
+	if c.timer != nil {
+		c.timer.Stop()
+		c.timer = nil
+	}
+	if !t.IsZero() {
+		c.timer = time.AfterFunc(time.Until(t), func() {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			c.cond.Broadcast()
+		})
+	}
+	c.cond.Broadcast()
+	return nil
+}
+
+func (c *TunnelConn) SetWriteDeadline(t time.Time) error {
+	return nil
+}
+
+// TunnelListener hands out the connections of tunnels so a gRPC server can serve them
+type TunnelListener struct {
+	addr   net.Addr
+	conns  chan net.Conn
+	closed chan struct{}
+	once   sync.Once
+}
+
+// NewTunnelListener creates a listener reporting addr as its address
+func NewTunnelListener(addr net.Addr) *TunnelListener {
+	return &TunnelListener{
+		addr:   addr,
+		conns:  make(chan net.Conn),
+		closed: make(chan struct{}),
+	}
+}
+
+// Push hands the connection to the server, it fails once the listener is closed
+func (l *TunnelListener) Push(conn net.Conn) error {
+	select {
+	case l.conns <- conn:
+		return nil
+	case <-l.closed:
+		return net.ErrClosed
+	}
+}
+
+func (l *TunnelListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.conns:
+		return conn, nil
+	case <-l.closed:
+		return nil, net.ErrClosed
+	}
+}
+
+func (l *TunnelListener) Close() error {
+	l.once.Do(func() { close(l.closed) })
+	return nil
+}
+
+func (l *TunnelListener) Addr() net.Addr { return l.addr }
+
+// tunnelAddr is the address of a tunnel whose stream has no address of its own
+type tunnelAddr string
+
+func (a tunnelAddr) Network() string { return "tunnel" }
+func (a tunnelAddr) String() string  { return string(a) }
+
+// TunnelAddr returns addr, or a placeholder named name when the stream didn't have one
+func TunnelAddr(addr net.Addr, name string) net.Addr {
+	if addr != nil {
+		return addr
+	}
+	return tunnelAddr(name)
+}
+
+// ErrTunnelClosed is returned when gRPC tries to dial a tunnel again after its connection was lost
+var ErrTunnelClosed = errors.New("tunnel closed")
+
+// DialTunnel starts a gRPC connection to hostname over conn, the certificate of the other side has to be for
+// hostname just like when dialing it directly. The connection can't be redialed once conn is closed.
+func (tn *Tailnet) DialTunnel(ctx context.Context, hostname string, conn net.Conn) (*grpc.ClientConn, error) {
+	tc, err := tn.dialCredentials()
+	if err != nil {
+		return nil, err
+	}
+	var once sync.Once
+	return grpc.DialContext(ctx, hostname,
+		grpc.WithTransportCredentials(tc),
+		grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
+			var c net.Conn
+			once.Do(func() { c = conn })
+			if c == nil {
+				return nil, ErrTunnelClosed
+			}
+			return c, nil
+		}),
+	)
+}
+
+// WithInboundListener turns the listener of the gRPC server off, a node that reaches the coordination server through
+// a tunnel doesn't need one
+func (tn *Tailnet) WithInboundListener(enabled bool) Option {
+	return func(tn *Tailnet) error {
+		tn.noListener = !enabled
+		return nil
+	}
+}
@@ -0,0 +1,177 @@
+package queries
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Statuses for jobs and the hosts they run on
+const (
+	JobRunning  = "running"
+	JobFinished = "finished"
+	JobCanceled = "canceled"
+
+	HostPending   = "pending"
+	HostRunning   = "running"
+	HostSucceeded = "succeeded"
+	HostFailed    = "failed"
+	HostCanceled  = "canceled"
+)
+
+const (
+	InsertJobQuery = `INSERT INTO command_jobs (job_id, requester, pattern, command, request, created, status) VALUES(?,?,?,?,?,?,?)`
+	GetJobQuery    = `SELECT job_id,requester,pattern,command,request,created,status,finished FROM command_jobs WHERE job_id=?`
+	GetJobsQuery   = `SELECT j.job_id,j.requester,j.pattern,j.command,j.created,j.status,j.finished,
+	COUNT(r.id),
+	COUNT(CASE WHEN r.status='failed' THEN 1 END)
+	FROM command_jobs j LEFT JOIN command_records r ON r.job_id=j.job_id
+	GROUP BY j.job_id ORDER BY j.created DESC LIMIT ?`
+	FinishJobQuery = `UPDATE command_jobs SET status=?, finished=? WHERE job_id=?`
+
+	InsertCommandRecordQuery = `INSERT INTO command_records (job_id, hostname, status) VALUES(?,?,'pending')`
+	StartCommandRecordQuery  = `UPDATE command_records SET status='running', started=? WHERE id=?`
+	FinishCommandRecordQuery = `UPDATE command_records SET status=?, success=?, exit_code=?, stdout=?, stderr=?, error=?, signal=?, reason=?, duration_ms=?, finished=? WHERE id=?`
+	GetCommandRecordsQuery   = `SELECT id,job_id,hostname,status,success,exit_code,stdout,stderr,error,signal,reason,duration_ms,started,finished FROM command_records WHERE job_id=? ORDER BY hostname`
+	GetJobHostsQuery         = `SELECT hostname FROM command_records WHERE job_id=? ORDER BY hostname`
+
+	AbandonJobsQuery    = `UPDATE command_jobs SET status='canceled', finished=? WHERE status='running'`
+	AbandonRecordsQuery = `UPDATE command_records SET status='canceled', error='coordination server restarted', finished=? WHERE status IN ('pending','running')`
+)
+
+// CommandJobRow is a single command dispatched to the nodes matching a pattern
+type CommandJobRow struct {
+	JobID     string
+	Requester string
+	Pattern   string
+	Command   string
+	Request   []byte
+	Created   time.Time
+	Status    string
+	Finished  sql.NullTime
+	Hosts     int
+	Failed    int
+}
+
+// CommandRecordRow is the result of a job on a single host
+type CommandRecordRow struct {
+	ID       int64
+	JobID    string
+	Hostname string
+	Status   string
+	Success  sql.NullBool
+	ExitCode sql.NullInt32
+	Stdout   []byte
+	Stderr   []byte
+	Error    sql.NullString
+	Signal   string
+	Reason   string
+	Duration sql.NullInt64 //milliseconds
+	Started  sql.NullTime
+	Finished sql.NullTime
+}
+
+func InsertJob(db *sql.DB, row *CommandJobRow) error {
+	_, err := db.Exec(InsertJobQuery, row.JobID, row.Requester, row.Pattern, row.Command, row.Request, row.Created, JobRunning)
+	return err
+}
+
+func GetJob(db *sql.DB, jobID string) (*CommandJobRow, error) {
+	r := CommandJobRow{}
+	row := db.QueryRow(GetJobQuery, jobID)
+	err := row.Scan(&r.JobID, &r.Requester, &r.Pattern, &r.Command, &r.Request, &r.Created, &r.Status, &r.Finished)
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// GetJobs returns the most recent jobs first along with how many hosts they ran on and how many of those failed
+func GetJobs(db *sql.DB, limit int) ([]*CommandJobRow, error) {
+	rows, err := db.Query(GetJobsQuery, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	jobs := make([]*CommandJobRow, 0)
+	for rows.Next() {
+		r := CommandJobRow{}
+		if err := rows.Scan(&r.JobID, &r.Requester, &r.Pattern, &r.Command, &r.Created, &r.Status, &r.Finished, &r.Hosts, &r.Failed); err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, &r)
+	}
+	return jobs, rows.Err()
+}
+
+func FinishJob(db *sql.DB, jobID, status string, finished time.Time) error {
+	_, err := db.Exec(FinishJobQuery, status, finished, jobID)
+	return err
+}
+
+// InsertCommandRecord records the host as pending for the job and returns the id of the record
+func InsertCommandRecord(db *sql.DB, jobID, hostname string) (int64, error) {
+	res, err := db.Exec(InsertCommandRecordQuery, jobID, hostname)
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
+}
+
+// StartCommandRecord marks the host as running the job
+func StartCommandRecord(db *sql.DB, id int64, started time.Time) error {
+	_, err := db.Exec(StartCommandRecordQuery, started, id)
+	return err
+}
+
+func FinishCommandRecord(db *sql.DB, row *CommandRecordRow) error {
+	_, err := db.Exec(FinishCommandRecordQuery, row.Status, row.Success, row.ExitCode, row.Stdout, row.Stderr, row.Error, row.Signal, row.Reason, row.Duration, row.Finished, row.ID)
+	return err
+}
+
+// AbandonJobs cancels every job and host that was still in progress, they can't finish once the server that ran them is gone
+func AbandonJobs(db *sql.DB, now time.Time) error {
+	if _, err := db.Exec(AbandonRecordsQuery, now); err != nil {
+		return err
+	}
+	_, err := db.Exec(AbandonJobsQuery, now)
+	return err
+}
+
+func GetCommandRecords(db *sql.DB, jobID string) ([]*CommandRecordRow, error) {
+	rows, err := db.Query(GetCommandRecordsQuery, jobID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	records := make([]*CommandRecordRow, 0)
+	for rows.Next() {
+		r := CommandRecordRow{}
+		err := rows.Scan(&r.ID, &r.JobID, &r.Hostname, &r.Status, &r.Success, &r.ExitCode, &r.Stdout, &r.Stderr, &r.Error, &r.Signal, &r.Reason, &r.Duration, &r.Started, &r.Finished)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, &r)
+	}
+	return records, rows.Err()
+}
+
+// GetJobHosts returns the hosts the job was sent to
+func GetJobHosts(db *sql.DB, jobID string) ([]string, error) {
+	rows, err := db.Query(GetJobHostsQuery, jobID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	hosts := make([]string, 0)
+	for rows.Next() {
+		var host string
+		if err := rows.Scan(&host); err != nil {
+			return nil, err
+		}
+		hosts = append(hosts, host)
+	}
+	return hosts, rows.Err()
+}
@@ -0,0 +1,91 @@
+package queries
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+)
+
+// States of a discovered device, see DiscoveredDeviceRow
+const (
+	DeviceUnregistered = "unregistered"
+	DeviceRegistered   = "registered"
+	DeviceMissing      = "missing"
+)
+
+const (
+	UpsertDiscoveredDeviceQuery = `INSERT INTO discovered_devices (hostname, device_id, name, addresses, tags, os, client_version, last_seen, state,
+	first_seen, checked) VALUES(?,?,?,?,?,?,?,?,?,?,?) ON CONFLICT(hostname) DO UPDATE SET device_id=excluded.device_id, name=excluded.name,
+	addresses=excluded.addresses, tags=excluded.tags, os=excluded.os, client_version=excluded.client_version, last_seen=excluded.last_seen,
+	first_seen=CASE WHEN state=excluded.state THEN first_seen ELSE excluded.first_seen END, state=excluded.state, checked=excluded.checked`
+	GetDiscoveredDevicesQuery   = `SELECT hostname,device_id,name,addresses,tags,os,client_version,last_seen,state,first_seen,checked FROM discovered_devices ORDER BY hostname`
+	DeleteStaleDevicesQuery     = `DELETE FROM discovered_devices WHERE checked<?`
+	DeleteDiscoveredDeviceQuery = `DELETE FROM discovered_devices WHERE hostname=?`
+)
+
+// DiscoveredDeviceRow is what discovery found out about a node. Unregistered devices carry a discovery tag but never
+// registered, missing ones registered but are gone from the tailnet. FirstSeen is when the device entered its state.
+type DiscoveredDeviceRow struct {
+	Hostname      string
+	DeviceID      string
+	Name          string
+	Addresses     []string
+	Tags          []string
+	OS            string
+	ClientVersion string
+	LastSeen      sql.NullTime
+	State         string
+	FirstSeen     time.Time
+	Checked       time.Time
+}
+
+// SetDiscoveredDevices replaces the result of the previous discovery with rows, a device keeps its FirstSeen as
+// long as its state doesn't change
+func SetDiscoveredDevices(db *sql.DB, rows []*DiscoveredDeviceRow, checked time.Time) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, r := range rows {
+		_, err := tx.Exec(UpsertDiscoveredDeviceQuery, r.Hostname, r.DeviceID, r.Name, strings.Join(r.Addresses, ","), strings.Join(r.Tags, ","),
+			r.OS, r.ClientVersion, r.LastSeen, r.State, checked, checked)
+		if err != nil {
+			return err
+		}
+	}
+	if _, err := tx.Exec(DeleteStaleDevicesQuery, checked); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetDiscoveredDevices returns the result of the last discovery
+func GetDiscoveredDevices(db *sql.DB) ([]*DiscoveredDeviceRow, error) {
+	rows, err := db.Query(GetDiscoveredDevicesQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	devices := make([]*DiscoveredDeviceRow, 0)
+	for rows.Next() {
+		r := DiscoveredDeviceRow{}
+		var addrs, tags string
+		err := rows.Scan(&r.Hostname, &r.DeviceID, &r.Name, &addrs, &tags, &r.OS, &r.ClientVersion, &r.LastSeen, &r.State, &r.FirstSeen, &r.Checked)
+		if err != nil {
+			return nil, err
+		}
+		r.Addresses = splitList(addrs)
+		r.Tags = splitList(tags)
+		devices = append(devices, &r)
+	}
+	return devices, rows.Err()
+}
+
+// DeleteDiscoveredDevice forgets the device until it is discovered again
+func DeleteDiscoveredDevice(db *sql.DB, hostname string) error {
+	_, err := db.Exec(DeleteDiscoveredDeviceQuery, hostname)
+	return err
+}
@@ -0,0 +1,166 @@
+package queries
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Health of a node as decided by pinging it, unknown until it answers the first time
+const (
+	HealthUnknown  = "unknown"
+	HealthOnline   = "online"
+	HealthDegraded = "degraded"
+	HealthOffline  = "offline"
+)
+
+const (
+	SetNodeHealthQuery = `REPLACE INTO node_health (hostname, status, since, latency_ms, last_seen, last_checked, next_probe, failures, error)
+	VALUES(?,?,?,?,?,?,?,?,?)`
+	GetNodeHealthQuery     = `SELECT hostname,status,since,latency_ms,last_seen,last_checked,next_probe,failures,error FROM node_health WHERE hostname=?`
+	GetAllNodeHealthQuery  = `SELECT hostname,status,since,latency_ms,last_seen,last_checked,next_probe,failures,error FROM node_health`
+	DeleteNodeHealthQuery  = `DELETE FROM node_health WHERE hostname=?`
+	CountNodeHealthQuery   = `SELECT status,COUNT(*) FROM node_health GROUP BY status`
+	InsertPingQuery        = `INSERT INTO node_pings (hostname, pinged, successful, latency_ms, error) VALUES(?,?,?,?,?)`
+	GetPingsQuery          = `SELECT hostname,pinged,successful,latency_ms,error FROM node_pings WHERE hostname=? ORDER BY pinged DESC LIMIT ?`
+	DeletePingsBeforeQuery = `DELETE FROM node_pings WHERE pinged<?`
+	DeletePingsQuery       = `DELETE FROM node_pings WHERE hostname=?`
+)
+
+// NodeHealthRow is the current health of a node. Since is when it changed to its status, NextProbe is when the node
+// is due to be pinged again which backs off while it is offline.
+type NodeHealthRow struct {
+	Hostname    string
+	Status      string
+	Since       time.Time
+	LatencyMs   float64
+	LastSeen    sql.NullTime
+	LastChecked time.Time
+	NextProbe   time.Time
+	Failures    int
+	Error       string
+}
+
+// PingRow is a single ping of a node
+type PingRow struct {
+	Hostname   string
+	Pinged     time.Time
+	Successful bool
+	LatencyMs  float64
+	Error      string
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanNodeHealth(s scanner) (*NodeHealthRow, error) {
+	r := NodeHealthRow{}
+	err := s.Scan(&r.Hostname, &r.Status, &r.Since, &r.LatencyMs, &r.LastSeen, &r.LastChecked, &r.NextProbe, &r.Failures, &r.Error)
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// SetNodeHealth stores the health of the node along with the ping that decided it
+func SetNodeHealth(db *sql.DB, row *NodeHealthRow, ping *PingRow) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(SetNodeHealthQuery, row.Hostname, row.Status, row.Since, row.LatencyMs, row.LastSeen, row.LastChecked, row.NextProbe,
+		row.Failures, row.Error)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(InsertPingQuery, ping.Hostname, ping.Pinged, ping.Successful, ping.LatencyMs, ping.Error); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetNodeHealth returns the health of the node, sql.ErrNoRows if it was never pinged
+func GetNodeHealth(db *sql.DB, hostname string) (*NodeHealthRow, error) {
+	return scanNodeHealth(db.QueryRow(GetNodeHealthQuery, hostname))
+}
+
+// GetAllNodeHealth returns the health of every node that was pinged by hostname
+func GetAllNodeHealth(db *sql.DB) (map[string]*NodeHealthRow, error) {
+	rows, err := db.Query(GetAllNodeHealthQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make(map[string]*NodeHealthRow)
+	for rows.Next() {
+		r, err := scanNodeHealth(rows)
+		if err != nil {
+			return nil, err
+		}
+		res[r.Hostname] = r
+	}
+	return res, rows.Err()
+}
+
+// CountNodesByHealth returns how many pinged nodes there are with each health status
+func CountNodesByHealth(db *sql.DB) (map[string]int, error) {
+	return countBy(db, CountNodeHealthQuery)
+}
+
+// countBy reads the rows of a query grouping a count by one column
+func countBy(db *sql.DB, query string) (map[string]int, error) {
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make(map[string]int)
+	for rows.Next() {
+		var key string
+		var n int
+		if err := rows.Scan(&key, &n); err != nil {
+			return nil, err
+		}
+		res[key] = n
+	}
+	return res, rows.Err()
+}
+
+// GetPings returns the most recent pings of the node, newest first
+func GetPings(db *sql.DB, hostname string, limit int) ([]*PingRow, error) {
+	rows, err := db.Query(GetPingsQuery, hostname, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make([]*PingRow, 0)
+	for rows.Next() {
+		r := PingRow{}
+		if err := rows.Scan(&r.Hostname, &r.Pinged, &r.Successful, &r.LatencyMs, &r.Error); err != nil {
+			return nil, err
+		}
+		res = append(res, &r)
+	}
+	return res, rows.Err()
+}
+
+// DeletePingsBefore drops the ping history older than t
+func DeletePingsBefore(db *sql.DB, t time.Time) error {
+	_, err := db.Exec(DeletePingsBeforeQuery, t)
+	return err
+}
+
+// DeleteNodeHealth forgets the health and ping history of the node
+func DeleteNodeHealth(db *sql.DB, hostname string) error {
+	for _, q := range []string{DeletePingsQuery, DeleteNodeHealthQuery} {
+		if _, err := db.Exec(q, hostname); err != nil {
+			return err
+		}
+	}
+	return nil
+}
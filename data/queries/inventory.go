@@ -0,0 +1,205 @@
+package queries
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+)
+
+const (
+	InsertInventoryQuery = `REPLACE INTO node_inventory (hostname, os, distro, distro_version, distro_name, kernel, arch, cpu_count, cpu_model,
+	memory_total, memory_available, uptime_seconds, tailscale_ips, tailscale_tags, tailsys_version, ip, collected) VALUES(?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)`
+	GetInventoryQuery = `SELECT hostname,os,distro,distro_version,distro_name,kernel,arch,cpu_count,cpu_model,
+	memory_total,memory_available,uptime_seconds,tailscale_ips,tailscale_tags,tailsys_version,ip,collected FROM node_inventory WHERE hostname=?`
+	GetInventoriesQuery = `SELECT hostname,os,distro,distro_version,distro_name,kernel,arch,cpu_count,cpu_model,
+	memory_total,memory_available,uptime_seconds,tailscale_ips,tailscale_tags,tailsys_version,ip,collected FROM node_inventory`
+	DeleteInventoryQuery = `DELETE FROM node_inventory WHERE hostname=?`
+
+	InsertDiskQuery  = `INSERT INTO node_disks (hostname, device, mountpoint, fstype, total_bytes, free_bytes) VALUES(?,?,?,?,?,?)`
+	GetDisksQuery    = `SELECT device,mountpoint,fstype,total_bytes,free_bytes FROM node_disks WHERE hostname=? ORDER BY mountpoint`
+	DeleteDisksQuery = `DELETE FROM node_disks WHERE hostname=?`
+
+	InsertInterfaceQuery  = `INSERT INTO node_interfaces (hostname, name, mac, addresses, mtu, up) VALUES(?,?,?,?,?,?)`
+	GetInterfacesQuery    = `SELECT name,mac,addresses,mtu,up FROM node_interfaces WHERE hostname=? ORDER BY name`
+	GetAllInterfacesQuery = `SELECT hostname,name,mac,addresses,mtu,up FROM node_interfaces ORDER BY hostname,name`
+	DeleteInterfacesQuery = `DELETE FROM node_interfaces WHERE hostname=?`
+)
+
+// InventoryRow is the last inventory a node reported. Lists are stored comma separated so they can still be searched with LIKE.
+type InventoryRow struct {
+	Hostname        string
+	OS              string
+	Distro          string
+	DistroVersion   string
+	DistroName      string
+	Kernel          string
+	Arch            string
+	CPUCount        int32
+	CPUModel        string
+	MemoryTotal     uint64
+	MemoryAvailable uint64
+	UptimeSeconds   int64
+	TailscaleIPs    []string
+	TailscaleTags   []string
+	TailsysVersion  string
+	IP              string
+	Collected       time.Time
+	Disks           []*DiskRow
+	Interfaces      []*InterfaceRow
+}
+
+type DiskRow struct {
+	Device     string
+	Mountpoint string
+	Fstype     string
+	TotalBytes uint64
+	FreeBytes  uint64
+}
+
+type InterfaceRow struct {
+	Name      string
+	MAC       string
+	Addresses []string
+	MTU       int32
+	Up        bool
+}
+
+// SetInventory replaces the inventory of the node along with its disks and interfaces
+func SetInventory(db *sql.DB, row *InventoryRow) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(InsertInventoryQuery, row.Hostname, row.OS, row.Distro, row.DistroVersion, row.DistroName, row.Kernel, row.Arch,
+		row.CPUCount, row.CPUModel, row.MemoryTotal, row.MemoryAvailable, row.UptimeSeconds,
+		strings.Join(row.TailscaleIPs, ","), strings.Join(row.TailscaleTags, ","), row.TailsysVersion, row.IP, row.Collected)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(DeleteDisksQuery, row.Hostname); err != nil {
+		return err
+	}
+	for _, d := range row.Disks {
+		if _, err := tx.Exec(InsertDiskQuery, row.Hostname, d.Device, d.Mountpoint, d.Fstype, d.TotalBytes, d.FreeBytes); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.Exec(DeleteInterfacesQuery, row.Hostname); err != nil {
+		return err
+	}
+	for _, i := range row.Interfaces {
+		if _, err := tx.Exec(InsertInterfaceQuery, row.Hostname, i.Name, i.MAC, strings.Join(i.Addresses, ","), i.MTU, i.Up); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// GetInventory returns the last inventory of the node, sql.ErrNoRows if it never reported one
+func GetInventory(db *sql.DB, hostname string) (*InventoryRow, error) {
+	r := InventoryRow{}
+	var ips, tags string
+	err := db.QueryRow(GetInventoryQuery, hostname).Scan(&r.Hostname, &r.OS, &r.Distro, &r.DistroVersion, &r.DistroName, &r.Kernel, &r.Arch,
+		&r.CPUCount, &r.CPUModel, &r.MemoryTotal, &r.MemoryAvailable, &r.UptimeSeconds, &ips, &tags, &r.TailsysVersion, &r.IP, &r.Collected)
+	if err != nil {
+		return nil, err
+	}
+	r.TailscaleIPs = splitList(ips)
+	r.TailscaleTags = splitList(tags)
+
+	rows, err := db.Query(GetDisksQuery, hostname)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		d := DiskRow{}
+		if err := rows.Scan(&d.Device, &d.Mountpoint, &d.Fstype, &d.TotalBytes, &d.FreeBytes); err != nil {
+			return nil, err
+		}
+		r.Disks = append(r.Disks, &d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	irows, err := db.Query(GetInterfacesQuery, hostname)
+	if err != nil {
+		return nil, err
+	}
+	defer irows.Close()
+	for irows.Next() {
+		i := InterfaceRow{}
+		var addrs string
+		if err := irows.Scan(&i.Name, &i.MAC, &addrs, &i.MTU, &i.Up); err != nil {
+			return nil, err
+		}
+		i.Addresses = splitList(addrs)
+		r.Interfaces = append(r.Interfaces, &i)
+	}
+	return &r, irows.Err()
+}
+
+// GetInventories returns the last inventory of every node that reported one by hostname, along with its interfaces
+// but without its disks
+func GetInventories(db *sql.DB) (map[string]*InventoryRow, error) {
+	rows, err := db.Query(GetInventoriesQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	invs := make(map[string]*InventoryRow)
+	for rows.Next() {
+		r := InventoryRow{}
+		var ips, tags string
+		err := rows.Scan(&r.Hostname, &r.OS, &r.Distro, &r.DistroVersion, &r.DistroName, &r.Kernel, &r.Arch,
+			&r.CPUCount, &r.CPUModel, &r.MemoryTotal, &r.MemoryAvailable, &r.UptimeSeconds, &ips, &tags, &r.TailsysVersion, &r.IP, &r.Collected)
+		if err != nil {
+			return nil, err
+		}
+		r.TailscaleIPs = splitList(ips)
+		r.TailscaleTags = splitList(tags)
+		invs[r.Hostname] = &r
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	irows, err := db.Query(GetAllInterfacesQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer irows.Close()
+	for irows.Next() {
+		i := InterfaceRow{}
+		var hostname, addrs string
+		if err := irows.Scan(&hostname, &i.Name, &i.MAC, &addrs, &i.MTU, &i.Up); err != nil {
+			return nil, err
+		}
+		i.Addresses = splitList(addrs)
+		if r, ok := invs[hostname]; ok {
+			r.Interfaces = append(r.Interfaces, &i)
+		}
+	}
+	return invs, irows.Err()
+}
+
+// DeleteInventory forgets everything the node reported
+func DeleteInventory(db *sql.DB, hostname string) error {
+	for _, q := range []string{DeleteInterfacesQuery, DeleteDisksQuery, DeleteInventoryQuery} {
+		if _, err := db.Exec(q, hostname); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func splitList(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
@@ -2,54 +2,62 @@ package queries
 
 import (
 	"database/sql"
-	"fmt"
-	"regexp"
+	"log/slog"
 )
 
+// Statuses a node moves through from registering to being allowed to receive commands
 const (
-	GetHostsQuery   = `SELECT hostname,key_id,proto FROM node_registration`
-	GetHostQuery    = `SELECT hostname,key_id,proto FROM node_registration WHERE hostname=?`
-	UpdateHostQuery = `UPDATE node_registration SET proto=? WHERE hostname=?`
-	InsertHostQuery = `REPLACE INTO node_registration VALUES(?,?,?);`
+	NodePending  = "pending"
+	NodeAccepted = "accepted"
+	NodeRejected = "rejected"
+	NodeRevoked  = "revoked"
+)
 
-	GetServerQuery    = `SELECT hostname, key FROM server_registration WHERE key=?`
-	InsertServerQuery = `REPLACE INTO server_registration VALUES(?,?)`
+const (
+	GetHostsQuery         = `SELECT hostname,key_id,proto,status,fingerprint FROM node_registration`
+	GetAcceptedHostsQuery = `SELECT hostname,key_id,proto,status,fingerprint FROM node_registration WHERE status='accepted'`
+	GetHostQuery          = `SELECT hostname,key_id,proto,status,fingerprint FROM node_registration WHERE hostname=?`
+	UpdateHostQuery       = `UPDATE node_registration SET proto=? WHERE hostname=?`
+	InsertHostQuery       = `REPLACE INTO node_registration (hostname, key_id, proto, status, fingerprint) VALUES(?,?,?,?,?);`
+	SetHostStatusQuery    = `UPDATE node_registration SET status=? WHERE hostname=?`
+	DeleteHostQuery       = `DELETE FROM node_registration WHERE hostname=?`
+	CountHostsQuery       = `SELECT status,COUNT(*) FROM node_registration GROUP BY status`
+
+	GetServerQuery           = `SELECT hostname,key_id,signing_key FROM server_registration WHERE key_id=?`
+	GetServerByHostnameQuery = `SELECT hostname,key_id,signing_key FROM server_registration WHERE hostname=?`
+	InsertServerQuery        = `REPLACE INTO server_registration (hostname, key_id, signing_key) VALUES(?,?,?)`
 )
 
 type RegisteredHostsData struct {
-	Hostname string
-	Key      string
-	Data     []byte
+	Hostname    string
+	Key         string
+	Data        []byte
+	Status      string
+	Fingerprint string
 }
 
-func GetMatchRegisteredHosts(db *sql.DB, pattern string) (chan *RegisteredHostsData, error) {
-	rchan := make(chan *RegisteredHostsData, 1000)
-
-	re, err := regexp.CompilePOSIX(pattern)
+// GetAcceptedHosts returns the hosts that may be sent commands
+func GetAcceptedHosts(db *sql.DB) ([]*RegisteredHostsData, error) {
+	rows, err := db.Query(GetAcceptedHostsQuery)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
-	go func(db *sql.DB, re *regexp.Regexp, rchan chan *RegisteredHostsData) {
-		defer close(rchan)
-		rows, err := db.Query(GetHostsQuery)
-		if err != nil {
-			fmt.Println(fmt.Errorf("problem getting hosts %w", err))
-		}
-		defer rows.Close()
-		for rows.Next() {
-			r := RegisteredHostsData{}
-			err := rows.Scan(&r.Hostname, &r.Key, &r.Data)
-			if err != nil {
-				fmt.Println(fmt.Errorf("problem getting hosts %w", err))
-			}
-      fmt.Println("found a row: ", r.Hostname)
-			if re.MatchString(r.Hostname) {
-				rchan <- &r
-			}
+	hosts := make([]*RegisteredHostsData, 0)
+	for rows.Next() {
+		r := RegisteredHostsData{}
+		if err := rows.Scan(&r.Hostname, &r.Key, &r.Data, &r.Status, &r.Fingerprint); err != nil {
+			return nil, err
 		}
-	}(db, re, rchan)
-	return rchan, nil
+		hosts = append(hosts, &r)
+	}
+	return hosts, rows.Err()
+}
+
+// CountHostsByStatus returns how many nodes there are with each registration status
+func CountHostsByStatus(db *sql.DB) (map[string]int, error) {
+	return countBy(db, CountHostsQuery)
 }
 
 func GetRegisteredHosts(db *sql.DB) chan *RegisteredHostsData {
@@ -58,16 +66,16 @@ func GetRegisteredHosts(db *sql.DB) chan *RegisteredHostsData {
 	go func(db *sql.DB, rchan chan *RegisteredHostsData) {
 		defer close(rchan)
 		rows, err := db.Query(GetHostsQuery)
-		defer rows.Close()
 		if err != nil {
-			fmt.Println(fmt.Errorf("unable to execute query: %w\n", err))
+			slog.Error("unable to list registered hosts", "err", err)
 			return
 		}
+		defer rows.Close()
 		for rows.Next() {
 			r := RegisteredHostsData{}
-			err := rows.Scan(&r.Hostname, &r.Key, &r.Data)
+			err := rows.Scan(&r.Hostname, &r.Key, &r.Data, &r.Status, &r.Fingerprint)
 			if err != nil {
-				fmt.Println(fmt.Errorf("error loading row: %w\n", err))
+				slog.Error("unable to load registered host", "err", err)
 			}
 			rchan <- &r
 		}
@@ -80,7 +88,7 @@ func GetRegisteredHosts(db *sql.DB) chan *RegisteredHostsData {
 func GetRegisteredHost(db *sql.DB, hostname string) (*RegisteredHostsData, error) {
 	row := db.QueryRow(GetHostQuery, hostname)
 	r := RegisteredHostsData{}
-	err := row.Scan(&r.Hostname, &r.Key, &r.Data, &r.Key)
+	err := row.Scan(&r.Hostname, &r.Key, &r.Data, &r.Status, &r.Fingerprint)
 	if err != nil {
 		return nil, err
 	}
@@ -93,26 +101,48 @@ func UpdateRegisteredHost(db *sql.DB, row *RegisteredHostsData) error {
 }
 
 func InsertHostRegistration(db *sql.DB, row *RegisteredHostsData) error {
-	_, err := db.Exec(InsertHostQuery, row.Hostname, row.Key, row.Data)
+	_, err := db.Exec(InsertHostQuery, row.Hostname, row.Key, row.Data, row.Status, row.Fingerprint)
+	return err
+}
+
+func SetHostStatus(db *sql.DB, hostname, status string) error {
+	_, err := db.Exec(SetHostStatusQuery, status, hostname)
+	return err
+}
+
+func DeleteHost(db *sql.DB, hostname string) error {
+	_, err := db.Exec(DeleteHostQuery, hostname)
 	return err
 }
 
 type RegisteredServerRow struct {
-	Hostname string
-	Key      string
+	Hostname   string
+	Key        string
+	SigningKey []byte
 }
 
 func GetRegisteredCoordinationServer(db *sql.DB, key string) (*RegisteredServerRow, error) {
 	r := RegisteredServerRow{}
 	row := db.QueryRow(GetServerQuery, key)
-	err := row.Scan(r.Hostname, r.Key)
+	err := row.Scan(&r.Hostname, &r.Key, &r.SigningKey)
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// GetCoordinationServerByHostname returns the coordination server registered under hostname
+func GetCoordinationServerByHostname(db *sql.DB, hostname string) (*RegisteredServerRow, error) {
+	r := RegisteredServerRow{}
+	row := db.QueryRow(GetServerByHostnameQuery, hostname)
+	err := row.Scan(&r.Hostname, &r.Key, &r.SigningKey)
 	if err != nil {
 		return nil, err
 	}
 	return &r, nil
 }
 
-func SetRegisteredCoordinationServer(db *sql.DB, hostname, key string) error {
-	_, err := db.Exec(InsertServerQuery, hostname, key)
+func SetRegisteredCoordinationServer(db *sql.DB, row *RegisteredServerRow) error {
+	_, err := db.Exec(InsertServerQuery, row.Hostname, row.Key, row.SigningKey)
 	return err
 }
@@ -0,0 +1,73 @@
+package queries
+
+import (
+	"database/sql"
+	"time"
+)
+
+const (
+	InsertShellSessionQuery = `INSERT INTO shell_sessions (session_id, requester, hostname, user, command, started) VALUES(?,?,?,?,?,?)`
+	FinishShellSessionQuery = `UPDATE shell_sessions SET ended=?, exit_code=?, error=?, transcript=? WHERE session_id=?`
+	GetShellSessionQuery    = `SELECT session_id,requester,hostname,user,command,started,ended,exit_code,error,transcript FROM shell_sessions WHERE session_id=?`
+	GetShellSessionsQuery   = `SELECT session_id,requester,hostname,user,command,started,ended,exit_code,error FROM shell_sessions ORDER BY started DESC LIMIT ?`
+	AbandonShellsQuery      = `UPDATE shell_sessions SET ended=?, error='coordination server restarted' WHERE ended IS NULL`
+)
+
+// ShellSessionRow is an interactive shell opened on a node, the transcript is written when the session ends
+type ShellSessionRow struct {
+	SessionID  string
+	Requester  string
+	Hostname   string
+	User       string
+	Command    string
+	Started    time.Time
+	Ended      sql.NullTime
+	ExitCode   sql.NullInt32
+	Error      string
+	Transcript []byte
+}
+
+func InsertShellSession(db *sql.DB, row *ShellSessionRow) error {
+	_, err := db.Exec(InsertShellSessionQuery, row.SessionID, row.Requester, row.Hostname, row.User, row.Command, row.Started)
+	return err
+}
+
+func FinishShellSession(db *sql.DB, row *ShellSessionRow) error {
+	_, err := db.Exec(FinishShellSessionQuery, row.Ended, row.ExitCode, row.Error, row.Transcript, row.SessionID)
+	return err
+}
+
+func GetShellSession(db *sql.DB, sessionID string) (*ShellSessionRow, error) {
+	r := ShellSessionRow{}
+	row := db.QueryRow(GetShellSessionQuery, sessionID)
+	err := row.Scan(&r.SessionID, &r.Requester, &r.Hostname, &r.User, &r.Command, &r.Started, &r.Ended, &r.ExitCode, &r.Error, &r.Transcript)
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// GetShellSessions returns the most recent sessions first without their transcripts
+func GetShellSessions(db *sql.DB, limit int) ([]*ShellSessionRow, error) {
+	rows, err := db.Query(GetShellSessionsQuery, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := make([]*ShellSessionRow, 0)
+	for rows.Next() {
+		r := ShellSessionRow{}
+		if err := rows.Scan(&r.SessionID, &r.Requester, &r.Hostname, &r.User, &r.Command, &r.Started, &r.Ended, &r.ExitCode, &r.Error); err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, &r)
+	}
+	return sessions, rows.Err()
+}
+
+// AbandonShells ends every session that was still open, they can't outlive the server that relayed them
+func AbandonShells(db *sql.DB, now time.Time) error {
+	_, err := db.Exec(AbandonShellsQuery, now)
+	return err
+}
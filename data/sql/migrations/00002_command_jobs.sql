@@ -0,0 +1,42 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS command_jobs (
+  job_id TEXT PRIMARY KEY,
+  requester TEXT NOT NULL,
+  pattern TEXT NOT NULL,
+  command TEXT NOT NULL,
+  request BLOB,
+  created DATETIME NOT NULL
+);
+
+CREATE INDEX idx_command_jobs_created ON command_jobs (created);
+
+-- command_records was never written to so it is safe to recreate with the job columns
+DROP TABLE command_records;
+CREATE TABLE IF NOT EXISTS command_records (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  job_id TEXT NOT NULL,
+  hostname TEXT NOT NULL,
+  success INTEGER,
+  exit_code INTEGER,
+  stdout BLOB,
+  stderr BLOB,
+  error TEXT,
+  started DATETIME NOT NULL,
+  finished DATETIME,
+  FOREIGN KEY(job_id) REFERENCES command_jobs(job_id)
+);
+
+CREATE INDEX idx_command_records_job_id ON command_records (job_id);
+
+-- +goose Down
+DROP TABLE command_records;
+DROP TABLE command_jobs;
+
+CREATE TABLE IF NOT EXISTS command_records (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  hostname TEXT NOT NULL,
+  time DATETIME NOT NULL,   
+  success INTEGER,
+  output BLOB,
+  FOREIGN KEY(hostname) REFERENCES node_registration(hostname)
+);
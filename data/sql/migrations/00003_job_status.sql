@@ -0,0 +1,55 @@
+-- +goose Up
+ALTER TABLE command_jobs ADD COLUMN status TEXT NOT NULL DEFAULT 'finished';
+ALTER TABLE command_jobs ADD COLUMN finished DATETIME;
+
+-- hosts are recorded as pending when the job is submitted so started can no longer be required
+CREATE TABLE command_records_new (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  job_id TEXT NOT NULL,
+  hostname TEXT NOT NULL,
+  status TEXT NOT NULL DEFAULT 'pending',
+  success INTEGER,
+  exit_code INTEGER,
+  stdout BLOB,
+  stderr BLOB,
+  error TEXT,
+  started DATETIME,
+  finished DATETIME,
+  FOREIGN KEY(job_id) REFERENCES command_jobs(job_id)
+);
+
+INSERT INTO command_records_new (id, job_id, hostname, status, success, exit_code, stdout, stderr, error, started, finished)
+SELECT id, job_id, hostname,
+  CASE WHEN finished IS NULL THEN 'failed' WHEN success=1 THEN 'succeeded' ELSE 'failed' END,
+  success, exit_code, stdout, stderr, error, started, finished
+FROM command_records;
+
+DROP TABLE command_records;
+ALTER TABLE command_records_new RENAME TO command_records;
+CREATE INDEX idx_command_records_job_id ON command_records (job_id);
+
+-- +goose Down
+CREATE TABLE command_records_old (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  job_id TEXT NOT NULL,
+  hostname TEXT NOT NULL,
+  success INTEGER,
+  exit_code INTEGER,
+  stdout BLOB,
+  stderr BLOB,
+  error TEXT,
+  started DATETIME NOT NULL,
+  finished DATETIME,
+  FOREIGN KEY(job_id) REFERENCES command_jobs(job_id)
+);
+
+INSERT INTO command_records_old (id, job_id, hostname, success, exit_code, stdout, stderr, error, started, finished)
+SELECT id, job_id, hostname, success, exit_code, stdout, stderr, error, COALESCE(started, finished, CURRENT_TIMESTAMP), finished
+FROM command_records;
+
+DROP TABLE command_records;
+ALTER TABLE command_records_old RENAME TO command_records;
+CREATE INDEX idx_command_records_job_id ON command_records (job_id);
+
+ALTER TABLE command_jobs DROP COLUMN finished;
+ALTER TABLE command_jobs DROP COLUMN status;
@@ -0,0 +1,8 @@
+-- +goose Up
+-- nodes that registered before acceptance was enforced have to be accepted again
+ALTER TABLE node_registration ADD COLUMN status TEXT NOT NULL DEFAULT 'pending';
+ALTER TABLE node_registration ADD COLUMN fingerprint TEXT NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE node_registration DROP COLUMN fingerprint;
+ALTER TABLE node_registration DROP COLUMN status;
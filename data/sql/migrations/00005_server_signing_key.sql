@@ -0,0 +1,6 @@
+-- +goose Up
+-- servers registered before requests were signed have to register again before they can send commands
+ALTER TABLE server_registration ADD COLUMN signing_key BLOB;
+
+-- +goose Down
+ALTER TABLE server_registration DROP COLUMN signing_key;
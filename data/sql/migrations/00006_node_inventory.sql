@@ -0,0 +1,46 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS node_inventory (
+  hostname TEXT NOT NULL PRIMARY KEY,
+  os TEXT NOT NULL DEFAULT '',
+  distro TEXT NOT NULL DEFAULT '',
+  distro_version TEXT NOT NULL DEFAULT '',
+  distro_name TEXT NOT NULL DEFAULT '',
+  kernel TEXT NOT NULL DEFAULT '',
+  arch TEXT NOT NULL DEFAULT '',
+  cpu_count INTEGER NOT NULL DEFAULT 0,
+  cpu_model TEXT NOT NULL DEFAULT '',
+  memory_total INTEGER NOT NULL DEFAULT 0,
+  memory_available INTEGER NOT NULL DEFAULT 0,
+  uptime_seconds INTEGER NOT NULL DEFAULT 0,
+  tailscale_ips TEXT NOT NULL DEFAULT '',
+  tailscale_tags TEXT NOT NULL DEFAULT '',
+  tailsys_version TEXT NOT NULL DEFAULT '',
+  ip TEXT NOT NULL DEFAULT '',
+  collected DATETIME NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS node_disks (
+  hostname TEXT NOT NULL,
+  device TEXT NOT NULL,
+  mountpoint TEXT NOT NULL,
+  fstype TEXT NOT NULL,
+  total_bytes INTEGER NOT NULL,
+  free_bytes INTEGER NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS node_interfaces (
+  hostname TEXT NOT NULL,
+  name TEXT NOT NULL,
+  mac TEXT NOT NULL,
+  addresses TEXT NOT NULL,
+  mtu INTEGER NOT NULL,
+  up INTEGER NOT NULL
+);
+
+CREATE INDEX idx_node_disks_hostname ON node_disks (hostname);
+CREATE INDEX idx_node_interfaces_hostname ON node_interfaces (hostname);
+
+-- +goose Down
+DROP TABLE node_interfaces;
+DROP TABLE node_disks;
+DROP TABLE node_inventory;
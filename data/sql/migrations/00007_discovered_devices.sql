@@ -0,0 +1,17 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS discovered_devices (
+  hostname TEXT NOT NULL PRIMARY KEY,
+  device_id TEXT NOT NULL DEFAULT '',
+  name TEXT NOT NULL DEFAULT '',
+  addresses TEXT NOT NULL DEFAULT '',
+  tags TEXT NOT NULL DEFAULT '',
+  os TEXT NOT NULL DEFAULT '',
+  client_version TEXT NOT NULL DEFAULT '',
+  last_seen DATETIME,
+  state TEXT NOT NULL,
+  first_seen DATETIME NOT NULL,
+  checked DATETIME NOT NULL
+);
+
+-- +goose Down
+DROP TABLE discovered_devices;
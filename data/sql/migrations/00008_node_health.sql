@@ -0,0 +1,26 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS node_health (
+  hostname TEXT NOT NULL PRIMARY KEY,
+  status TEXT NOT NULL,
+  since DATETIME NOT NULL,
+  latency_ms REAL NOT NULL DEFAULT 0,
+  last_seen DATETIME,
+  last_checked DATETIME NOT NULL,
+  next_probe DATETIME NOT NULL,
+  failures INTEGER NOT NULL DEFAULT 0,
+  error TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS node_pings (
+  hostname TEXT NOT NULL,
+  pinged DATETIME NOT NULL,
+  successful BOOLEAN NOT NULL,
+  latency_ms REAL NOT NULL DEFAULT 0,
+  error TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_node_pings_hostname_pinged ON node_pings (hostname, pinged);
+
+-- +goose Down
+DROP TABLE node_pings;
+DROP TABLE node_health;
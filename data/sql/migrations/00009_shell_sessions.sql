@@ -0,0 +1,18 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS shell_sessions (
+  session_id TEXT PRIMARY KEY,
+  requester TEXT NOT NULL,
+  hostname TEXT NOT NULL,
+  user TEXT NOT NULL DEFAULT '',
+  command TEXT NOT NULL DEFAULT '',
+  started DATETIME NOT NULL,
+  ended DATETIME,
+  exit_code INTEGER,
+  error TEXT NOT NULL DEFAULT '',
+  transcript BLOB
+);
+
+CREATE INDEX idx_shell_sessions_started ON shell_sessions (started);
+
+-- +goose Down
+DROP TABLE shell_sessions;
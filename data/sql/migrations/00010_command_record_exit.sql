@@ -0,0 +1,10 @@
+-- +goose Up
+-- how the command ended on the host, so a finished job replays the same exit as it was streamed
+ALTER TABLE command_records ADD COLUMN signal TEXT NOT NULL DEFAULT '';
+ALTER TABLE command_records ADD COLUMN reason TEXT NOT NULL DEFAULT '';
+ALTER TABLE command_records ADD COLUMN duration_ms INTEGER;
+
+-- +goose Down
+ALTER TABLE command_records DROP COLUMN duration_ms;
+ALTER TABLE command_records DROP COLUMN reason;
+ALTER TABLE command_records DROP COLUMN signal;
@@ -0,0 +1,138 @@
+// Package logging creates the structured loggers used by every part of tailsys
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Formats the logs can be written in
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Redacted replaces every secret that would otherwise end up in the logs
+const Redacted = "[REDACTED]"
+
+// secretKeys are parts of attribute and proto field names whose values are never logged
+var secretKeys = []string{"secret", "password", "passwd", "token", "private", "auth_key", "authkey", "credential", "csr"}
+
+// secretValues are parts of values that give away a secret no matter what attribute they are logged under
+var secretValues = []string{"PRIVATE KEY-----", "tskey-"}
+
+// New creates a logger writing to w in format, debug messages are only written when verbose. Secrets are redacted
+// from every message.
+func New(w io.Writer, format string, verbose bool) (*slog.Logger, error) {
+	level := slog.LevelInfo
+	if verbose {
+		level = slog.LevelDebug
+	}
+	opts := &slog.HandlerOptions{
+		Level:       level,
+		ReplaceAttr: redact,
+	}
+	switch strings.ToLower(format) {
+	case "", FormatText:
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	case FormatJSON:
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	}
+	return nil, fmt.Errorf("unknown log format %q, use %s or %s", format, FormatText, FormatJSON)
+}
+
+// Discard is a logger that writes nothing
+func Discard() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+// redact hides attributes named like secrets, values that look like secrets and the secret fields of protos
+func redact(groups []string, a slog.Attr) slog.Attr {
+	if secretKey(a.Key) {
+		return slog.String(a.Key, Redacted)
+	}
+	switch a.Value.Kind() {
+	case slog.KindString:
+		if secretValue(a.Value.String()) {
+			return slog.String(a.Key, Redacted)
+		}
+	case slog.KindAny:
+		switch v := a.Value.Any().(type) {
+		case proto.Message:
+			return slog.String(a.Key, redactProto(v))
+		case error:
+			if secretValue(v.Error()) {
+				return slog.Any(a.Key, errors.New(Redacted))
+			}
+		case fmt.Stringer:
+			if secretValue(v.String()) {
+				return slog.String(a.Key, Redacted)
+			}
+		}
+	}
+	return a
+}
+
+func secretKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, s := range secretKeys {
+		if strings.Contains(key, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func secretValue(value string) bool {
+	for _, s := range secretValues {
+		if strings.Contains(value, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactProto renders the message on a single line with its secret fields cleared
+func redactProto(m proto.Message) string {
+	m = proto.Clone(m)
+	clearSecrets(m.ProtoReflect())
+	return prototext.MarshalOptions{}.Format(m)
+}
+
+func clearSecrets(m protoreflect.Message) {
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case secretKey(string(fd.Name())):
+			m.Clear(fd)
+		case fd.Message() == nil:
+			if fd.Kind() == protoreflect.StringKind && !fd.IsList() && !fd.IsMap() && secretValue(v.String()) {
+				m.Clear(fd)
+			}
+		case fd.IsList():
+			for i := 0; i < v.List().Len(); i++ {
+				clearSecrets(v.List().Get(i).Message())
+			}
+		case fd.IsMap():
+			v.Map().Range(func(k protoreflect.MapKey, mv protoreflect.Value) bool {
+				switch {
+				case fd.MapValue().Message() != nil:
+					clearSecrets(mv.Message())
+				case fd.MapValue().Kind() == protoreflect.StringKind && (secretKey(k.String()) || secretValue(mv.String())):
+					//like environment variables holding tokens
+					v.Map().Set(k, protoreflect.ValueOfString(Redacted))
+				}
+				return true
+			})
+		default:
+			clearSecrets(v.Message())
+		}
+		return true
+	})
+}
@@ -0,0 +1,377 @@
+// Package metrics keeps counters, gauges and histograms and serves them in the prometheus text format. It only does
+// what tailsys needs, metrics are created once when their package is loaded and registered with Default.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefBuckets are the histogram buckets in seconds used when none are given, from 5ms to 10s
+var DefBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// collector writes one metric family in the text format
+type collector interface {
+	name() string
+	write(w io.Writer)
+}
+
+// Registry holds the metrics served together
+type Registry struct {
+	mu         sync.Mutex
+	collectors map[string]collector
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{collectors: make(map[string]collector)}
+}
+
+// Default is the registry every metric of tailsys is registered with
+var Default = NewRegistry()
+
+// register adds c, a metric can only be registered once under its name
+func (r *Registry) register(c collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.collectors[c.name()]; ok {
+		panic(fmt.Sprintf("metric %s registered twice", c.name()))
+	}
+	r.collectors[c.name()] = c
+}
+
+// Unregister removes the metric named name, it does nothing when there is none
+func (r *Registry) Unregister(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.collectors, name)
+}
+
+// WriteText writes every metric sorted by name in the text format
+func (r *Registry) WriteText(w io.Writer) {
+	r.mu.Lock()
+	collectors := make([]collector, 0, len(r.collectors))
+	for _, c := range r.collectors {
+		collectors = append(collectors, c)
+	}
+	r.mu.Unlock()
+	sort.Slice(collectors, func(i, j int) bool { return collectors[i].name() < collectors[j].name() })
+
+	bw := bufio.NewWriter(w)
+	for _, c := range collectors {
+		c.write(bw)
+	}
+	bw.Flush()
+}
+
+// Handler serves the metrics of the registry
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		r.WriteText(w)
+	})
+}
+
+// desc is what every metric family has
+type desc struct {
+	fqName string
+	help   string
+	kind   string
+	labels []string
+}
+
+func (d *desc) name() string { return d.fqName }
+
+func (d *desc) header(w io.Writer) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", d.fqName, escapeHelp(d.help), d.fqName, d.kind)
+}
+
+// key joins the label values into a map key, checking there is one for every label
+func (d *desc) key(values []string) string {
+	if len(values) != len(d.labels) {
+		panic(fmt.Sprintf("metric %s has %d labels, got %d values", d.fqName, len(d.labels), len(values)))
+	}
+	return strings.Join(values, "\xff")
+}
+
+// labelPairs renders the labels with their values, extra is added at the end like the le of a histogram bucket
+func (d *desc) labelPairs(values []string, extra ...string) string {
+	if len(d.labels) == 0 && len(extra) == 0 {
+		return ""
+	}
+	pairs := make([]string, 0, len(d.labels)+len(extra)/2)
+	for i, l := range d.labels {
+		pairs = append(pairs, l+`="`+escapeLabel(values[i])+`"`)
+	}
+	for i := 0; i+1 < len(extra); i += 2 {
+		pairs = append(pairs, extra[i]+`="`+escapeLabel(extra[i+1])+`"`)
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// series is a value of a metric family for one set of label values
+type series[T any] struct {
+	values []string
+	metric T
+}
+
+// family holds the series of a metric by their label values
+type family[T any] struct {
+	desc
+	mu     sync.Mutex
+	series map[string]*series[T]
+	create func() T
+}
+
+func (f *family[T]) with(values []string) T {
+	key := f.key(values)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	s, ok := f.series[key]
+	if !ok {
+		s = &series[T]{values: append([]string(nil), values...), metric: f.create()}
+		f.series[key] = s
+	}
+	return s.metric
+}
+
+// Delete drops the series with the label values, like a node that was deleted
+func (f *family[T]) Delete(values ...string) {
+	key := f.key(values)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.series, key)
+}
+
+// sorted returns the series ordered by their label values so the output is stable
+func (f *family[T]) sorted() []*series[T] {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	res := make([]*series[T], 0, len(f.series))
+	for _, s := range f.series {
+		res = append(res, s)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return strings.Join(res[i].values, "\xff") < strings.Join(res[j].values, "\xff")
+	})
+	return res
+}
+
+func newFamily[T any](name, help, kind string, labels []string, create func() T) *family[T] {
+	return &family[T]{
+		desc:   desc{fqName: name, help: help, kind: kind, labels: labels},
+		series: make(map[string]*series[T]),
+		create: create,
+	}
+}
+
+// Counter only goes up
+type Counter struct {
+	mu sync.Mutex
+	v  float64
+}
+
+// Inc adds one
+func (c *Counter) Inc() { c.Add(1) }
+
+// Add adds v, which can't be negative
+func (c *Counter) Add(v float64) {
+	if v < 0 {
+		panic("counters can't go down")
+	}
+	c.mu.Lock()
+	c.v += v
+	c.mu.Unlock()
+}
+
+func (c *Counter) value() float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.v
+}
+
+// CounterVec is a counter for every set of label values
+type CounterVec struct {
+	*family[*Counter]
+}
+
+// NewCounterVec creates a counter with labels and registers it with Default
+func NewCounterVec(name, help string, labels ...string) *CounterVec {
+	c := &CounterVec{newFamily(name, help, "counter", labels, func() *Counter { return &Counter{} })}
+	Default.register(c)
+	return c
+}
+
+// With returns the counter for the label values, in the order of the labels
+func (c *CounterVec) With(values ...string) *Counter { return c.with(values) }
+
+func (c *CounterVec) write(w io.Writer) {
+	c.header(w)
+	for _, s := range c.sorted() {
+		fmt.Fprintf(w, "%s%s %s\n", c.fqName, c.labelPairs(s.values), formatFloat(s.metric.value()))
+	}
+}
+
+// Gauge goes up and down
+type Gauge struct {
+	mu sync.Mutex
+	v  float64
+}
+
+// Set sets the gauge to v
+func (g *Gauge) Set(v float64) {
+	g.mu.Lock()
+	g.v = v
+	g.mu.Unlock()
+}
+
+// Add adds v, which may be negative
+func (g *Gauge) Add(v float64) {
+	g.mu.Lock()
+	g.v += v
+	g.mu.Unlock()
+}
+
+// Inc adds one
+func (g *Gauge) Inc() { g.Add(1) }
+
+// Dec takes one away
+func (g *Gauge) Dec() { g.Add(-1) }
+
+func (g *Gauge) value() float64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.v
+}
+
+// GaugeVec is a gauge for every set of label values
+type GaugeVec struct {
+	*family[*Gauge]
+}
+
+// NewGaugeVec creates a gauge with labels and registers it with Default
+func NewGaugeVec(name, help string, labels ...string) *GaugeVec {
+	g := &GaugeVec{newFamily(name, help, "gauge", labels, func() *Gauge { return &Gauge{} })}
+	Default.register(g)
+	return g
+}
+
+// With returns the gauge for the label values, in the order of the labels
+func (g *GaugeVec) With(values ...string) *Gauge { return g.with(values) }
+
+func (g *GaugeVec) write(w io.Writer) {
+	g.header(w)
+	for _, s := range g.sorted() {
+		fmt.Fprintf(w, "%s%s %s\n", g.fqName, g.labelPairs(s.values), formatFloat(s.metric.value()))
+	}
+}
+
+// GaugeFunc is a gauge whose values are looked up every time the metrics are served
+type GaugeFunc struct {
+	desc
+	collect func(emit func(v float64, values ...string))
+}
+
+// NewGaugeFunc creates a gauge that calls collect for its values and registers it with Default. collect calls emit
+// once for every set of label values.
+func NewGaugeFunc(name, help string, labels []string, collect func(emit func(v float64, values ...string))) *GaugeFunc {
+	g := &GaugeFunc{desc: desc{fqName: name, help: help, kind: "gauge", labels: labels}, collect: collect}
+	Default.register(g)
+	return g
+}
+
+func (g *GaugeFunc) write(w io.Writer) {
+	g.header(w)
+	g.collect(func(v float64, values ...string) {
+		g.key(values)
+		fmt.Fprintf(w, "%s%s %s\n", g.fqName, g.labelPairs(values), formatFloat(v))
+	})
+}
+
+// Histogram counts observations into buckets
+type Histogram struct {
+	mu      sync.Mutex
+	buckets []float64
+	counts  []uint64
+	count   uint64
+	sum     float64
+}
+
+// Observe records v
+func (h *Histogram) Observe(v float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for i, b := range h.buckets {
+		if v <= b {
+			h.counts[i]++
+		}
+	}
+	h.count++
+	h.sum += v
+}
+
+// HistogramVec is a histogram for every set of label values
+type HistogramVec struct {
+	*family[*Histogram]
+	buckets []float64
+}
+
+// NewHistogramVec creates a histogram with labels and registers it with Default, DefBuckets are used without buckets
+func NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	if len(buckets) == 0 {
+		buckets = DefBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+	h := &HistogramVec{buckets: buckets}
+	h.family = newFamily(name, help, "histogram", labels, func() *Histogram {
+		return &Histogram{buckets: buckets, counts: make([]uint64, len(buckets))}
+	})
+	Default.register(h)
+	return h
+}
+
+// With returns the histogram for the label values, in the order of the labels
+func (h *HistogramVec) With(values ...string) *Histogram { return h.with(values) }
+
+func (h *HistogramVec) write(w io.Writer) {
+	h.header(w)
+	for _, s := range h.sorted() {
+		hist := s.metric
+		hist.mu.Lock()
+		for i, b := range hist.buckets {
+			fmt.Fprintf(w, "%s_bucket%s %d\n", h.fqName, h.labelPairs(s.values, "le", formatFloat(b)), hist.counts[i])
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", h.fqName, h.labelPairs(s.values, "le", "+Inf"), hist.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", h.fqName, h.labelPairs(s.values), formatFloat(hist.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", h.fqName, h.labelPairs(s.values), hist.count)
+		hist.mu.Unlock()
+	}
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(s string) string  { return helpEscaper.Replace(s) }
+func escapeLabel(s string) string { return labelEscaper.Replace(s) }
@@ -8,13 +8,21 @@ import (
 	pb "github.com/charles-d-burton/tailsys/commands"
 	"github.com/charles-d-burton/tailsys/connections"
 	"github.com/charles-d-burton/tailsys/services"
-	"github.com/google/uuid"
 )
 
+// MethodRoles only lets the coordination server call the services on a node
+var MethodRoles = connections.MethodRoles{
+	"": {connections.RoleCoordinator},
+}
+
 type Client struct {
 	services.DataManagement
 	connections.Tailnet
 	ID string
+
+	verifier   services.CommandVerifier
+	tunnel     *connections.TunnelListener
+	stopTunnel context.CancelFunc
 }
 
 type Option func(cl *Client) error
@@ -27,26 +35,46 @@ func (cl *Client) NewClient(ctx context.Context, opts ...Option) error {
 		}
 	}
 
-	cl.ID = uuid.NewString()
+	return nil
+}
+
+// LoadIdentity loads the identity of the client from the data directory, it is the key we register with
+func (cl *Client) LoadIdentity() error {
+	id, err := services.LoadIdentity(cl.ConfigDir, cl.Logger())
+	if err != nil {
+		return fmt.Errorf("unable to load identity: %w", err)
+	}
+	cl.ID = id.ID
+	cl.Logger().Info("loaded identity", "id", cl.ID)
 	return nil
 }
 
 func (cl *Client) StartDatabase(ctx context.Context) error {
-	return cl.StartDB(cl.ConfigDir)
+	return cl.StartDB(cl.ConfigDir, cl.Logger())
 }
 
 func (cl *Client) StartRPCClientMode(ctx context.Context) error {
-	fmt.Println("starting grpc client server")
 	if cl.DB == nil {
 		return errors.New("datastore not initialized")
 	}
 
 	pb.RegisterPingerServer(cl.GRPCServer, &services.Pinger{
-		DB: cl.DB,
-		ID: cl.ID,
+		DB:  cl.DB,
+		ID:  cl.ID,
+		Log: cl.Logger(),
 	})
 
-	pb.RegisterCommandRunnerServer(cl.GRPCServer, &CommandServer{})
+	pb.RegisterCommandRunnerServer(cl.GRPCServer, &CommandServer{log: cl.Logger()})
+	pb.RegisterStateRunnerServer(cl.GRPCServer, &StateServer{log: cl.Logger()})
+	pb.RegisterFileTransferServer(cl.GRPCServer, &TransferServer{log: cl.Logger()})
+	pb.RegisterShellServer(cl.GRPCServer, &ShellServer{log: cl.Logger()})
+	pb.RegisterSystemInfoServer(cl.GRPCServer, &InventoryServer{cl: cl})
 
-	return cl.GRPCServer.Serve(cl.Listener)
+	cl.Logger().Info("node serving", "addr", cl.Addr, "reverse", cl.tunnel != nil)
+	if cl.tunnel != nil {
+		//the tunnel stays open until the commands that came through it are done
+		defer cl.stopTunnel()
+		return cl.Serve(ctx, cl.tunnel)
+	}
+	return cl.Serve(ctx, cl.Listener)
 }
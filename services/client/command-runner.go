@@ -1,39 +1,242 @@
 package client
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
+	"os"
 	"os/exec"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	pb "github.com/charles-d-burton/tailsys/commands"
+	"github.com/charles-d-burton/tailsys/metrics"
+	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+// killGracePeriod is how long a canceled command has to exit after being interrupted before it is killed
+const killGracePeriod = 5 * time.Second
+
+var (
+	commandsRunning = metrics.NewGaugeVec("tailsys_node_commands_running",
+		"Commands running on this node.")
+	commandsRun = metrics.NewCounterVec("tailsys_node_commands_total",
+		"Commands run on this node, by result.", "result")
+	commandDuration = metrics.NewHistogramVec("tailsys_node_command_duration_seconds",
+		"Time commands ran on this node, by result.",
+		[]float64{.01, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60, 300, 900, 3600}, "result")
+)
+
 // CommandServer struct to contain command runner rpc
 type CommandServer struct {
 	pb.UnimplementedCommandRunnerServer
+	log *slog.Logger
 }
 
 // RegisterCommandRunner registers the RPC call and implements behavior for command runner
 func (c *CommandServer) Command(ctx context.Context, in *pb.CommandRequest) (*pb.CommandResponse, error) {
-	fmt.Printf("running command: %s\n", in.Command)
-  cnds := strings.Fields(in.Command)
-  cmdo := exec.Command(cnds[0], cnds[1:]...)
-  out, err := cmdo.Output()
-  fmt.Println(string(out))
-  if err != nil {
-    return &pb.CommandResponse{
-      Timestamp: timestamppb.Now(),
-      Successful: false,
-      Output: []byte(err.Error()),
-      ExitCode: 1,
-    }, nil
-  }
-	return &pb.CommandResponse{
-		Timestamp:  timestamppb.Now(),
-		Successful: true,
-		Output:     out,
-		ExitCode:   0,
-	}, nil
+	var stdout, stderr bytes.Buffer
+	res := c.execute(ctx, in, &stdout, &stderr)
+	res.Stdout = stdout.Bytes()
+	res.Stderr = stderr.Bytes()
+	return res, nil
+}
+
+// CommandStream runs the command and sends stdout and stderr back as they are produced, followed by the exit status
+func (c *CommandServer) CommandStream(in *pb.CommandRequest, stream pb.CommandRunner_CommandStreamServer) error {
+	out := &outputSender{send: stream.Send}
+	res := c.execute(stream.Context(), in, out.writer(pb.OutputStream_STDOUT), out.writer(pb.OutputStream_STDERR))
+	return out.exit(res)
+}
+
+// execute runs the requested command to completion, writing its output as it is produced.
+// If the request has a timeout, or the caller goes away, the whole process group is asked to stop and
+// killed if it is still around after killGracePeriod.
+func (c *CommandServer) execute(ctx context.Context, in *pb.CommandRequest, stdout, stderr io.Writer) *pb.CommandResponse {
+	start := time.Now()
+	log := c.log.With("command", in.Command, "argv", in.Argv, "run_as", in.RunAs)
+	log.Info("running command")
+	commandsRunning.With().Inc()
+	res := &pb.CommandResponse{
+		ExitCode: -1,
+	}
+	finish := func() *pb.CommandResponse {
+		took := time.Since(start)
+		res.Timestamp = timestamppb.Now()
+		res.Duration = durationpb.New(took)
+		log.Info("command finished", "exit_code", res.ExitCode, "reason", res.Reason.String(), "duration", took)
+		commandsRunning.With().Dec()
+		result := commandResult(res)
+		commandsRun.With(result).Inc()
+		commandDuration.With(result).Observe(took.Seconds())
+		return res
+	}
+
+	runCtx := ctx
+	if timeout := in.GetTimeout().AsDuration(); timeout > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	cmdo, err := buildCommand(runCtx, in)
+	if err != nil {
+		res.Reason = pb.TerminationReason_START_FAILED
+		res.Error = err.Error()
+		return finish()
+	}
+	setProcessGroup(cmdo)
+	cmdo.Cancel = func() error { return interruptProcessGroup(cmdo) }
+	//the process is killed if it ignores the interrupt, this also stops us waiting forever on orphans holding stdout/stderr open
+	cmdo.WaitDelay = killGracePeriod
+	cmdo.Stdout = stdout
+	cmdo.Stderr = stderr
+
+	err = cmdo.Run()
+	if runCtx.Err() != nil {
+		//anything the command left behind in its group goes with it
+		killProcessGroup(cmdo)
+	}
+	if cmdo.ProcessState == nil {
+		res.Reason = pb.TerminationReason_START_FAILED
+		res.Error = err.Error()
+		return finish()
+	}
+
+	res.ExitCode = int32(cmdo.ProcessState.ExitCode())
+	res.Signal = exitSignal(cmdo.ProcessState)
+	switch {
+	case err == nil:
+		res.Reason = pb.TerminationReason_EXITED
+	case ctx.Err() != nil:
+		res.Reason = pb.TerminationReason_CANCELED
+		res.Error = "command canceled by caller"
+	case runCtx.Err() != nil:
+		res.Reason = pb.TerminationReason_TIMED_OUT
+		res.Error = fmt.Sprintf("command timed out after %s", in.GetTimeout().AsDuration())
+	case res.Signal != "":
+		res.Reason = pb.TerminationReason_SIGNALED
+	default:
+		res.Reason = pb.TerminationReason_EXITED
+	}
+
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) && res.Error == "" {
+		res.Error = err.Error()
+	}
+	res.Successful = err == nil && res.Reason == pb.TerminationReason_EXITED
+	return finish()
+}
+
+// commandResult is the result label of a command, a command that exited is failed unless it exited with 0
+func commandResult(res *pb.CommandResponse) string {
+	switch {
+	case res.Successful:
+		return "succeeded"
+	case res.Reason == pb.TerminationReason_EXITED:
+		return "failed"
+	}
+	return strings.ToLower(res.Reason.String())
+}
+
+// buildCommand turns the request into the process to run. An argv is run as is, shell mode hands the
+// command string to the interpreter, otherwise the command is split on whitespace.
+func buildCommand(ctx context.Context, in *pb.CommandRequest) (*exec.Cmd, error) {
+	var args []string
+	switch {
+	case len(in.Argv) > 0:
+		args = in.Argv
+	case in.Shell:
+		if strings.TrimSpace(in.Command) == "" {
+			return nil, errors.New("no command provided")
+		}
+		interpreter := strings.Fields(in.Interpreter)
+		if len(interpreter) == 0 {
+			interpreter = defaultInterpreter
+		}
+		args = append(append([]string{}, interpreter...), in.Command)
+	default:
+		args = strings.Fields(in.Command)
+	}
+	if len(args) == 0 || args[0] == "" {
+		return nil, errors.New("no command provided")
+	}
+
+	cmdo := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmdo.Dir = in.Cwd
+	if len(in.Stdin) > 0 {
+		cmdo.Stdin = bytes.NewReader(in.Stdin)
+	}
+
+	env := os.Environ()
+	if in.RunAs != "" {
+		userEnv, err := setCredential(cmdo, in.RunAs)
+		if err != nil {
+			return nil, err
+		}
+		env = append(env, userEnv...)
+	}
+	keys := make([]string, 0, len(in.Env))
+	for k := range in.Env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		env = append(env, k+"="+in.Env[k])
+	}
+	cmdo.Env = env
+	return cmdo, nil
+}
+
+// outputSender serializes output chunks from both pipes onto a single stream and numbers them in send order
+type outputSender struct {
+	mu   sync.Mutex
+	seq  uint64
+	send func(*pb.CommandStreamResponse) error
+}
+
+func (o *outputSender) writer(stream pb.OutputStream) *outputWriter {
+	return &outputWriter{sender: o, stream: stream}
+}
+
+func (o *outputSender) exit(res *pb.CommandResponse) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.send(&pb.CommandStreamResponse{
+		Payload: &pb.CommandStreamResponse_Exit{Exit: res},
+	})
+}
+
+// outputWriter tags everything written to it with the pipe it came from
+type outputWriter struct {
+	sender *outputSender
+	stream pb.OutputStream
+}
+
+func (w *outputWriter) Write(p []byte) (int, error) {
+	w.sender.mu.Lock()
+	defer w.sender.mu.Unlock()
+	w.sender.seq++
+	data := make([]byte, len(p))
+	copy(data, p)
+	err := w.sender.send(&pb.CommandStreamResponse{
+		Payload: &pb.CommandStreamResponse_Output{
+			Output: &pb.CommandOutput{
+				Stream:    w.stream,
+				Sequence:  w.sender.seq,
+				Data:      data,
+				Timestamp: timestamppb.Now(),
+			},
+		},
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
 }
@@ -0,0 +1,158 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"log/slog"
+	"net"
+	"os"
+	"runtime"
+	"strings"
+
+	pb "github.com/charles-d-burton/tailsys/commands"
+	"github.com/charles-d-burton/tailsys/services"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// InventoryServer lets the coordination server ask for our inventory again
+type InventoryServer struct {
+	pb.UnimplementedSystemInfoServer
+	cl *Client
+}
+
+// SysInfo collects the inventory of the node
+func (s *InventoryServer) SysInfo(ctx context.Context, in *pb.SysInfoRequest) (*pb.SysInfo, error) {
+	s.cl.Logger().Debug("received inventory request")
+	return s.cl.sysInfo(ctx), nil
+}
+
+// sysInfo describes this node to the coordination server
+func (cl *Client) sysInfo(ctx context.Context) *pb.SysInfo {
+	inv := collectInventory()
+	ips, tags, err := cl.TailnetAddresses(ctx)
+	if err != nil {
+		cl.Logger().Warn("unable to get tailnet addresses", "err", err)
+	}
+	inv.TailscaleIps = ips
+	inv.TailscaleTags = tags
+
+	//the coordination server dials the hostname, the ip is informational
+	ip := cl.Hostname
+	if len(ips) > 0 {
+		ip = ips[0]
+	} else if addr := firstAddress(inv.Interfaces); addr != "" {
+		ip = addr
+	}
+	return &pb.SysInfo{
+		Hostname:  cl.Hostname,
+		Port:      cl.Port,
+		Type:      osType(),
+		Ip:        ip,
+		LastSeen:  timestamppb.Now(),
+		Inventory: inv,
+	}
+}
+
+// collectInventory gathers what can be found out about the system, anything that can't be read is left empty
+func collectInventory() *pb.Inventory {
+	inv := &pb.Inventory{
+		Os:             runtime.GOOS,
+		Arch:           runtime.GOARCH,
+		CpuCount:       int32(runtime.NumCPU()),
+		TailsysVersion: services.Version,
+		Collected:      timestamppb.Now(),
+	}
+	readOSRelease(inv)
+	inv.Interfaces = networkInterfaces()
+	collectSystem(inv)
+	return inv
+}
+
+func osType() pb.OSType {
+	switch runtime.GOOS {
+	case "linux":
+		return pb.OSType_LINUX
+	case "darwin":
+		return pb.OSType_MACOS
+	case "windows":
+		return pb.OSType_WINDOWS
+	}
+	return pb.OSType_OS_TYPE_UNSPECIFIED
+}
+
+// readOSRelease fills in the distro from os-release, see os-release(5)
+func readOSRelease(inv *pb.Inventory) {
+	var f *os.File
+	var err error
+	for _, path := range []string{"/etc/os-release", "/usr/lib/os-release"} {
+		if f, err = os.Open(path); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), "=")
+		if !ok {
+			continue
+		}
+		value = strings.Trim(value, `"'`)
+		switch key {
+		case "ID":
+			inv.Distro = value
+		case "VERSION_ID":
+			inv.DistroVersion = value
+		case "PRETTY_NAME":
+			inv.DistroName = value
+		}
+	}
+}
+
+// networkInterfaces lists every interface but loopback with its addresses
+func networkInterfaces() []*pb.NetworkInterface {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		slog.Warn("unable to list network interfaces", "err", err)
+		return nil
+	}
+	res := make([]*pb.NetworkInterface, 0, len(ifaces))
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		ni := &pb.NetworkInterface{
+			Name: iface.Name,
+			Mac:  iface.HardwareAddr.String(),
+			Mtu:  int32(iface.MTU),
+			Up:   iface.Flags&net.FlagUp != 0,
+		}
+		addrs, err := iface.Addrs()
+		if err == nil {
+			for _, addr := range addrs {
+				ni.Addresses = append(ni.Addresses, addr.String())
+			}
+		}
+		res = append(res, ni)
+	}
+	return res
+}
+
+// firstAddress returns the first ip of an interface that is up
+func firstAddress(ifaces []*pb.NetworkInterface) string {
+	for _, iface := range ifaces {
+		if !iface.Up {
+			continue
+		}
+		for _, addr := range iface.Addresses {
+			ip, _, err := net.ParseCIDR(addr)
+			if err == nil && !ip.IsLinkLocalUnicast() {
+				return ip.String()
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,135 @@
+package client
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+	"strings"
+
+	pb "github.com/charles-d-burton/tailsys/commands"
+	"golang.org/x/sys/unix"
+)
+
+// pseudoFilesystems are mounted filesystems that don't hold data and are left out of the disks
+var pseudoFilesystems = map[string]struct{}{
+	"autofs": {}, "binfmt_misc": {}, "bpf": {}, "cgroup": {}, "cgroup2": {}, "configfs": {}, "debugfs": {},
+	"devpts": {}, "devtmpfs": {}, "fusectl": {}, "hugetlbfs": {}, "mqueue": {}, "nsfs": {}, "proc": {},
+	"pstore": {}, "ramfs": {}, "rpc_pipefs": {}, "securityfs": {}, "squashfs": {}, "sysfs": {}, "tmpfs": {},
+	"tracefs": {}, "efivarfs": {},
+}
+
+// collectSystem reads the kernel, cpu, memory, uptime and disks from /proc
+func collectSystem(inv *pb.Inventory) {
+	if data, err := os.ReadFile("/proc/sys/kernel/osrelease"); err == nil {
+		inv.Kernel = strings.TrimSpace(string(data))
+	}
+	inv.CpuModel = cpuModel()
+	inv.MemoryTotalBytes, inv.MemoryAvailableBytes = memory()
+	if data, err := os.ReadFile("/proc/uptime"); err == nil {
+		if fields := strings.Fields(string(data)); len(fields) > 0 {
+			if secs, err := strconv.ParseFloat(fields[0], 64); err == nil {
+				inv.UptimeSeconds = int64(secs)
+			}
+		}
+	}
+	inv.Disks = disks()
+}
+
+func cpuModel() string {
+	f, err := os.Open("/proc/cpuinfo")
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		//x86 has a model name, most arm boards only have the hardware
+		switch strings.TrimSpace(key) {
+		case "model name", "Hardware", "Model":
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+// memory returns the total and available memory in bytes from /proc/meminfo
+func memory() (uint64, uint64) {
+	f, err := os.Open("/proc/meminfo")
+	if err != nil {
+		return 0, 0
+	}
+	defer f.Close()
+
+	var total, available uint64
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		kb, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		switch fields[0] {
+		case "MemTotal:":
+			total = kb * 1024
+		case "MemAvailable:":
+			available = kb * 1024
+		}
+	}
+	return total, available
+}
+
+// disks lists the mounted filesystems that hold data with their size
+func disks() []*pb.Disk {
+	f, err := os.Open("/proc/mounts")
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	res := make([]*pb.Disk, 0)
+	seen := make(map[string]struct{})
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 3 {
+			continue
+		}
+		device, mountpoint, fstype := fields[0], unescapeMount(fields[1]), fields[2]
+		if _, ok := pseudoFilesystems[fstype]; ok {
+			continue
+		}
+		if _, ok := seen[mountpoint]; ok {
+			continue
+		}
+		seen[mountpoint] = struct{}{}
+
+		var st unix.Statfs_t
+		if err := unix.Statfs(mountpoint, &st); err != nil || st.Blocks == 0 {
+			continue
+		}
+		res = append(res, &pb.Disk{
+			Device:     device,
+			Mountpoint: mountpoint,
+			Fstype:     fstype,
+			TotalBytes: st.Blocks * uint64(st.Bsize),
+			FreeBytes:  st.Bavail * uint64(st.Bsize),
+		})
+	}
+	return res
+}
+
+// unescapeMount undoes the octal escaping of spaces and tabs in /proc/mounts
+func unescapeMount(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	r := strings.NewReplacer(`\040`, " ", `\011`, "\t", `\012`, "\n", `\134`, `\`)
+	return r.Replace(s)
+}
@@ -0,0 +1,9 @@
+//go:build !linux
+// +build !linux
+
+package client
+
+import pb "github.com/charles-d-burton/tailsys/commands"
+
+// collectSystem has nothing more to add outside of linux, there is no /proc to read
+func collectSystem(inv *pb.Inventory) {}
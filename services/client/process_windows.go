@@ -0,0 +1,36 @@
+package client
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+)
+
+// defaultInterpreter is used to run commands in shell mode when the request doesn't name one
+var defaultInterpreter = []string{"cmd.exe", "/C"}
+
+// setProcessGroup is a no-op on windows, the process is killed directly
+func setProcessGroup(cmd *exec.Cmd) {}
+
+// interruptProcessGroup kills the command, windows has no way to ask a console process to stop
+func interruptProcessGroup(cmd *exec.Cmd) error {
+	return killProcessGroup(cmd)
+}
+
+// killProcessGroup kills the command
+func killProcessGroup(cmd *exec.Cmd) error {
+	if cmd.Process == nil {
+		return nil
+	}
+	return cmd.Process.Kill()
+}
+
+// exitSignal always returns an empty string since windows processes are not terminated by signals
+func exitSignal(ps *os.ProcessState) string {
+	return ""
+}
+
+// setCredential is not supported on windows
+func setCredential(cmd *exec.Cmd, username string) ([]string, error) {
+	return nil, errors.New("running commands as another user is not supported on windows")
+}
@@ -0,0 +1,105 @@
+//go:build linux
+// +build linux
+
+package client
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// openPTY allocates a pseudo terminal and returns its controlling side along with the terminal for the shell
+func openPTY() (*os.File, *os.File, error) {
+	ptmx, err := os.OpenFile("/dev/ptmx", os.O_RDWR, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	var n uint32
+	err = ptyControl(ptmx, func(fd int) error {
+		if err := unix.IoctlSetPointerInt(fd, unix.TIOCSPTLCK, 0); err != nil {
+			return err
+		}
+		n, err = unix.IoctlGetUint32(fd, unix.TIOCGPTN)
+		return err
+	})
+	if err != nil {
+		ptmx.Close()
+		return nil, nil, fmt.Errorf("unable to unlock pty: %w", err)
+	}
+	tty, err := os.OpenFile(fmt.Sprintf("/dev/pts/%d", n), os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		ptmx.Close()
+		return nil, nil, err
+	}
+	return ptmx, tty, nil
+}
+
+// ptyControl runs fn on the descriptor of the pty. Going through the raw connection keeps the file non-blocking
+// so closing it stops a pending read.
+func ptyControl(ptmx *os.File, fn func(fd int) error) error {
+	rc, err := ptmx.SyscallConn()
+	if err != nil {
+		return err
+	}
+	var fnErr error
+	if err := rc.Control(func(fd uintptr) { fnErr = fn(int(fd)) }); err != nil {
+		return err
+	}
+	return fnErr
+}
+
+func setWindowSize(ptmx *os.File, rows, cols uint32) error {
+	return ptyControl(ptmx, func(fd int) error {
+		return unix.IoctlSetWinsize(fd, unix.TIOCSWINSZ, &unix.Winsize{Row: uint16(rows), Col: uint16(cols)})
+	})
+}
+
+// startOnTerminal starts the command in a new session with the terminal as its controlling terminal
+func startOnTerminal(cmd *exec.Cmd, tty *os.File) error {
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = tty, tty, tty
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setsid = true
+	cmd.SysProcAttr.Setctty = true
+	//the controlling terminal is given as a descriptor of the child, stdin
+	cmd.SysProcAttr.Ctty = 0
+	return cmd.Start()
+}
+
+// signalForeground sends the signal to the process group in the foreground of the terminal, like typing ^C would
+func signalForeground(ptmx *os.File, name string) error {
+	sig := unix.SignalNum(name)
+	if sig == 0 {
+		return fmt.Errorf("unknown signal %s", name)
+	}
+	return ptyControl(ptmx, func(fd int) error {
+		pgrp, err := unix.IoctlGetInt(fd, unix.TIOCGPGRP)
+		if err != nil {
+			return err
+		}
+		return unix.Kill(-pgrp, sig)
+	})
+}
+
+// hangup tells the shell and everything in its session that the terminal has gone away
+func hangup(cmd *exec.Cmd) error {
+	if cmd.Process == nil {
+		return nil
+	}
+	err := syscall.Kill(-cmd.Process.Pid, syscall.SIGHUP)
+	if err == syscall.ESRCH {
+		return os.ErrProcessDone
+	}
+	return err
+}
+
+// isPTYClosed is true for the error reading the pty returns once the shell side of the terminal is closed
+func isPTYClosed(err error) bool {
+	return errors.Is(err, syscall.EIO)
+}
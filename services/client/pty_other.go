@@ -0,0 +1,36 @@
+//go:build !linux
+// +build !linux
+
+package client
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+)
+
+var errNoPTY = errors.New("shells are only supported on linux nodes")
+
+func openPTY() (*os.File, *os.File, error) {
+	return nil, nil, errNoPTY
+}
+
+func setWindowSize(*os.File, uint32, uint32) error {
+	return errNoPTY
+}
+
+func startOnTerminal(*exec.Cmd, *os.File) error {
+	return errNoPTY
+}
+
+func signalForeground(*os.File, string) error {
+	return errNoPTY
+}
+
+func hangup(cmd *exec.Cmd) error {
+	return killProcessGroup(cmd)
+}
+
+func isPTYClosed(error) bool {
+	return false
+}
@@ -2,6 +2,7 @@ package client
 
 import (
 	"context"
+	"crypto/ed25519"
 	"errors"
 	"fmt"
 	"os"
@@ -10,66 +11,143 @@ import (
 	pb "github.com/charles-d-burton/tailsys/commands"
 	"github.com/charles-d-burton/tailsys/connections"
 	"github.com/charles-d-burton/tailsys/data/queries"
-	"google.golang.org/protobuf/types/known/timestamppb"
+	"github.com/charles-d-burton/tailsys/services"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"gopkg.in/yaml.v3"
 )
 
-// RegisterWithCoordinationServer generate the registration request and send it to the coordination server
-func (cl *Client) RegisterWithCoordinationServer(ctx context.Context, addr string) error {
-	for i := 0; i < 5; i++ {
-		fmt.Println("coordination server address: ", addr)
-		ctxTo, cancel := context.WithTimeout(ctx, time.Second*2)
-		defer cancel()
-		sconfig, err := cl.getTlSConfig()
-		if err != nil {
-			return err
-		}
+// PendingRegistrationInterval is how often a node that is waiting to be accepted registers again
+const PendingRegistrationInterval = 30 * time.Second
 
-		conn, err := cl.DialContext(ctxTo, addr, sconfig)
-		if err != nil {
-			return err
-		}
-		defer conn.Close()
-
-		c := pb.NewRegistrationClient(conn)
-
-		fmt.Println("attempting to send registration request")
-		fmt.Println(conn.GetState())
+// RegisterWithCoordinationServer generate the registration request and send it to the coordination server, it
+// returns once the node has been accepted and has a certificate
+func (cl *Client) RegisterWithCoordinationServer(ctx context.Context, addr string) error {
+	sconfig, err := cl.getTlSConfig()
+	if err != nil {
+		return err
+	}
+	if sconfig.CA == "" {
+		return fmt.Errorf("no ca in %s, create it on the coordination server with tailsys ca bundle", cl.ConfigDir+"/certs/server-config.yaml")
+	}
+	//trust the coordination server before we have a certificate of our own
+	if err := cl.SetCertificate("", sconfig.CA, nil); err != nil {
+		return err
+	}
+	csr, err := cl.CertificateRequest()
+	if err != nil {
+		return err
+	}
 
+	attempt := 0
+	for {
+		attempt++
 		req := &pb.NodeRegistrationRequest{
-			Info: &pb.SysInfo{
-				Hostname: cl.Hostname,
-				Port:     cl.Port,
-				Type:     pb.OSType_LINUX,
-				Ip:       cl.Hostname,
-				LastSeen: timestamppb.Now(),
-			},
+			Info:       cl.sysInfo(ctx),
 			Key:        &pb.Key{Key: cl.ID},
 			SystemType: pb.SystemType_CLIENT,
-			Tlskey:     cl.TLSConfig.TLSKey,
-			Tlscert:    cl.TLSConfig.TLSCert,
+			Csr:        csr,
 		}
-		fmt.Println(req)
-		r, err := c.Register(ctx, req)
+		cl.Logger().Info("registering with coordination server", "addr", addr, "hostname", req.GetInfo().GetHostname(), "attempt", attempt)
+		r, err := cl.register(ctx, addr, req)
 
-		if err != nil {
-			fmt.Println(err)
-			time.Sleep(3 * time.Second)
-			if i == 4 {
+		wait := 3 * time.Second
+		switch {
+		case err != nil:
+			cl.Logger().Warn("registration failed", "addr", addr, "err", err)
+			switch status.Code(err) {
+			case codes.AlreadyExists, codes.InvalidArgument, codes.PermissionDenied, codes.Unauthenticated:
+				return err
+			}
+			if attempt == 5 {
 				return errors.New(fmt.Sprintf("unable to connect to coordation server: %s", addr))
 			}
-			continue
+		case !r.Accepted || r.GetCertificate() == nil:
+			if err := cl.addRegistration(r); err != nil {
+				return err
+			}
+			//the certificate is only issued once the node is accepted, keep asking until then
+			cl.Logger().Warn("registration is waiting to be accepted on the coordination server", "retry", PendingRegistrationInterval)
+			attempt = 0
+			wait = PendingRegistrationInterval
+		default:
+			if err := cl.SetCertificate(r.Certificate.Cert, r.Certificate.Ca, r.Certificate.Crl); err != nil {
+				return err
+			}
+			if err := cl.addRegistration(r); err != nil {
+				return err
+			}
+			cl.Logger().Info("registered with coordination server", "coordinator", r.GetHostname(), "accepted", r.Accepted)
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// register sends a single registration request, the connection only lives as long as the attempt
+func (cl *Client) register(ctx context.Context, addr string, req *pb.NodeRegistrationRequest) (*pb.NodeRegistrationResponse, error) {
+	ctxTo, cancel := context.WithTimeout(ctx, time.Second*2)
+	defer cancel()
+
+	conn, err := cl.DialContext(ctxTo, addr)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	return pb.NewRegistrationClient(conn).Register(ctx, req)
+}
+
+// StartCertificateRenewal keeps our certificate and the crl from the coordination server up to date in the background
+func (cl *Client) StartCertificateRenewal(ctx context.Context, addr string) {
+	go func() {
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := cl.renewCertificate(ctx, addr); err != nil {
+					cl.Logger().Error("unable to renew certificate", "err", err)
+				}
+			}
 		}
+	}()
+}
 
-		fmt.Println("registering response")
-		err = cl.addRegistration(r)
+// renewCertificate asks for a new certificate when ours is close to expiring, otherwise it only refreshes the crl
+func (cl *Client) renewCertificate(ctx context.Context, addr string) error {
+	req := &pb.RenewRequest{}
+	if cl.CertificateExpiring() {
+		csr, err := cl.CertificateRequest()
 		if err != nil {
 			return err
 		}
-		fmt.Println(r)
-		break
+		req.Csr = csr
 	}
-	return nil
+
+	ctxTo, cancel := context.WithTimeout(ctx, time.Second*10)
+	defer cancel()
+	conn, err := cl.DialContext(ctxTo, addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	r, err := pb.NewRegistrationClient(conn).Renew(ctxTo, req)
+	if err != nil {
+		return err
+	}
+	if r.Cert != "" {
+		cl.Logger().Info("renewed certificate")
+	}
+	return cl.SetCertificate(r.Cert, r.Ca, r.Crl)
 }
 
 func (cl *Client) getTlSConfig() (*connections.TLSConfig, error) {
@@ -87,11 +165,19 @@ func (cl *Client) getTlSConfig() (*connections.TLSConfig, error) {
 }
 
 func (cl *Client) addRegistration(r *pb.NodeRegistrationResponse) error {
-	fmt.Println("registering server")
-	err := queries.SetRegisteredCoordinationServer(cl.DB, r.GetHostname(), r.GetKey().Key)
+	if len(r.SigningKey) != ed25519.PublicKeySize {
+		return errors.New("coordination server did not send a signing key, commands from it can't be verified")
+	}
+	if services.IdentityID(r.SigningKey) != r.GetKey().Key {
+		return fmt.Errorf("coordination server id %s does not match its signing key", r.GetKey().Key)
+	}
+	err := queries.SetRegisteredCoordinationServer(cl.DB, &queries.RegisteredServerRow{
+		Hostname:   r.GetHostname(),
+		Key:        r.GetKey().Key,
+		SigningKey: r.SigningKey,
+	})
 	if err != nil {
-		fmt.Println("could not register server")
-		return err
+		return fmt.Errorf("unable to store the coordination server: %w", err)
 	}
 	return nil
 }
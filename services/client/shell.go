@@ -0,0 +1,187 @@
+package client
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"time"
+
+	pb "github.com/charles-d-burton/tailsys/commands"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// defaultTerm is the TERM of shells whose request doesn't say
+const defaultTerm = "xterm-256color"
+
+// ShellServer runs the interactive shells the coordination server opens on the node
+type ShellServer struct {
+	pb.UnimplementedShellServer
+	log *slog.Logger
+}
+
+// Session runs a shell on a new pseudo terminal. Input, window size changes and signals arrive on the stream and the
+// output of the terminal goes back on it until the shell exits. The shell is hung up when the stream goes away.
+func (s *ShellServer) Session(stream pb.Shell_SessionServer) error {
+	first, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	req := first.GetRequest()
+	if req == nil || req.GetOpen() == nil {
+		return status.Error(codes.InvalidArgument, "session has to start with a request")
+	}
+	open := req.GetOpen()
+	log := s.log.With("session", req.SessionId, "user", open.User)
+
+	cmd, err := shellCommand(open)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	ptmx, tty, err := openPTY()
+	if err != nil {
+		return status.Errorf(codes.Unavailable, "unable to open a terminal: %v", err)
+	}
+	defer ptmx.Close()
+	if open.GetSize().GetRows() > 0 && open.GetSize().GetCols() > 0 {
+		if err := setWindowSize(ptmx, open.Size.Rows, open.Size.Cols); err != nil {
+			log.Warn("unable to set window size", "err", err)
+		}
+	}
+	start := time.Now()
+	err = startOnTerminal(cmd, tty)
+	//the shell has its own copy, the session only talks to the controlling side
+	tty.Close()
+	if err != nil {
+		return status.Errorf(codes.FailedPrecondition, "unable to start %s: %v", cmd.Path, err)
+	}
+	log.Info("shell started", "command", strings.Join(cmd.Args, " "), "pid", cmd.Process.Pid)
+
+	go func() {
+		for {
+			in, err := stream.Recv()
+			if err != nil {
+				//the operator or coordination server went away, the terminal is gone as far as the shell knows
+				hangup(cmd)
+				return
+			}
+			switch {
+			case in.GetData() != nil:
+				if _, err := ptmx.Write(in.GetData()); err != nil {
+					log.Warn("unable to write to terminal", "err", err)
+				}
+			case in.GetResize() != nil:
+				if err := setWindowSize(ptmx, in.GetResize().Rows, in.GetResize().Cols); err != nil {
+					log.Warn("unable to set window size", "err", err)
+				}
+			case in.GetSignal() != "":
+				if err := signalForeground(ptmx, in.GetSignal()); err != nil {
+					log.Warn("unable to signal shell", "signal", in.GetSignal(), "err", err)
+				}
+			}
+		}
+	}()
+
+	output := make(chan error, 1)
+	go func() {
+		buf := make([]byte, 32*1024)
+		for {
+			n, err := ptmx.Read(buf)
+			if n > 0 {
+				if err := stream.Send(&pb.ShellOutput{Payload: &pb.ShellOutput_Data{Data: buf[:n]}}); err != nil {
+					output <- err
+					return
+				}
+			}
+			if err != nil {
+				output <- err
+				return
+			}
+		}
+	}()
+
+	waitErr := cmd.Wait()
+	//reading stops once nothing has the terminal open anymore, background jobs of the shell may still hold it
+	select {
+	case err = <-output:
+	case <-time.After(time.Second):
+		ptmx.Close()
+		err = <-output
+	}
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, os.ErrClosed) && !isPTYClosed(err) {
+		log.Warn("unable to relay terminal output", "err", err)
+	}
+
+	exit := &pb.ShellExit{Duration: durationpb.New(time.Since(start))}
+	var exitErr *exec.ExitError
+	switch {
+	case errors.As(waitErr, &exitErr):
+		exit.ExitCode = int32(exitErr.ExitCode())
+		exit.Signal = exitSignal(exitErr.ProcessState)
+	case waitErr != nil:
+		exit.ExitCode = -1
+		exit.Error = waitErr.Error()
+	}
+	log.Info("shell exited", "exit_code", exit.ExitCode, "signal", exit.Signal, "duration", time.Since(start))
+	return stream.Send(&pb.ShellOutput{Payload: &pb.ShellOutput_Exit{Exit: exit}})
+}
+
+// shellCommand is the command of the session, the login shell of the user unless the request names one
+func shellCommand(open *pb.ShellOpen) (*exec.Cmd, error) {
+	var u *user.User
+	var err error
+	if open.User != "" {
+		u, err = user.Lookup(open.User)
+	} else {
+		u, err = user.Current()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var cmd *exec.Cmd
+	if len(open.Command) > 0 {
+		cmd = exec.Command(open.Command[0], open.Command[1:]...)
+	} else {
+		shell := loginShell(u.Username)
+		cmd = exec.Command(shell)
+		//a leading dash is how a shell is told it is a login shell
+		cmd.Args[0] = "-" + filepath.Base(shell)
+	}
+
+	env := []string{"HOME=" + u.HomeDir, "USER=" + u.Username, "LOGNAME=" + u.Username}
+	if open.User != "" {
+		if env, err = setCredential(cmd, open.User); err != nil {
+			return nil, err
+		}
+	}
+	term := open.Term
+	if term == "" {
+		term = defaultTerm
+	}
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Env = append(cmd.Env, "TERM="+term, "SHELL="+cmd.Path)
+	cmd.Dir = u.HomeDir
+	if _, err := os.Stat(cmd.Dir); err != nil {
+		cmd.Dir = "/"
+	}
+	return cmd, nil
+}
+
+// loginShell returns the shell of the user from the account database, /bin/sh when there is none
+func loginShell(username string) string {
+	out, err := exec.Command("getent", "passwd", username).Output()
+	if err == nil {
+		fields := strings.Split(strings.TrimSpace(string(out)), ":")
+		if len(fields) >= 7 && fields[6] != "" {
+			return fields[6]
+		}
+	}
+	return "/bin/sh"
+}
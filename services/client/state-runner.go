@@ -0,0 +1,61 @@
+package client
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	pb "github.com/charles-d-burton/tailsys/commands"
+	"github.com/charles-d-burton/tailsys/services/state"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// StateServer applies the states sent by the coordination server
+type StateServer struct {
+	pb.UnimplementedStateRunnerServer
+	log *slog.Logger
+}
+
+// ApplyState applies every resource of the state and reports how each went, a state that doesn't parse isn't
+// applied at all
+func (s *StateServer) ApplyState(ctx context.Context, in *pb.StateRequest) (*pb.StateResponse, error) {
+	start := time.Now()
+	res := &pb.StateResponse{
+		Hostname: in.Hostname,
+		Test:     in.Test,
+	}
+	finish := func() (*pb.StateResponse, error) {
+		res.Timestamp = timestamppb.Now()
+		res.Duration = durationpb.New(time.Since(start))
+		return res, nil
+	}
+
+	st, err := state.Parse(in.State)
+	if err != nil {
+		res.Error = err.Error()
+		return finish()
+	}
+	s.log.Info("applying state", "resources", len(st.Resources), "test", in.Test)
+	res.Successful = true
+	for _, r := range st.Apply(ctx, in.Test, s.log) {
+		rr := &pb.ResourceResult{
+			Id:       r.ID,
+			Changes:  r.Changes,
+			Duration: durationpb.New(r.Duration),
+		}
+		switch r.Status {
+		case state.Changed:
+			rr.Status = pb.ResourceStatus_RESOURCE_CHANGED
+		case state.Failed:
+			rr.Status = pb.ResourceStatus_RESOURCE_FAILED
+			rr.Error = r.Err.Error()
+			res.Successful = false
+		default:
+			rr.Status = pb.ResourceStatus_RESOURCE_UNCHANGED
+		}
+		res.Resources = append(res.Resources, rr)
+	}
+	s.log.Info("state applied", "successful", res.Successful, "test", in.Test, "duration", time.Since(start))
+	return finish()
+}
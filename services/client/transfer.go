@@ -0,0 +1,370 @@
+package client
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	pb "github.com/charles-d-burton/tailsys/commands"
+	"github.com/charles-d-burton/tailsys/services"
+	"github.com/charles-d-burton/tailsys/services/state"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// chunkSize is how much of a file goes in each message of a download
+const chunkSize = 64 * 1024
+
+// TransferServer writes the files the coordination server copies to the node and sends back the ones it fetches
+type TransferServer struct {
+	pb.UnimplementedFileTransferServer
+	log *slog.Logger
+}
+
+// Upload receives the signed header and then the content of the file. The content goes to a temporary file next to
+// the destination that only replaces it once the size and checksum match and the mode and owner are set, so the
+// destination is never left half written. A file that is already there as asked for is left alone.
+func (s *TransferServer) Upload(stream pb.FileTransfer_UploadServer) error {
+	start := time.Now()
+	first, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	header := first.GetHeader()
+	if header == nil || header.GetFile() == nil {
+		return status.Error(codes.InvalidArgument, "upload has to start with a header")
+	}
+	file := header.GetFile()
+	res := &pb.FileUploadResponse{
+		Hostname: header.Hostname,
+		Path:     file.Path,
+	}
+	log := s.log.With("path", file.Path)
+
+	changed, size, err := s.receive(stream, file)
+	if err != nil {
+		log.Warn("unable to write file", "err", err)
+		res.Error = err.Error()
+	} else {
+		log.Info("file written", "size", size, "changed", changed)
+		res.Successful = true
+		res.Changed = changed
+	}
+	res.Size = size
+	res.Timestamp = timestamppb.Now()
+	res.Duration = durationpb.New(time.Since(start))
+	return stream.SendAndClose(res)
+}
+
+// receive writes the content of the stream into place, reporting whether the destination changed
+func (s *TransferServer) receive(stream pb.FileTransfer_UploadServer, file *pb.FileHeader) (bool, uint64, error) {
+	if err := services.CheckPath(file.Path); err != nil {
+		return false, 0, err
+	}
+	if file.Mode > 0o7777 {
+		return false, 0, fmt.Errorf("invalid mode %o", file.Mode)
+	}
+	dir := filepath.Dir(file.Path)
+	if file.Makedirs {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return false, 0, err
+		}
+	} else if _, err := os.Stat(dir); errors.Is(err, fs.ErrNotExist) {
+		return false, 0, fmt.Errorf("directory %s doesn't exist, set makedirs to create it", dir)
+	}
+	current, err := os.Stat(file.Path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return false, 0, err
+	}
+	if current != nil && current.IsDir() {
+		return false, 0, fmt.Errorf("%s is a directory", file.Path)
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(file.Path)+".tailsys-*")
+	if err != nil {
+		return false, 0, err
+	}
+	//once renamed the temporary file is gone and removing it fails quietly
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	sum := sha256.New()
+	size, err := copyChunks(io.MultiWriter(tmp, sum), stream)
+	if err != nil {
+		return false, size, err
+	}
+	if size != file.Size {
+		return false, size, fmt.Errorf("received %d of %d bytes", size, file.Size)
+	}
+	if !bytes.Equal(sum.Sum(nil), file.Sha256) {
+		return false, size, errors.New("checksum doesn't match the content received")
+	}
+	if err := tmp.Sync(); err != nil {
+		return false, size, err
+	}
+	if err := tmp.Close(); err != nil {
+		return false, size, err
+	}
+
+	mode := fs.FileMode(file.Mode)
+	switch {
+	case file.Mode != 0:
+	case current != nil:
+		mode = state.UnixMode(current)
+	default:
+		mode = 0o644
+	}
+	if err := os.Chmod(tmp.Name(), state.GoMode(mode)); err != nil {
+		return false, size, err
+	}
+	if err := chownFile(tmp.Name(), file.Owner, file.Group); err != nil {
+		return false, size, err
+	}
+
+	same, err := sameFile(file.Path, tmp.Name(), file.Sha256)
+	if err != nil {
+		return false, size, err
+	}
+	if same {
+		return false, size, nil
+	}
+	if err := os.Rename(tmp.Name(), file.Path); err != nil {
+		return false, size, err
+	}
+	return true, size, nil
+}
+
+// copyChunks writes the data chunks of the stream until the coordination server closes it
+func copyChunks(w io.Writer, stream pb.FileTransfer_UploadServer) (uint64, error) {
+	var size uint64
+	for {
+		msg, err := stream.Recv()
+		if err == io.EOF {
+			return size, nil
+		}
+		if err != nil {
+			return size, err
+		}
+		if msg.GetHeader() != nil {
+			return size, status.Error(codes.InvalidArgument, "upload may only have one header")
+		}
+		n, err := w.Write(msg.GetData())
+		size += uint64(n)
+		if err != nil {
+			return size, err
+		}
+	}
+}
+
+// chownFile sets the owner and group that are set
+func chownFile(path, owner, group string) error {
+	if owner == "" && group == "" {
+		return nil
+	}
+	uid, gid := -1, -1
+	var err error
+	if owner != "" {
+		if uid, err = state.LookupUser(owner); err != nil {
+			return err
+		}
+	}
+	if group != "" {
+		if gid, err = state.LookupGroup(group); err != nil {
+			return err
+		}
+	}
+	return os.Chown(path, uid, gid)
+}
+
+// sameFile is true when the destination already has the content, mode and owner of the file that would replace it
+func sameFile(dest, tmp string, sum []byte) (bool, error) {
+	want, err := os.Stat(tmp)
+	if err != nil {
+		return false, err
+	}
+	have, err := os.Stat(dest)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if have.Size() != want.Size() || state.UnixMode(have) != state.UnixMode(want) {
+		return false, nil
+	}
+	haveUID, haveGID, err := state.FileOwner(have)
+	if err == nil {
+		wantUID, wantGID, _ := state.FileOwner(want)
+		if haveUID != wantUID || haveGID != wantGID {
+			return false, nil
+		}
+	}
+	current, err := fileSum(dest)
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(current, sum), nil
+}
+
+// fileSum is the sha256 of the content of the file
+func fileSum(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
+
+// Download sends the file, or a tar archive of the directory, in chunks followed by the checksum of what was sent.
+// The download fails once more than the max size of the request would be sent.
+func (s *TransferServer) Download(in *pb.FileDownloadRequest, stream pb.FileTransfer_DownloadServer) error {
+	if err := services.CheckPath(in.Path); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	fi, err := os.Stat(in.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return status.Errorf(codes.NotFound, "%s doesn't exist", in.Path)
+	}
+	if err != nil {
+		return err
+	}
+	if !fi.IsDir() && !fi.Mode().IsRegular() {
+		return status.Errorf(codes.InvalidArgument, "%s is not a regular file or directory", in.Path)
+	}
+	if !fi.IsDir() && uint64(fi.Size()) > in.MaxSize {
+		return status.Errorf(codes.ResourceExhausted, "%s is %d bytes, more than the limit of %d", in.Path, fi.Size(), in.MaxSize)
+	}
+	log := s.log.With("path", in.Path)
+	log.Info("sending file", "directory", fi.IsDir())
+
+	info := &pb.FileInfo{Path: in.Path, Directory: fi.IsDir(), Mode: uint32(state.UnixMode(fi))}
+	if err := stream.Send(&pb.FileChunk{Payload: &pb.FileChunk_Info{Info: info}}); err != nil {
+		return err
+	}
+	w := &chunkWriter{stream: stream, limit: in.MaxSize, sum: sha256.New()}
+	if fi.IsDir() {
+		err = writeTar(stream.Context(), w, in.Path)
+	} else {
+		err = copyFile(w, in.Path)
+	}
+	if err == nil {
+		err = w.flush()
+	}
+	if err != nil {
+		log.Warn("unable to send file", "err", err)
+		return err
+	}
+	log.Info("file sent", "size", w.size)
+	done := &pb.FileDone{Size: w.size, Sha256: w.sum.Sum(nil)}
+	return stream.Send(&pb.FileChunk{Payload: &pb.FileChunk_Done{Done: done}})
+}
+
+// chunkWriter sends what is written to it in chunks of ChunkSize, hashing it and failing past the limit
+type chunkWriter struct {
+	stream pb.FileTransfer_DownloadServer
+	limit  uint64
+	size   uint64
+	sum    hash.Hash
+	buf    []byte
+}
+
+func (w *chunkWriter) Write(p []byte) (int, error) {
+	if w.size+uint64(len(p)) > w.limit {
+		return 0, status.Errorf(codes.ResourceExhausted, "more than the limit of %d bytes", w.limit)
+	}
+	w.size += uint64(len(p))
+	w.sum.Write(p)
+	w.buf = append(w.buf, p...)
+	for len(w.buf) >= chunkSize {
+		if err := w.send(w.buf[:chunkSize]); err != nil {
+			return 0, err
+		}
+		w.buf = w.buf[chunkSize:]
+	}
+	return len(p), nil
+}
+
+// flush sends what is left of the last chunk
+func (w *chunkWriter) flush() error {
+	if len(w.buf) == 0 {
+		return nil
+	}
+	err := w.send(w.buf)
+	w.buf = nil
+	return err
+}
+
+func (w *chunkWriter) send(data []byte) error {
+	return w.stream.Send(&pb.FileChunk{Payload: &pb.FileChunk_Data{Data: data}})
+}
+
+func copyFile(w io.Writer, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(w, f)
+	return err
+}
+
+// writeTar archives the regular files and directories under root with names relative to it, anything else like
+// symlinks and sockets is left out
+func writeTar(ctx context.Context, w io.Writer, root string) error {
+	tw := tar.NewWriter(w)
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if path == root || (!d.IsDir() && !d.Type().IsRegular()) {
+			return nil
+		}
+		fi, err := d.Info()
+		if err != nil {
+			return err
+		}
+		hdr, err := tar.FileInfoHeader(fi, "")
+		if err != nil {
+			return err
+		}
+		name, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		hdr.Name = filepath.ToSlash(name)
+		if d.IsDir() {
+			hdr.Name += "/"
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		return copyFile(tw, path)
+	})
+	if err != nil {
+		return err
+	}
+	return tw.Close()
+}
@@ -0,0 +1,179 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	pb "github.com/charles-d-burton/tailsys/commands"
+	"github.com/charles-d-burton/tailsys/connections"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// DefaultHeartbeatInterval is how often heartbeats are sent through the tunnel when no interval is configured
+const DefaultHeartbeatInterval = 10 * time.Second
+
+// inventoryCheckInterval is how often a node in reverse mode checks whether its inventory changed
+const inventoryCheckInterval = time.Minute
+
+// maxTunnelBackoff is the longest wait between attempts to open the tunnel again
+const maxTunnelBackoff = time.Minute
+
+// StartTunnel keeps a tunnel to the coordination server open in the background, opening it again whenever it is lost.
+// StartRPCClientMode then serves the coordination server through the tunnel instead of a listener and closes the
+// tunnel once it stopped, so commands still running when ctx is done can finish.
+func (cl *Client) StartTunnel(ctx context.Context, addr string, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultHeartbeatInterval
+	}
+	ctx, cl.stopTunnel = context.WithCancel(context.WithoutCancel(ctx))
+	cl.tunnel = connections.NewTunnelListener(connections.TunnelAddr(nil, cl.Hostname))
+	go func() {
+		wait := time.Second
+		for {
+			opened := time.Now()
+			err := cl.runTunnel(ctx, addr, interval)
+			if ctx.Err() != nil {
+				return
+			}
+			//a tunnel that stayed up for a while was lost, not refused, so try again quickly
+			if time.Since(opened) > maxTunnelBackoff {
+				wait = time.Second
+			}
+			cl.Logger().Warn("tunnel lost, opening it again", "addr", addr, "wait", wait, "err", err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(wait):
+			}
+			wait = min(wait*2, maxTunnelBackoff)
+		}
+	}()
+}
+
+// runTunnel opens the tunnel and keeps it open until it fails. The coordination server gets a connection to our gRPC
+// server through it, heartbeats are sent every interval and the inventory whenever it changed.
+func (cl *Client) runTunnel(ctx context.Context, addr string, interval time.Duration) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	conn, err := cl.DialContext(ctx, addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	stream, err := pb.NewTunnelClient(conn).Connect(ctx)
+	if err != nil {
+		return err
+	}
+
+	var sendMu sync.Mutex
+	send := func(f *pb.TunnelFrame) error {
+		sendMu.Lock()
+		defer sendMu.Unlock()
+		return stream.Send(f)
+	}
+	tc := connections.NewTunnelConn(
+		func(data []byte) error { return send(&pb.TunnelFrame{Frame: &pb.TunnelFrame_Data{Data: data}}) },
+		connections.TunnelAddr(nil, cl.Hostname),
+		connections.TunnelAddr(nil, addr),
+	)
+	defer tc.Close()
+
+	heartbeat := func() error {
+		return send(&pb.TunnelFrame{Frame: &pb.TunnelFrame_Heartbeat{Heartbeat: &pb.Heartbeat{
+			Sent:     timestamppb.Now(),
+			Interval: durationpb.New(interval),
+		}}})
+	}
+	//the first heartbeat tells the coordination server how long to wait for the next one
+	if err := heartbeat(); err != nil {
+		return err
+	}
+	var sent *pb.Inventory
+	sendInventory := func() error {
+		inv := cl.sysInfo(ctx).Inventory
+		if sent != nil && !inventoryChanged(sent, inv) {
+			return nil
+		}
+		if err := send(&pb.TunnelFrame{Frame: &pb.TunnelFrame_Inventory{Inventory: inv}}); err != nil {
+			return err
+		}
+		sent = inv
+		return nil
+	}
+	if err := sendInventory(); err != nil {
+		return err
+	}
+	if err := cl.tunnel.Push(tc); err != nil {
+		return err
+	}
+	cl.Logger().Info("connected through a tunnel", "addr", addr)
+
+	acks := make(chan struct{}, 1)
+	errc := make(chan error, 1)
+	go func() {
+		for {
+			f, err := stream.Recv()
+			if err != nil {
+				tc.CloseWithError(err)
+				errc <- err
+				return
+			}
+			switch frame := f.Frame.(type) {
+			case *pb.TunnelFrame_Data:
+				tc.Deliver(frame.Data)
+			case *pb.TunnelFrame_Heartbeat:
+				select {
+				case acks <- struct{}{}:
+				default:
+				}
+			}
+		}
+	}()
+
+	beats := time.NewTicker(interval)
+	defer beats.Stop()
+	inventory := time.NewTicker(inventoryCheckInterval)
+	defer inventory.Stop()
+	lastAck := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-errc:
+			return err
+		case <-acks:
+			lastAck = time.Now()
+		case <-beats.C:
+			if time.Since(lastAck) > 3*interval {
+				return errors.New("coordination server stopped answering heartbeats")
+			}
+			if err := heartbeat(); err != nil {
+				return err
+			}
+		case <-inventory.C:
+			if err := sendInventory(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// inventoryChanged compares the inventories without the values that change all the time like free memory and uptime
+func inventoryChanged(a, b *pb.Inventory) bool {
+	stable := func(inv *pb.Inventory) *pb.Inventory {
+		inv = proto.Clone(inv).(*pb.Inventory)
+		inv.MemoryAvailableBytes = 0
+		inv.UptimeSeconds = 0
+		inv.Collected = nil
+		for _, d := range inv.Disks {
+			d.FreeBytes = 0
+		}
+		return inv
+	}
+	return !proto.Equal(stable(a), stable(b))
+}
@@ -0,0 +1,72 @@
+package client
+
+import (
+	"context"
+	"crypto/ed25519"
+
+	pb "github.com/charles-d-burton/tailsys/commands"
+	"github.com/charles-d-burton/tailsys/connections"
+	"github.com/charles-d-burton/tailsys/data/queries"
+	"github.com/charles-d-burton/tailsys/services"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// unsignedMethods are the methods that don't carry a signature. Anyone on the tailnet may ping a node, the inventory
+// may only be asked for by the coordination server.
+var unsignedMethods = map[string]bool{
+	"/tailsys.Pinger/Ping":        true,
+	"/tailsys.SystemInfo/SysInfo": true,
+}
+
+// Authorize only lets the coordination server we registered with call the node. The caller has to be that server
+// on the tailnet and in its certificate, and every request other than the unsigned methods has to carry its signature.
+func (cl *Client) Authorize(ctx context.Context, method string, req any) error {
+	if method == "/tailsys.Pinger/Ping" {
+		return nil
+	}
+	cert := connections.PeerCertificate(ctx)
+	id := connections.IdentityFromContext(ctx)
+	if cert == nil || id == nil {
+		return status.Errorf(codes.Unauthenticated, "unable to identify the caller of %s", method)
+	}
+	server, err := queries.GetCoordinationServerByHostname(cl.DB, cert.Subject.CommonName)
+	if err != nil {
+		return cl.deny(method, "%s is not a registered coordination server", cert.Subject.CommonName)
+	}
+	if id.Node != server.Hostname {
+		return cl.deny(method, "certificate for %s was presented by %s", server.Hostname, id)
+	}
+	if unsignedMethods[method] {
+		return nil
+	}
+
+	switch in := req.(type) {
+	case *pb.FileUpload:
+		//uploads are signed in their header, the chunks after it belong to the upload the header was verified for
+		if in.GetHeader() == nil {
+			return nil
+		}
+		req = in.GetHeader()
+	case *pb.ShellInput:
+		//shells are signed in the request that opens them, input after it belongs to the session that was verified
+		if in.GetRequest() == nil {
+			return nil
+		}
+		req = in.GetRequest()
+	}
+	in, ok := req.(services.SignedRequest)
+	if !ok {
+		return cl.deny(method, "%s does not take unsigned requests", method)
+	}
+	if err := cl.verifier.Verify(ed25519.PublicKey(server.SigningKey), cl.Hostname, in); err != nil {
+		return cl.deny(method, "request from %s rejected: %v", server.Hostname, err)
+	}
+	return nil
+}
+
+func (cl *Client) deny(method, format string, args ...any) error {
+	err := status.Errorf(codes.PermissionDenied, format, args...)
+	cl.Logger().Warn("denied request", "method", method, "err", err)
+	return err
+}
@@ -1,9 +1,10 @@
-package commander 
+package commander
 
 import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 	"time"
@@ -12,13 +13,20 @@ import (
 	"github.com/charles-d-burton/tailsys/connections"
 	"github.com/google/uuid"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"gopkg.in/yaml.v3"
 )
 
+// chunkSize is how much of a file goes in each message sent to the coordination server
+const chunkSize = 64 * 1024
+
 type Client struct {
 	connections.Tailnet
 	CoordinationServer string
 	ID                 string
+	// Output is the format results are written in, table when not set
+	Output Format
 	// TLS                *connections.TLSConfig
 }
 
@@ -67,95 +75,648 @@ func (cl *Client) getTlSConfig() (*connections.TLSConfig, error) {
 func (cl *Client) getConn(ctx context.Context) (*grpc.ClientConn, error) {
 	ctxTo, cancel := context.WithTimeout(ctx, time.Second*30)
 	defer cancel()
-	return cl.DialContext(ctxTo, cl.CoordinationServer, cl.TLSConfig)
-}
-
-func (cl *Client) SendCommand(ctx context.Context, cmd string, pattern string) error {
-  for i := range 5 {
-
-    if cl.TLSConfig == nil {
-      tls, err := cl.getTlSConfig()
-      if err != nil {
-        return err
-      }
-      cl.TLSConfig = tls
-    }
-
-    command := &pb.CommanderRequest{
-      Command: cmd,
-      Pattern: pattern,
-    }
-    conn, err := cl.getConn(ctx)
-
-    if err != nil {
-      if i < 5 {
-        fmt.Println(fmt.Errorf("unable to connect: %w", err))
-        time.Sleep(5 * time.Second)
-        fmt.Println("retrying connection")
-        continue
-      }
-      return err
-    }
-    cc := pb.NewCommandManagerClient(conn)
-    r, err := cc.SendCommandToNodes(ctx, command)
-    if err != nil {
-      if i < 5 {
-        fmt.Println(fmt.Errorf("unable to send command: %w", err))
-        time.Sleep(5 * time.Second)
-        fmt.Println("retrying sending command")
-        continue
-      }
-      return err
-    }
-    for _, res := range r.Response {
-      fmt.Printf("command: %s ran with exit code %d\n", cmd, res.ExitCode)
-      fmt.Printf("  result: %s\n", string(res.GetOutput()))
-    }
-    return nil
-  }
-	return nil
+	return cl.DialContext(ctxTo, cl.CoordinationServer)
+}
+
+// withManager connects to the coordination server and hands the connection to fn, retrying while the server is unavailable
+func (cl *Client) withManager(ctx context.Context, fn func(pb.CommandManagerClient) error) error {
+	if cl.TLSConfig == nil {
+		tls, err := cl.getTlSConfig()
+		if err != nil {
+			return err
+		}
+		if tls.TLSCert == "" || tls.CA == "" {
+			return fmt.Errorf("%s has no operator certificate, create one on the coordination server with tailsys ca issue", cl.ConfigDir+"/certs/server-config.yaml")
+		}
+		cl.TLSConfig = tls
+		if err := cl.SetCRL([]byte(tls.CRL)); err != nil {
+			return err
+		}
+	}
+
+	var err error
+	for i := range 5 {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(5 * time.Second):
+			}
+			cl.Logger().Info("retrying connection", "addr", cl.CoordinationServer, "attempt", i+1)
+		}
+
+		var conn *grpc.ClientConn
+		conn, err = cl.getConn(ctx)
+		if err != nil {
+			cl.Logger().Warn("unable to connect", "addr", cl.CoordinationServer, "err", err)
+			continue
+		}
+
+		err = fn(pb.NewCommandManagerClient(conn))
+		conn.Close()
+		if status.Code(err) != codes.Unavailable {
+			return err
+		}
+		cl.Logger().Warn("coordination server unavailable", "addr", cl.CoordinationServer, "err", err)
+	}
+	return err
+}
+
+// SendCommand runs the command on the matching nodes and prints the output of each host as it arrives, or a record
+// for each host as it finishes in the structured formats. With failFast the job is canceled as soon as a host fails.
+// A *FailedError is returned when the command failed on any host.
+func (cl *Client) SendCommand(ctx context.Context, command *pb.CommanderRequest, failFast bool) error {
+	return cl.withManager(ctx, func(cc pb.CommandManagerClient) error {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		stream, err := cc.SendCommandToNodesStream(ctx, command)
+		if err != nil {
+			return err
+		}
+
+		out := newHostPrinter(os.Stdout, os.Stderr)
+		enc := cl.encoder(os.Stdout)
+		results := newHostResults()
+		jobID := ""
+		finished, failed := 0, 0
+		stopped := false
+		for {
+			r, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				if jobID == "" {
+					return err
+				}
+				//the command is already running, don't retry and run it a second time
+				return fmt.Errorf("lost connection to coordination server during job %s: %v", jobID, err)
+			}
+			if jobID == "" {
+				jobID = r.JobId
+				if enc == nil {
+					fmt.Printf("job: %s\n", jobID)
+				}
+			}
+			switch payload := r.Payload.(type) {
+			case *pb.CommandStreamResponse_Output:
+				if enc == nil {
+					out.write(r.Hostname, payload.Output.Stream, payload.Output.Data)
+				} else {
+					results.write(r.Hostname, payload.Output)
+				}
+			case *pb.CommandStreamResponse_Exit:
+				finished++
+				if !payload.Exit.Successful {
+					failed++
+				}
+				if enc == nil {
+					out.flush(r.Hostname)
+					out.exit(r.Hostname, payload.Exit)
+				} else if err := enc.add(results.exit(r.Hostname, jobID, payload.Exit)); err != nil {
+					return err
+				}
+			}
+			if failFast && failed > 0 {
+				//only report the job as stopped when some hosts were still to finish
+				stopped = jobPending(ctx, cc, jobID)
+				//closing the stream cancels the job on the coordination server
+				cancel()
+				break
+			}
+		}
+		if enc != nil {
+			if err := enc.close(); err != nil {
+				return err
+			}
+		}
+		if failed > 0 {
+			return &FailedError{Failed: failed, Nodes: finished, Stopped: stopped}
+		}
+		return nil
+	})
+}
+
+// ApplyState applies a state on the nodes and prints the result of every resource as each node finishes. A
+// *FailedError is returned when the state failed on any node.
+func (cl *Client) ApplyState(ctx context.Context, req *pb.StateApplyRequest) error {
+	return cl.withManager(ctx, func(cc pb.CommandManagerClient) error {
+		stream, err := cc.ApplyState(ctx, req)
+		if err != nil {
+			return err
+		}
+		enc := cl.encoder(os.Stdout)
+		received, failed := 0, 0
+		for {
+			res, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				if received == 0 {
+					return err
+				}
+				//the state is already being applied, don't retry and apply it a second time
+				return fmt.Errorf("lost connection to coordination server while applying state: %v", err)
+			}
+			received++
+			if !res.Successful {
+				failed++
+			}
+			if enc == nil {
+				printState(os.Stdout, res)
+				continue
+			}
+			record, err := protoRecord(res)
+			if err != nil {
+				return err
+			}
+			if err := enc.add(record); err != nil {
+				return err
+			}
+		}
+		if enc != nil {
+			if err := enc.close(); err != nil {
+				return err
+			}
+		}
+		if failed > 0 {
+			return &FailedError{Failed: failed, Nodes: received}
+		}
+		return nil
+	})
+}
+
+// jobPending reports whether any host of the job is still waiting for the command or running it, it assumes so when
+// the job can't be read
+func jobPending(ctx context.Context, cc pb.CommandManagerClient, jobID string) bool {
+	job, err := cc.GetJob(ctx, &pb.JobID{JobId: jobID})
+	if err != nil {
+		return true
+	}
+	for _, rec := range job.Records {
+		if rec.Status == pb.HostStatus_HOST_PENDING || rec.Status == pb.HostStatus_HOST_RUNNING {
+			return true
+		}
+	}
+	return false
+}
+
+// SendFile copies the content to the nodes matching the pattern and prints how each node wrote it. The content is
+// read again from the start when the connection has to be retried. A *FailedError is returned when any node failed.
+func (cl *Client) SendFile(ctx context.Context, pattern string, file *pb.FileHeader, content io.ReadSeeker) error {
+	return cl.withManager(ctx, func(cc pb.CommandManagerClient) error {
+		if _, err := content.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		stream, err := cc.SendFile(ctx)
+		if err != nil {
+			return err
+		}
+		header := &pb.FileSendHeader{Pattern: pattern, File: file}
+		if err := stream.Send(&pb.FileSend{Payload: &pb.FileSend_Header{Header: header}}); err != nil {
+			return sendErr(stream, err)
+		}
+		buf := make([]byte, chunkSize)
+		for {
+			n, err := content.Read(buf)
+			if n > 0 {
+				if err := stream.Send(&pb.FileSend{Payload: &pb.FileSend_Data{Data: buf[:n]}}); err != nil {
+					return sendErr(stream, err)
+				}
+			}
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return err
+			}
+		}
+		if err := stream.CloseSend(); err != nil {
+			return err
+		}
+
+		enc := cl.encoder(os.Stdout)
+		received, failed := 0, 0
+		for {
+			res, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				if received == 0 {
+					return err
+				}
+				//some nodes already have the file, don't retry and send it again
+				return fmt.Errorf("lost connection to coordination server while copying file: %v", err)
+			}
+			received++
+			if !res.Successful {
+				failed++
+			}
+			if enc == nil {
+				printTransfer(os.Stdout, res)
+			} else if err := enc.add(uploadResult(res)); err != nil {
+				return err
+			}
+		}
+		if enc == nil {
+			fmt.Printf("copied %s to %d of %d nodes\n", file.Path, received-failed, received)
+		} else if err := enc.close(); err != nil {
+			return err
+		}
+		if failed > 0 {
+			return &FailedError{Failed: failed, Nodes: received}
+		}
+		return nil
+	})
+}
+
+// FetchFile downloads the file or directory from the nodes into dir/<hostname>/<path> and prints how each node went.
+// A *FailedError is returned when any node failed.
+func (cl *Client) FetchFile(ctx context.Context, req *pb.FileFetchRequest, dir string) error {
+	return cl.withManager(ctx, func(cc pb.CommandManagerClient) error {
+		stream, err := cc.FetchFile(ctx, req)
+		if err != nil {
+			return err
+		}
+		downloads := make(map[string]*download)
+		//aborting removes the temporary files of the downloads that didn't finish
+		defer func() {
+			for _, dl := range downloads {
+				dl.abort(errors.New("fetch stopped"))
+			}
+		}()
+		enc := cl.encoder(os.Stdout)
+		var encErr error
+		received, fetched, failed := 0, 0, 0
+		fail := func(host string, err error) {
+			if dl := downloads[host]; dl != nil {
+				dl.abort(err)
+				delete(downloads, host)
+			}
+			failed++
+			if enc == nil {
+				fmt.Fprintf(os.Stdout, "%s: error: %v\n", host, err)
+				return
+			}
+			if err := enc.add(&TransferResult{Host: host, Path: req.Path, Error: err.Error()}); err != nil {
+				encErr = err
+			}
+		}
+		for {
+			res, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				if received == 0 {
+					return err
+				}
+				return fmt.Errorf("lost connection to coordination server while fetching files: %v", err)
+			}
+			received++
+			host := res.Hostname
+			if res.Error != "" {
+				fail(host, errors.New(res.Error))
+				continue
+			}
+			chunk := res.GetChunk()
+			dl := downloads[host]
+			switch {
+			case chunk.GetInfo() != nil:
+				dl, err := newDownload(dir, host, req.Path, chunk.GetInfo())
+				if err != nil {
+					fail(host, err)
+					continue
+				}
+				downloads[host] = dl
+			case dl == nil:
+				//the download of the host already failed here, the rest of what it sends is dropped
+			case chunk.GetDone() != nil:
+				delete(downloads, host)
+				files, err := dl.finish(chunk.GetDone())
+				if err != nil {
+					fail(host, err)
+					continue
+				}
+				fetched++
+				if enc == nil {
+					printFetch(os.Stdout, dl, files)
+				} else if err := enc.add(fetchResult(dl, files)); err != nil {
+					return err
+				}
+			default:
+				if err := dl.write(chunk.GetData()); err != nil {
+					fail(host, err)
+				}
+			}
+		}
+		if encErr != nil {
+			return encErr
+		}
+		if enc == nil {
+			fmt.Printf("fetched %s from %d of %d nodes\n", req.Path, fetched, fetched+failed)
+		} else if err := enc.close(); err != nil {
+			return err
+		}
+		if failed > 0 {
+			return &FailedError{Failed: failed, Nodes: fetched + failed}
+		}
+		return nil
+	})
+}
+
+// sendErr returns why the coordination server stopped the transfer, a failed Send only says the stream is gone
+func sendErr(stream pb.CommandManager_SendFileClient, err error) error {
+	if errors.Is(err, io.EOF) {
+		_, err = stream.Recv()
+	}
+	return err
 }
 
+// GetNodes prints the accepted nodes matching the pattern
 func (cl *Client) GetNodes(ctx context.Context, pattern string) error {
-  for i := range 5 {
-
-    if cl.TLSConfig == nil {
-      tls, err := cl.getTlSConfig()
-      if err != nil {
-        return err
-      }
-      cl.TLSConfig = tls
-    }
-    gn := &pb.NodeQuery{
-      Pattern: pattern,
-    }
-
-    conn, err := cl.getConn(ctx)
-    if err != nil {
-      if i < 5 {
-        fmt.Println(fmt.Errorf("unable to connect: %w", err))
-        time.Sleep(5 * time.Second)
-        fmt.Println("retrying connection")
-        continue
-      }
-      return err
-    }
-
-    cc := pb.NewCommandManagerClient(conn)
-    r, err := cc.GetNodes(ctx, gn)
-    if err != nil {
-      if i < 5 {
-        fmt.Println(fmt.Errorf("unable to get nodes: %w", err))
-        time.Sleep(5 * time.Second)
-        fmt.Println("retrying getting nodes")
-        continue
-      }
-      return err
-    }
-    for _, res := range r.Nodes {
-      fmt.Printf("found node %s\n", res)
-    }
-    return nil
-  }
-	return nil
+	return cl.withManager(ctx, func(cc pb.CommandManagerClient) error {
+		r, err := cc.GetNodes(ctx, &pb.NodeQuery{
+			Pattern: pattern,
+		})
+		if err != nil {
+			return err
+		}
+		enc := cl.encoder(os.Stdout)
+		if enc == nil {
+			printNodes(os.Stdout, r.Nodes)
+			fmt.Printf("%d nodes match %q\n", len(r.Nodes), pattern)
+			return nil
+		}
+		for _, node := range r.Nodes {
+			if err := enc.add(map[string]string{"hostname": node}); err != nil {
+				return err
+			}
+		}
+		return enc.close()
+	})
+}
+
+// History prints the most recent jobs sent through the coordination server
+func (cl *Client) History(ctx context.Context, limit int) error {
+	return cl.withManager(ctx, func(cc pb.CommandManagerClient) error {
+		r, err := cc.ListJobs(ctx, &pb.JobQuery{
+			Limit: int32(limit),
+		})
+		if err != nil {
+			return err
+		}
+		enc := cl.encoder(os.Stdout)
+		if enc == nil {
+			printJobs(os.Stdout, r.Jobs)
+			return nil
+		}
+		for _, job := range r.Jobs {
+			record, err := protoRecord(job)
+			if err != nil {
+				return err
+			}
+			if err := enc.add(record); err != nil {
+				return err
+			}
+		}
+		return enc.close()
+	})
+}
+
+// ShowJob prints a job along with the result from every host it ran on
+func (cl *Client) ShowJob(ctx context.Context, jobID string) error {
+	return cl.withManager(ctx, func(cc pb.CommandManagerClient) error {
+		r, err := cc.GetJob(ctx, &pb.JobID{
+			JobId: jobID,
+		})
+		if err != nil {
+			return err
+		}
+		return cl.printJob(r)
+	})
+}
+
+// printJob writes the job and the result from each host in the output format
+func (cl *Client) printJob(job *pb.Job) error {
+	enc := cl.encoder(os.Stdout)
+	if enc == nil {
+		printJob(os.Stdout, job)
+		return nil
+	}
+	record, err := jobRecord(job)
+	if err != nil {
+		return err
+	}
+	return enc.one(record)
+}
+
+// SubmitJob starts the command on the matching nodes without waiting for it and prints the job id
+func (cl *Client) SubmitJob(ctx context.Context, command *pb.CommanderRequest) error {
+	return cl.withManager(ctx, func(cc pb.CommandManagerClient) error {
+		r, err := cc.SubmitJob(ctx, command)
+		if err != nil {
+			return err
+		}
+		if enc := cl.encoder(os.Stdout); enc != nil {
+			return enc.one(map[string]string{"jobId": r.JobId})
+		}
+		fmt.Println(r.JobId)
+		return nil
+	})
+}
+
+// WatchJob prints everything that happens on a job until it finishes, or a record for each host as it finishes in
+// the structured formats. A *FailedError is returned when the command failed on any host.
+func (cl *Client) WatchJob(ctx context.Context, jobID string) error {
+	return cl.withManager(ctx, func(cc pb.CommandManagerClient) error {
+		stream, err := cc.WatchJob(ctx, &pb.JobID{
+			JobId: jobID,
+		})
+		if err != nil {
+			return err
+		}
+
+		out := newHostPrinter(os.Stdout, os.Stderr)
+		enc := cl.encoder(os.Stdout)
+		results := newHostResults()
+		finished, failed := 0, 0
+		for {
+			ev, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return err
+			}
+			if exit := ev.GetExit(); exit != nil {
+				finished++
+				if !exit.Successful {
+					failed++
+				}
+			}
+			if enc == nil {
+				out.event(ev)
+				continue
+			}
+			switch e := ev.Event.(type) {
+			case *pb.JobEvent_Output:
+				results.write(ev.Hostname, e.Output)
+			case *pb.JobEvent_Exit:
+				if err := enc.add(results.exit(ev.Hostname, ev.JobId, e.Exit)); err != nil {
+					return err
+				}
+			}
+		}
+		if enc != nil {
+			if err := enc.close(); err != nil {
+				return err
+			}
+		}
+		if failed > 0 {
+			return &FailedError{Failed: failed, Nodes: finished}
+		}
+		return nil
+	})
+}
+
+// CancelJob stops a running job and prints where it got to
+func (cl *Client) CancelJob(ctx context.Context, jobID string) error {
+	return cl.withManager(ctx, func(cc pb.CommandManagerClient) error {
+		r, err := cc.CancelJob(ctx, &pb.JobID{
+			JobId: jobID,
+		})
+		if err != nil {
+			return err
+		}
+		return cl.printJob(r)
+	})
+}
+
+// ListKeys prints the nodes matching the query and whether they are accepted
+func (cl *Client) ListKeys(ctx context.Context, query *pb.KeyQuery) error {
+	return cl.withManager(ctx, func(cc pb.CommandManagerClient) error {
+		r, err := cc.ListKeys(ctx, query)
+		if err != nil {
+			return err
+		}
+		return cl.writeKeys(r.Keys, "")
+	})
+}
+
+// writeKeys writes the keys, as a table after saying how many were changed when done is set
+func (cl *Client) writeKeys(keys []*pb.NodeKey, done string) error {
+	enc := cl.encoder(os.Stdout)
+	if enc == nil {
+		if done != "" {
+			fmt.Printf("%s %d keys\n", done, len(keys))
+		}
+		printKeys(os.Stdout, keys)
+		return nil
+	}
+	if err := addProtos(enc, keys); err != nil {
+		return err
+	}
+	return enc.close()
+}
+
+// AcceptKeys allows the matching nodes to receive commands and prints the nodes that were accepted
+func (cl *Client) AcceptKeys(ctx context.Context, query *pb.KeyQuery) error {
+	return cl.withManager(ctx, func(cc pb.CommandManagerClient) error {
+		r, err := cc.AcceptKeys(ctx, query)
+		if err != nil {
+			return err
+		}
+		return cl.writeKeys(r.Keys, "accepted")
+	})
+}
+
+// RejectKeys rejects or revokes the matching nodes and prints the nodes that were changed
+func (cl *Client) RejectKeys(ctx context.Context, query *pb.KeyQuery) error {
+	return cl.withManager(ctx, func(cc pb.CommandManagerClient) error {
+		r, err := cc.RejectKeys(ctx, query)
+		if err != nil {
+			return err
+		}
+		return cl.writeKeys(r.Keys, "rejected")
+	})
+}
+
+// DeleteKeys removes the matching nodes and prints the nodes that were deleted
+func (cl *Client) DeleteKeys(ctx context.Context, query *pb.KeyQuery) error {
+	return cl.withManager(ctx, func(cc pb.CommandManagerClient) error {
+		r, err := cc.DeleteKeys(ctx, query)
+		if err != nil {
+			return err
+		}
+		return cl.writeKeys(r.Keys, "deleted")
+	})
+}
+
+// GetInventory prints the inventory of the nodes matching the query along with any node that couldn't be refreshed
+func (cl *Client) GetInventory(ctx context.Context, query *pb.InventoryQuery, long bool) error {
+	return cl.withManager(ctx, func(cc pb.CommandManagerClient) error {
+		r, err := cc.GetInventory(ctx, query)
+		if err != nil {
+			return err
+		}
+		for host, msg := range r.Errors {
+			fmt.Fprintf(os.Stderr, "[%s] error: %s\n", host, msg)
+		}
+		enc := cl.encoder(os.Stdout)
+		if enc == nil {
+			printInventory(os.Stdout, r.Nodes, long)
+			return nil
+		}
+		if err := addProtos(enc, r.Nodes); err != nil {
+			return err
+		}
+		return enc.close()
+	})
+}
+
+// DiscoverNodes prints the tagged devices that haven't registered and the accepted nodes that left the tailnet
+func (cl *Client) DiscoverNodes(ctx context.Context, refresh, all bool) error {
+	return cl.withManager(ctx, func(cc pb.CommandManagerClient) error {
+		r, err := cc.DiscoverNodes(ctx, &pb.DiscoveryQuery{Refresh: refresh})
+		if err != nil {
+			return err
+		}
+		enc := cl.encoder(os.Stdout)
+		if enc == nil {
+			printDiscovery(os.Stdout, r, all)
+			return nil
+		}
+		nodes := make([]*pb.DiscoveredNode, 0, len(r.Nodes))
+		for _, node := range r.Nodes {
+			if all || node.State != pb.DiscoveryState_DISCOVERY_REGISTERED {
+				nodes = append(nodes, node)
+			}
+		}
+		if err := addProtos(enc, nodes); err != nil {
+			return err
+		}
+		return enc.close()
+	})
+}
+
+// NodeStatus prints the health of the nodes matching the pattern with up to history of their latest pings
+func (cl *Client) NodeStatus(ctx context.Context, pattern string, history int) error {
+	return cl.withManager(ctx, func(cc pb.CommandManagerClient) error {
+		r, err := cc.GetNodeStatus(ctx, &pb.NodeStatusQuery{Pattern: pattern, History: int32(history)})
+		if err != nil {
+			return err
+		}
+		enc := cl.encoder(os.Stdout)
+		if enc == nil {
+			printNodeStatus(os.Stdout, r.Nodes)
+			return nil
+		}
+		if err := addProtos(enc, r.Nodes); err != nil {
+			return err
+		}
+		return enc.close()
+	})
 }
@@ -0,0 +1,169 @@
+package commander
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	pb "github.com/charles-d-burton/tailsys/commands"
+)
+
+// download writes what one node sends into dir/<hostname>/<path>. A file goes to a temporary file that is renamed
+// once its checksum matches, a directory is extracted from the tar archive as it arrives.
+type download struct {
+	hostname string
+	dest     string
+	info     *pb.FileInfo
+	sum      hash.Hash
+	size     uint64
+	w        io.Writer
+
+	//file is the temporary file of a file download
+	file *os.File
+	//pipe feeds the archive of a directory download to extract, which reports the number of files on extracted
+	pipe      *io.PipeWriter
+	extracted chan extractResult
+}
+
+type extractResult struct {
+	files int
+	err   error
+}
+
+// newDownload starts the download of the requested path from the node. The destination is built from the path that
+// was requested rather than the one the node reports, so a node can't write anywhere but dir/<hostname>.
+func newDownload(dir, hostname, path string, info *pb.FileInfo) (*download, error) {
+	rel := filepath.Join(hostname, filepath.FromSlash(strings.TrimPrefix(path, "/")))
+	if !filepath.IsLocal(hostname) || filepath.Base(hostname) != hostname || !filepath.IsLocal(rel) {
+		return nil, fmt.Errorf("%s from %s is outside of %s", path, hostname, dir)
+	}
+	dl := &download{
+		hostname: hostname,
+		dest:     filepath.Join(dir, rel),
+		info:     info,
+		sum:      sha256.New(),
+	}
+	if info.Directory {
+		if err := os.MkdirAll(dl.dest, 0o755); err != nil {
+			return nil, err
+		}
+		pr, pw := io.Pipe()
+		dl.pipe = pw
+		dl.w = pw
+		dl.extracted = make(chan extractResult, 1)
+		go func() {
+			files, err := extractTar(pr, dl.dest)
+			//unblock the writer when extracting stopped before the end of the archive
+			pr.CloseWithError(err)
+			dl.extracted <- extractResult{files: files, err: err}
+		}()
+		return dl, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(dl.dest), 0o755); err != nil {
+		return nil, err
+	}
+	f, err := os.CreateTemp(filepath.Dir(dl.dest), "."+filepath.Base(dl.dest)+".tailsys-*")
+	if err != nil {
+		return nil, err
+	}
+	dl.file = f
+	dl.w = f
+	return dl, nil
+}
+
+func (dl *download) write(data []byte) error {
+	dl.size += uint64(len(data))
+	dl.sum.Write(data)
+	_, err := dl.w.Write(data)
+	return err
+}
+
+// finish checks the download is what the node sent and puts it in place, it returns the number of files written
+func (dl *download) finish(done *pb.FileDone) (int, error) {
+	if dl.size != done.Size || !bytes.Equal(dl.sum.Sum(nil), done.Sha256) {
+		dl.abort(errors.New("checksum mismatch"))
+		return 0, fmt.Errorf("checksum doesn't match the %d bytes received", dl.size)
+	}
+	if dl.pipe != nil {
+		dl.pipe.Close()
+		res := <-dl.extracted
+		return res.files, res.err
+	}
+	defer os.Remove(dl.file.Name())
+	if err := dl.file.Close(); err != nil {
+		return 0, err
+	}
+	if err := os.Chmod(dl.file.Name(), fs.FileMode(dl.info.Mode).Perm()); err != nil {
+		return 0, err
+	}
+	if err := os.Rename(dl.file.Name(), dl.dest); err != nil {
+		return 0, err
+	}
+	return 1, nil
+}
+
+// abort stops the download and removes what can't be used, files already extracted from an archive are kept
+func (dl *download) abort(err error) {
+	if dl.pipe != nil {
+		dl.pipe.CloseWithError(err)
+		<-dl.extracted
+		return
+	}
+	dl.file.Close()
+	os.Remove(dl.file.Name())
+}
+
+// extractTar writes the regular files and directories of the archive under dest. Entries that would end up outside
+// of dest are rejected, and permissions are kept without the setuid, setgid and sticky bits.
+func extractTar(r io.Reader, dest string) (int, error) {
+	tr := tar.NewReader(r)
+	files := 0
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return files, nil
+		}
+		if err != nil {
+			return files, err
+		}
+		name := filepath.FromSlash(strings.TrimSuffix(hdr.Name, "/"))
+		if !filepath.IsLocal(name) {
+			return files, fmt.Errorf("archive entry %s is outside of the directory", hdr.Name)
+		}
+		path := filepath.Join(dest, name)
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(path, 0o755); err != nil {
+				return files, err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+				return files, err
+			}
+			if err := writeFile(path, tr, fs.FileMode(hdr.Mode).Perm()); err != nil {
+				return files, err
+			}
+			files++
+		}
+	}
+}
+
+func writeFile(path string, r io.Reader, mode fs.FileMode) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,49 @@
+package commander
+
+import (
+	"crypto/sha256"
+	"os"
+	"path/filepath"
+	"testing"
+
+	pb "github.com/charles-d-burton/tailsys/commands"
+)
+
+func TestDownloadDestination(t *testing.T) {
+	dir := t.TempDir()
+	//the node claims a path of its own, the file still goes where it was requested
+	info := &pb.FileInfo{Path: "/../../../tmp/evil", Mode: 0o644}
+	dl, err := newDownload(dir, "web1", "/etc/motd", info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := []byte("welcome\n")
+	if err := dl.write(data); err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256(data)
+	if _, err := dl.finish(&pb.FileDone{Size: uint64(len(data)), Sha256: sum[:]}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(filepath.Join(dir, "web1", "etc", "motd"))
+	if err != nil || string(got) != string(data) {
+		t.Fatalf("expected the file in dir/web1/etc/motd, got %q %v", got, err)
+	}
+}
+
+func TestDownloadOutsideDir(t *testing.T) {
+	tests := []struct {
+		hostname string
+		path     string
+	}{
+		{"web1", "/../../etc/passwd"},
+		{"../web1", "/etc/motd"},
+		{"web1/../..", "/etc/motd"},
+		{"..", "/"},
+	}
+	for _, tt := range tests {
+		if _, err := newDownload(t.TempDir(), tt.hostname, tt.path, &pb.FileInfo{}); err == nil {
+			t.Errorf("expected %s from %s to be rejected", tt.path, tt.hostname)
+		}
+	}
+}
@@ -0,0 +1,282 @@
+package commander
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	pb "github.com/charles-d-burton/tailsys/commands"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/yaml.v3"
+)
+
+// Format is how results are written, table is for people and the others for scripts
+type Format string
+
+const (
+	FormatTable  Format = "table"
+	FormatJSON   Format = "json"
+	FormatYAML   Format = "yaml"
+	FormatNDJSON Format = "ndjson"
+)
+
+// Formats are the formats results can be written in
+var Formats = []Format{FormatTable, FormatJSON, FormatYAML, FormatNDJSON}
+
+// ParseFormat returns the format with the name
+func ParseFormat(name string) (Format, error) {
+	for _, f := range Formats {
+		if string(f) == name {
+			return f, nil
+		}
+	}
+	names := make([]string, len(Formats))
+	for i, f := range Formats {
+		names[i] = string(f)
+	}
+	return "", fmt.Errorf("unknown output format %q, use one of %s", name, strings.Join(names, ", "))
+}
+
+func (cl *Client) WithOutput(format Format) Option {
+	return func(cl *Client) error {
+		if _, err := ParseFormat(string(format)); err != nil {
+			return err
+		}
+		cl.Output = format
+		return nil
+	}
+}
+
+// FailedError is returned when a command, state or file transfer failed on some of the nodes it ran on
+type FailedError struct {
+	Failed int
+	Nodes  int
+	// Stopped is set when the job was canceled at the first failure, Nodes is how many finished before that
+	Stopped bool
+}
+
+func (e *FailedError) Error() string {
+	if e.Stopped {
+		return fmt.Sprintf("failed on %d of %d nodes, stopped at the first failure", e.Failed, e.Nodes)
+	}
+	return fmt.Sprintf("failed on %d of %d nodes", e.Failed, e.Nodes)
+}
+
+// HostResult is how a command finished on a host in the json, yaml and ndjson formats
+type HostResult struct {
+	Host       string `json:"host" yaml:"host"`
+	JobID      string `json:"jobId,omitempty" yaml:"jobId,omitempty"`
+	Status     string `json:"status" yaml:"status"`
+	Successful bool   `json:"successful" yaml:"successful"`
+	ExitCode   int32  `json:"exitCode" yaml:"exitCode"`
+	Signal     string `json:"signal,omitempty" yaml:"signal,omitempty"`
+	Stdout     string `json:"stdout" yaml:"stdout"`
+	Stderr     string `json:"stderr" yaml:"stderr"`
+	Duration   string `json:"duration" yaml:"duration"`
+	Error      string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// TransferResult is how copying a file to a host or fetching one from it went in the json, yaml and ndjson formats
+type TransferResult struct {
+	Host       string `json:"host" yaml:"host"`
+	Path       string `json:"path" yaml:"path"`
+	Successful bool   `json:"successful" yaml:"successful"`
+	Changed    bool   `json:"changed,omitempty" yaml:"changed,omitempty"`
+	Size       uint64 `json:"size" yaml:"size"`
+	Files      int    `json:"files,omitempty" yaml:"files,omitempty"`
+	Dest       string `json:"dest,omitempty" yaml:"dest,omitempty"`
+	Duration   string `json:"duration,omitempty" yaml:"duration,omitempty"`
+	Error      string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// uploadResult is how a node wrote a copied file
+func uploadResult(res *pb.FileUploadResponse) *TransferResult {
+	r := &TransferResult{
+		Host:       res.Hostname,
+		Path:       res.Path,
+		Successful: res.Successful,
+		Changed:    res.Changed,
+		Size:       res.Size,
+		Error:      res.Error,
+	}
+	if res.Duration != nil {
+		r.Duration = res.Duration.AsDuration().String()
+	}
+	return r
+}
+
+// fetchResult is a finished download, files is the number of files a directory held
+func fetchResult(dl *download, files int) *TransferResult {
+	return &TransferResult{
+		Host:       dl.hostname,
+		Path:       dl.info.Path,
+		Successful: true,
+		Size:       dl.size,
+		Files:      files,
+		Dest:       dl.dest,
+	}
+}
+
+// hostResults gathers the output of each host until its command exits
+type hostResults struct {
+	stdout map[string]*bytes.Buffer
+	stderr map[string]*bytes.Buffer
+}
+
+func newHostResults() *hostResults {
+	return &hostResults{stdout: make(map[string]*bytes.Buffer), stderr: make(map[string]*bytes.Buffer)}
+}
+
+func (hr *hostResults) write(host string, out *pb.CommandOutput) {
+	bufs := hr.stdout
+	if out.Stream == pb.OutputStream_STDERR {
+		bufs = hr.stderr
+	}
+	if bufs[host] == nil {
+		bufs[host] = &bytes.Buffer{}
+	}
+	bufs[host].Write(out.Data)
+}
+
+// exit returns the result of the host along with everything it wrote
+func (hr *hostResults) exit(host, jobID string, res *pb.CommandResponse) *HostResult {
+	r := &HostResult{
+		Host:       host,
+		JobID:      jobID,
+		Status:     strings.ReplaceAll(statusName(res.Reason.String(), ""), "_", " "),
+		Successful: res.Successful,
+		ExitCode:   res.ExitCode,
+		Signal:     res.Signal,
+		Stdout:     bufString(hr.stdout[host]),
+		Stderr:     bufString(hr.stderr[host]),
+		Duration:   res.GetDuration().AsDuration().String(),
+		Error:      res.Error,
+	}
+	if res.Reason == pb.TerminationReason_TERMINATION_REASON_UNSPECIFIED {
+		r.Status = "did not run"
+	}
+	delete(hr.stdout, host)
+	delete(hr.stderr, host)
+	return r
+}
+
+// bufString is what was written to the buffer, hosts that wrote nothing have none
+func bufString(b *bytes.Buffer) string {
+	if b == nil {
+		return ""
+	}
+	return b.String()
+}
+
+// recordResult is the result of a host as the job history recorded it
+func recordResult(jobID string, rec *pb.CommandRecord) *HostResult {
+	r := &HostResult{
+		Host:       rec.Hostname,
+		JobID:      jobID,
+		Status:     statusName(rec.Status.String(), "HOST_"),
+		Successful: rec.Successful,
+		ExitCode:   rec.ExitCode,
+		Stdout:     string(rec.Stdout),
+		Stderr:     string(rec.Stderr),
+		Error:      rec.Error,
+	}
+	if rec.Started != nil && rec.Finished != nil {
+		r.Duration = rec.Finished.AsTime().Sub(rec.Started.AsTime()).String()
+	}
+	return r
+}
+
+// encoder writes records in one of the structured formats. ndjson writes each record as it is added, json and yaml
+// write them as a list on close.
+type encoder struct {
+	w       io.Writer
+	format  Format
+	records []any
+}
+
+// encoder returns nil for the table format, results are printed for people then
+func (cl *Client) encoder(w io.Writer) *encoder {
+	if cl.Output == "" || cl.Output == FormatTable {
+		return nil
+	}
+	return &encoder{w: w, format: cl.Output, records: make([]any, 0)}
+}
+
+func (e *encoder) add(record any) error {
+	if e.format == FormatNDJSON {
+		return json.NewEncoder(e.w).Encode(record)
+	}
+	e.records = append(e.records, record)
+	return nil
+}
+
+func (e *encoder) close() error {
+	switch e.format {
+	case FormatJSON:
+		enc := json.NewEncoder(e.w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(e.records)
+	case FormatYAML:
+		return yaml.NewEncoder(e.w).Encode(e.records)
+	}
+	return nil
+}
+
+// one writes a single record instead of a list
+func (e *encoder) one(record any) error {
+	switch e.format {
+	case FormatJSON:
+		enc := json.NewEncoder(e.w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(record)
+	case FormatYAML:
+		return yaml.NewEncoder(e.w).Encode(record)
+	}
+	return json.NewEncoder(e.w).Encode(record)
+}
+
+// protoRecord turns the message into a record with the field names of its json mapping, so it can be written as
+// both json and yaml
+func protoRecord(m proto.Message) (map[string]any, error) {
+	data, err := protojson.MarshalOptions{EmitUnpopulated: true}.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	//json is yaml, decoding it as yaml keeps integers as integers
+	record := make(map[string]any)
+	if err := yaml.Unmarshal(data, &record); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// addProtos adds each message as a record
+func addProtos[M proto.Message](e *encoder, msgs []M) error {
+	for _, m := range msgs {
+		record, err := protoRecord(m)
+		if err != nil {
+			return err
+		}
+		if err := e.add(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// jobRecord is the job summary with the result of each host added under hosts
+func jobRecord(job *pb.Job) (map[string]any, error) {
+	record, err := protoRecord(job.Summary)
+	if err != nil {
+		return nil, err
+	}
+	hosts := make([]*HostResult, 0, len(job.Records))
+	for _, rec := range job.Records {
+		hosts = append(hosts, recordResult(job.Summary.JobId, rec))
+	}
+	record["hosts"] = hosts
+	return record, nil
+}
@@ -0,0 +1,450 @@
+package commander
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	pb "github.com/charles-d-burton/tailsys/commands"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// hostPrinter prints streamed output one line at a time with the host it came from as a prefix.
+// Chunks can end mid-line so the remainder is held until the rest of the line arrives.
+type hostPrinter struct {
+	stdout  io.Writer
+	stderr  io.Writer
+	partial map[string]map[pb.OutputStream][]byte
+}
+
+func newHostPrinter(stdout, stderr io.Writer) *hostPrinter {
+	return &hostPrinter{
+		stdout:  stdout,
+		stderr:  stderr,
+		partial: make(map[string]map[pb.OutputStream][]byte),
+	}
+}
+
+func (hp *hostPrinter) write(host string, stream pb.OutputStream, data []byte) {
+	if hp.partial[host] == nil {
+		hp.partial[host] = make(map[pb.OutputStream][]byte)
+	}
+	buf := append(hp.partial[host][stream], data...)
+	for {
+		idx := bytes.IndexByte(buf, '\n')
+		if idx < 0 {
+			break
+		}
+		hp.printLine(host, stream, buf[:idx])
+		buf = buf[idx+1:]
+	}
+	hp.partial[host][stream] = append([]byte(nil), buf...)
+}
+
+// flush prints anything left over for the host that was not terminated with a newline
+func (hp *hostPrinter) flush(host string) {
+	for stream, buf := range hp.partial[host] {
+		if len(buf) > 0 {
+			hp.printLine(host, stream, buf)
+		}
+	}
+	delete(hp.partial, host)
+}
+
+func (hp *hostPrinter) printLine(host string, stream pb.OutputStream, line []byte) {
+	w := hp.stdout
+	if stream == pb.OutputStream_STDERR {
+		w = hp.stderr
+	}
+	fmt.Fprintf(w, "[%s] %s\n", host, line)
+}
+
+// exit prints how the command on the host finished
+func (hp *hostPrinter) exit(host string, res *pb.CommandResponse) {
+	if res.GetError() != "" {
+		fmt.Fprintf(hp.stderr, "[%s] error: %s\n", host, res.GetError())
+	}
+	switch res.GetReason() {
+	case pb.TerminationReason_SIGNALED:
+		fmt.Fprintf(hp.stdout, "[%s] terminated by signal %s after %s\n", host, res.GetSignal(), res.GetDuration().AsDuration())
+	case pb.TerminationReason_EXITED, pb.TerminationReason_TIMED_OUT, pb.TerminationReason_CANCELED:
+		fmt.Fprintf(hp.stdout, "[%s] %s with code %d after %s\n", host, strings.ToLower(strings.ReplaceAll(res.GetReason().String(), "_", " ")), res.GetExitCode(), res.GetDuration().AsDuration())
+	default:
+		fmt.Fprintf(hp.stdout, "[%s] command did not run\n", host)
+	}
+}
+
+// printNodes prints the hostname of each node
+func printNodes(w io.Writer, nodes []string) {
+	fmt.Fprintln(w, "HOSTNAME")
+	for _, node := range nodes {
+		fmt.Fprintln(w, node)
+	}
+}
+
+// printJobs prints a one line summary of each job
+func printJobs(w io.Writer, jobs []*pb.JobSummary) {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "JOB ID\tCREATED\tSTATUS\tREQUESTER\tPATTERN\tHOSTS\tFAILED\tCOMMAND")
+	for _, job := range jobs {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%d\t%d\t%s\n",
+			job.JobId,
+			job.Created.AsTime().Local().Format(time.DateTime),
+			statusName(job.Status.String(), "JOB_"),
+			job.Requester,
+			job.Pattern,
+			job.Hosts,
+			job.Failed,
+			job.Command,
+		)
+	}
+	tw.Flush()
+}
+
+// printJob prints the job followed by the progress or result and output from each host
+func printJob(w io.Writer, job *pb.Job) {
+	s := job.Summary
+	fmt.Fprintf(w, "job:       %s\n", s.JobId)
+	fmt.Fprintf(w, "status:    %s\n", statusName(s.Status.String(), "JOB_"))
+	fmt.Fprintf(w, "command:   %s\n", s.Command)
+	fmt.Fprintf(w, "pattern:   %s\n", s.Pattern)
+	fmt.Fprintf(w, "requester: %s\n", s.Requester)
+	fmt.Fprintf(w, "created:   %s\n", s.Created.AsTime().Local().Format(time.DateTime))
+	if s.Finished != nil {
+		fmt.Fprintf(w, "finished:  %s\n", s.Finished.AsTime().Local().Format(time.DateTime))
+	}
+	fmt.Fprintf(w, "hosts:     %d (%d failed)\n", s.Hosts, s.Failed)
+
+	hp := newHostPrinter(w, w)
+	for _, rec := range job.Records {
+		fmt.Fprintln(w)
+		status := statusName(rec.Status.String(), "HOST_")
+		switch {
+		case rec.Started == nil && rec.Finished == nil:
+			fmt.Fprintf(w, "[%s] %s\n", rec.Hostname, status)
+			continue
+		case rec.Finished == nil:
+			fmt.Fprintf(w, "[%s] %s since %s\n", rec.Hostname, status, rec.Started.AsTime().Local().Format(time.DateTime))
+			continue
+		}
+		hp.write(rec.Hostname, pb.OutputStream_STDOUT, rec.Stdout)
+		hp.write(rec.Hostname, pb.OutputStream_STDERR, rec.Stderr)
+		hp.flush(rec.Hostname)
+		if rec.Error != "" {
+			fmt.Fprintf(w, "[%s] error: %s\n", rec.Hostname, rec.Error)
+		}
+		if rec.Started == nil {
+			fmt.Fprintf(w, "[%s] %s at %s\n", rec.Hostname, status, rec.Finished.AsTime().Local().Format(time.DateTime))
+			continue
+		}
+		fmt.Fprintf(w, "[%s] %s with exit code %d, ran %s to %s\n",
+			rec.Hostname,
+			status,
+			rec.ExitCode,
+			rec.Started.AsTime().Local().Format(time.DateTime),
+			rec.Finished.AsTime().Local().Format(time.DateTime),
+		)
+	}
+}
+
+// event prints a single event from a job being watched
+func (hp *hostPrinter) event(ev *pb.JobEvent) {
+	switch e := ev.Event.(type) {
+	case *pb.JobEvent_HostStatus:
+		fmt.Fprintf(hp.stdout, "[%s] %s\n", ev.Hostname, statusName(e.HostStatus.String(), "HOST_"))
+	case *pb.JobEvent_Output:
+		hp.write(ev.Hostname, e.Output.Stream, e.Output.Data)
+	case *pb.JobEvent_Exit:
+		hp.flush(ev.Hostname)
+		hp.exit(ev.Hostname, e.Exit)
+	case *pb.JobEvent_JobStatus:
+		fmt.Fprintf(hp.stdout, "job %s %s\n", ev.JobId, statusName(e.JobStatus.String(), "JOB_"))
+	}
+}
+
+// printKeys prints a line for each node key
+func printKeys(w io.Writer, keys []*pb.NodeKey) {
+	if len(keys) == 0 {
+		return
+	}
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "HOSTNAME\tSTATUS\tFINGERPRINT")
+	for _, key := range keys {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", key.Hostname, statusName(key.Status.String(), "KEY_"), key.Fingerprint)
+	}
+	tw.Flush()
+}
+
+// statusName turns a status enum into the word shown to the user, e.g. HOST_SUCCEEDED becomes succeeded
+func statusName(name, prefix string) string {
+	return strings.ToLower(strings.TrimPrefix(name, prefix))
+}
+
+// printInventory prints a line for each node, or everything that is known about each node when long is set
+func printInventory(w io.Writer, nodes []*pb.SysInfo, long bool) {
+	if long {
+		for i, node := range nodes {
+			if i > 0 {
+				fmt.Fprintln(w)
+			}
+			printNodeInventory(w, node)
+		}
+		return
+	}
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "HOSTNAME\tOS\tKERNEL\tARCH\tCPUS\tMEMORY\tUPTIME\tVERSION\tCOLLECTED")
+	for _, node := range nodes {
+		inv := node.Inventory
+		if inv == nil {
+			fmt.Fprintf(tw, "%s\t-\t-\t-\t-\t-\t-\t-\tnever\n", node.Hostname)
+			continue
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%d\t%s\t%s\t%s\t%s\n",
+			node.Hostname,
+			osName(inv),
+			inv.Kernel,
+			inv.Arch,
+			inv.CpuCount,
+			byteSize(inv.MemoryTotalBytes),
+			time.Duration(inv.UptimeSeconds)*time.Second,
+			inv.TailsysVersion,
+			inv.Collected.AsTime().Local().Format(time.DateTime),
+		)
+	}
+	tw.Flush()
+}
+
+func printNodeInventory(w io.Writer, node *pb.SysInfo) {
+	fmt.Fprintf(w, "hostname:       %s\n", node.Hostname)
+	inv := node.Inventory
+	if inv == nil {
+		fmt.Fprintln(w, "no inventory collected")
+		return
+	}
+	fmt.Fprintf(w, "ip:             %s\n", node.Ip)
+	fmt.Fprintf(w, "os:             %s\n", osName(inv))
+	fmt.Fprintf(w, "kernel:         %s\n", inv.Kernel)
+	fmt.Fprintf(w, "arch:           %s\n", inv.Arch)
+	fmt.Fprintf(w, "cpu:            %d x %s\n", inv.CpuCount, inv.CpuModel)
+	fmt.Fprintf(w, "memory:         %s available of %s\n", byteSize(inv.MemoryAvailableBytes), byteSize(inv.MemoryTotalBytes))
+	fmt.Fprintf(w, "uptime:         %s\n", time.Duration(inv.UptimeSeconds)*time.Second)
+	fmt.Fprintf(w, "tailscale ips:  %s\n", strings.Join(inv.TailscaleIps, ", "))
+	fmt.Fprintf(w, "tailscale tags: %s\n", strings.Join(inv.TailscaleTags, ", "))
+	fmt.Fprintf(w, "version:        %s\n", inv.TailsysVersion)
+	fmt.Fprintf(w, "collected:      %s\n", inv.Collected.AsTime().Local().Format(time.DateTime))
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "\nMOUNTPOINT\tDEVICE\tFSTYPE\tSIZE\tFREE")
+	for _, d := range inv.Disks {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", d.Mountpoint, d.Device, d.Fstype, byteSize(d.TotalBytes), byteSize(d.FreeBytes))
+	}
+	fmt.Fprintln(tw, "\nINTERFACE\tMAC\tMTU\tUP\tADDRESSES")
+	for _, i := range inv.Interfaces {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%t\t%s\n", i.Name, i.Mac, i.Mtu, i.Up, strings.Join(i.Addresses, ", "))
+	}
+	tw.Flush()
+}
+
+// osName is the distro when it is known, otherwise the os
+func osName(inv *pb.Inventory) string {
+	if inv.DistroName != "" {
+		return inv.DistroName
+	}
+	if inv.Distro != "" {
+		return strings.TrimSpace(inv.Distro + " " + inv.DistroVersion)
+	}
+	return inv.Os
+}
+
+// byteSize formats a number of bytes with a binary unit, e.g. 1536 becomes 1.5 KiB
+func byteSize(b uint64) string {
+	const unit = 1024
+	if b < unit {
+		return fmt.Sprintf("%d B", b)
+	}
+	div, exp := uint64(unit), 0
+	for n := b / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(b)/float64(div), "KMGTPE"[exp])
+}
+
+// printDiscovery prints a line for each discovered node, the registered ones only when all is set
+func printDiscovery(w io.Writer, list *pb.DiscoveryList, all bool) {
+	if list.Checked == nil {
+		fmt.Fprintln(w, "the tailnet hasn't been listed yet")
+		return
+	}
+	fmt.Fprintf(w, "tailnet listed at %s\n", list.Checked.AsTime().Local().Format(time.DateTime))
+	nodes := make([]*pb.DiscoveredNode, 0, len(list.Nodes))
+	for _, node := range list.Nodes {
+		if all || node.State != pb.DiscoveryState_DISCOVERY_REGISTERED {
+			nodes = append(nodes, node)
+		}
+	}
+	if len(nodes) == 0 {
+		fmt.Fprintln(w, "every tagged device is registered and every accepted node is on the tailnet")
+		return
+	}
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "HOSTNAME\tSTATE\tSINCE\tADDRESSES\tTAGS\tOS\tLAST SEEN")
+	for _, node := range nodes {
+		lastSeen := "-"
+		if node.LastSeen != nil {
+			lastSeen = node.LastSeen.AsTime().Local().Format(time.DateTime)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			node.Hostname,
+			statusName(node.State.String(), "DISCOVERY_"),
+			node.FirstSeen.AsTime().Local().Format(time.DateTime),
+			orDash(strings.Join(node.Addresses, ",")),
+			orDash(strings.Join(node.Tags, ",")),
+			orDash(node.Os),
+			lastSeen,
+		)
+	}
+	tw.Flush()
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// printNodeStatus prints a line for each node followed by its latest pings when they were asked for
+func printNodeStatus(w io.Writer, nodes []*pb.NodeStatus) {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "HOSTNAME\tSTATUS\tSINCE\tLATENCY\tLAST SEEN\tFAILURES\tNEXT PING\tERROR")
+	for _, node := range nodes {
+		if node.LastChecked == nil {
+			fmt.Fprintf(tw, "%s\t%s\t-\t-\t-\t-\t-\t-\n", node.Hostname, statusName(node.Health.String(), "NODE_"))
+			continue
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%d\t%s\t%s\n",
+			node.Hostname,
+			statusName(node.Health.String(), "NODE_"),
+			localTime(node.Since),
+			latency(node.LatencyMs),
+			localTime(node.LastSeen),
+			node.Failures,
+			localTime(node.NextProbe),
+			orDash(node.Error),
+		)
+	}
+	tw.Flush()
+
+	for _, node := range nodes {
+		if len(node.History) == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "\n%s\n", node.Hostname)
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		for _, p := range node.History {
+			if p.Successful {
+				fmt.Fprintf(tw, "  %s\t%s\n", localTime(p.Pinged), latency(p.LatencyMs))
+				continue
+			}
+			fmt.Fprintf(tw, "  %s\tfailed\t%s\n", localTime(p.Pinged), p.Error)
+		}
+		tw.Flush()
+	}
+}
+
+func localTime(t *timestamppb.Timestamp) string {
+	if t == nil {
+		return "-"
+	}
+	return t.AsTime().Local().Format(time.DateTime)
+}
+
+func latency(ms float64) string {
+	return fmt.Sprintf("%.1fms", ms)
+}
+
+// printState prints the result of every resource applied on a node followed by a summary
+func printState(w io.Writer, res *pb.StateResponse) {
+	mode := ""
+	if res.Test {
+		mode = " (test)"
+	}
+	if res.Error != "" {
+		fmt.Fprintf(w, "%s: error%s: %s\n", res.Hostname, mode, res.Error)
+		return
+	}
+	counts := make(map[pb.ResourceStatus]int)
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "%s%s\n", res.Hostname, mode)
+	for _, r := range res.Resources {
+		counts[r.Status]++
+		detail := strings.Join(r.Changes, ", ")
+		if r.Error != "" && detail != "" {
+			detail += ", "
+		}
+		detail += r.Error
+		fmt.Fprintf(tw, "  %s\t%s\t%s\n", statusName(r.Status.String(), "RESOURCE_"), r.Id, detail)
+	}
+	tw.Flush()
+	fmt.Fprintf(w, "%s: %d changed, %d unchanged, %d failed in %s\n",
+		res.Hostname,
+		counts[pb.ResourceStatus_RESOURCE_CHANGED],
+		counts[pb.ResourceStatus_RESOURCE_UNCHANGED],
+		counts[pb.ResourceStatus_RESOURCE_FAILED],
+		res.GetDuration().AsDuration().Round(time.Millisecond),
+	)
+}
+
+func printTransfer(w io.Writer, res *pb.FileUploadResponse) {
+	if !res.Successful {
+		fmt.Fprintf(w, "%s: error: %s\n", res.Hostname, res.Error)
+		return
+	}
+	result := "unchanged"
+	if res.Changed {
+		result = "written"
+	}
+	fmt.Fprintf(w, "%s: %s %s (%s) in %s\n",
+		res.Hostname,
+		result,
+		res.Path,
+		byteSize(res.Size),
+		res.GetDuration().AsDuration().Round(time.Millisecond),
+	)
+}
+
+func printFetch(w io.Writer, dl *download, files int) {
+	if dl.info.Directory {
+		fmt.Fprintf(w, "%s: fetched %s (%d files, %s) into %s\n", dl.hostname, dl.info.Path, files, byteSize(dl.size), dl.dest)
+		return
+	}
+	fmt.Fprintf(w, "%s: fetched %s (%s) into %s\n", dl.hostname, dl.info.Path, byteSize(dl.size), dl.dest)
+}
+
+func printShellSessions(w io.Writer, sessions []*pb.ShellSession) {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "SESSION ID\tSTARTED\tHOST\tREQUESTER\tUSER\tCOMMAND\tEXIT")
+	for _, s := range sessions {
+		exit := "running"
+		switch {
+		case s.Error != "":
+			exit = s.Error
+		case s.Ended != nil:
+			exit = fmt.Sprint(s.ExitCode)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			s.SessionId,
+			localTime(s.Started),
+			s.Hostname,
+			s.Requester,
+			orDash(s.User),
+			s.Command,
+			exit,
+		)
+	}
+	tw.Flush()
+}
@@ -0,0 +1,188 @@
+package commander
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+
+	pb "github.com/charles-d-burton/tailsys/commands"
+	"golang.org/x/term"
+)
+
+// eot is what a terminal sends for ^D, it ends the input of the shell when stdin isn't a terminal
+const eot = "\x04"
+
+// Shell opens an interactive shell on the host and connects it to the terminal until the shell exits. The terminal
+// is put in raw mode so keys like ^C go to the remote shell, and size changes follow the local window.
+func (cl *Client) Shell(ctx context.Context, open *pb.ShellOpen) (*pb.ShellExit, error) {
+	var exit *pb.ShellExit
+	err := cl.withManager(ctx, func(cc pb.CommandManagerClient) error {
+		stdin := int(os.Stdin.Fd())
+		interactive := term.IsTerminal(stdin)
+		if interactive {
+			open.Term = os.Getenv("TERM")
+			if cols, rows, err := term.GetSize(stdin); err == nil {
+				open.Size = &pb.WindowSize{Rows: uint32(rows), Cols: uint32(cols)}
+			}
+		}
+
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		stream, err := cc.Shell(ctx)
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(&pb.ShellInput{Payload: &pb.ShellInput_Open{Open: open}}); err != nil {
+			return err
+		}
+		first, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+		cl.Logger().Debug("shell session started", "session", first.GetSessionId())
+
+		if interactive {
+			state, err := term.MakeRaw(stdin)
+			if err != nil {
+				return err
+			}
+			defer term.Restore(stdin, state)
+		}
+		//only the input goroutine sends once the session is open
+		input := make(chan *pb.ShellInput)
+		go readInput(ctx, input, interactive)
+		go func() {
+			for in := range input {
+				if err := stream.Send(in); err != nil {
+					return
+				}
+			}
+		}()
+
+		for {
+			out, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				return errors.New("shell ended without an exit status")
+			}
+			if err != nil {
+				//input already reached the shell, don't retry and open a second one
+				return fmt.Errorf("lost connection to coordination server during shell session: %v", err)
+			}
+			if data := out.GetData(); data != nil {
+				os.Stdout.Write(data)
+			}
+			if out.GetExit() != nil {
+				exit = out.GetExit()
+				return nil
+			}
+		}
+	})
+	return exit, err
+}
+
+// readInput sends what is typed, window size changes and signals to input until ctx is done
+func readInput(ctx context.Context, input chan<- *pb.ShellInput, interactive bool) {
+	defer close(input)
+	send := func(in *pb.ShellInput) bool {
+		select {
+		case input <- in:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	data := make(chan []byte)
+	go func() {
+		buf := make([]byte, 32*1024)
+		for {
+			n, err := os.Stdin.Read(buf)
+			chunk := append([]byte(nil), buf[:n]...)
+			if err != nil && !interactive {
+				chunk = append(chunk, eot...)
+			}
+			if len(chunk) > 0 {
+				select {
+				case data <- chunk:
+				case <-ctx.Done():
+					return
+				}
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	resize := make(chan os.Signal, 1)
+	notifyResize(resize)
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, relayedSignals...)
+	defer signal.Stop(resize)
+	defer signal.Stop(signals)
+
+	for {
+		var in *pb.ShellInput
+		select {
+		case <-ctx.Done():
+			return
+		case b := <-data:
+			in = &pb.ShellInput{Payload: &pb.ShellInput_Data{Data: b}}
+		case <-resize:
+			cols, rows, err := term.GetSize(int(os.Stdin.Fd()))
+			if err != nil {
+				continue
+			}
+			in = &pb.ShellInput{Payload: &pb.ShellInput_Resize{Resize: &pb.WindowSize{Rows: uint32(rows), Cols: uint32(cols)}}}
+		case sig := <-signals:
+			name := signalName(sig)
+			if name == "" {
+				continue
+			}
+			in = &pb.ShellInput{Payload: &pb.ShellInput_Signal{Signal: name}}
+		}
+		if !send(in) {
+			return
+		}
+	}
+}
+
+// ShellSessions prints the most recent shell sessions
+func (cl *Client) ShellSessions(ctx context.Context, limit int) error {
+	return cl.withManager(ctx, func(cc pb.CommandManagerClient) error {
+		res, err := cc.ListShellSessions(ctx, &pb.ShellSessionQuery{Limit: int32(limit)})
+		if err != nil {
+			return err
+		}
+		enc := cl.encoder(os.Stdout)
+		if enc == nil {
+			printShellSessions(os.Stdout, res.Sessions)
+			return nil
+		}
+		for _, session := range res.Sessions {
+			record, err := protoRecord(session)
+			if err != nil {
+				return err
+			}
+			if err := enc.add(record); err != nil {
+				return err
+			}
+		}
+		return enc.close()
+	})
+}
+
+// ShellTranscript writes the asciicast recording of the session to w
+func (cl *Client) ShellTranscript(ctx context.Context, sessionID string, w io.Writer) error {
+	return cl.withManager(ctx, func(cc pb.CommandManagerClient) error {
+		res, err := cc.GetShellSession(ctx, &pb.ShellSessionID{SessionId: sessionID})
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(res.Transcript)
+		return err
+	})
+}
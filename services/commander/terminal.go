@@ -0,0 +1,29 @@
+//go:build !windows
+// +build !windows
+
+package commander
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// relayedSignals are sent on to the shell when the cli receives them, SIGTERM and SIGHUP end the session instead
+var relayedSignals = []os.Signal{syscall.SIGINT, syscall.SIGQUIT}
+
+// notifyResize sends on ch whenever the terminal changes size
+func notifyResize(ch chan<- os.Signal) {
+	signal.Notify(ch, syscall.SIGWINCH)
+}
+
+// signalName is the name of the signal the node understands, e.g. SIGINT
+func signalName(sig os.Signal) string {
+	switch sig {
+	case syscall.SIGINT:
+		return "SIGINT"
+	case syscall.SIGQUIT:
+		return "SIGQUIT"
+	}
+	return ""
+}
@@ -0,0 +1,17 @@
+package commander
+
+import (
+	"os"
+)
+
+var relayedSignals = []os.Signal{os.Interrupt}
+
+// notifyResize is a no-op on windows, consoles don't signal size changes
+func notifyResize(ch chan<- os.Signal) {}
+
+func signalName(sig os.Signal) string {
+	if sig == os.Interrupt {
+		return "SIGINT"
+	}
+	return ""
+}
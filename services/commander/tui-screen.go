@@ -0,0 +1,292 @@
+package commander
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ANSI sequences used to draw the interactive ui
+const (
+	altScreenOn  = "\x1b[?1049h"
+	altScreenOff = "\x1b[?1049l"
+	cursorHide   = "\x1b[?25l"
+	cursorShow   = "\x1b[?25h"
+	cursorHome   = "\x1b[H"
+	clearLine    = "\x1b[K"
+	clearBelow   = "\x1b[J"
+)
+
+func bold(s string) string    { return "\x1b[1m" + s + "\x1b[0m" }
+func reverse(s string) string { return "\x1b[7m" + s + "\x1b[0m" }
+func dim(s string) string     { return "\x1b[2m" + s + "\x1b[0m" }
+func red(s string) string     { return "\x1b[31m" + s + "\x1b[0m" }
+func green(s string) string   { return "\x1b[32m" + s + "\x1b[0m" }
+func yellow(s string) string  { return "\x1b[33m" + s + "\x1b[0m" }
+
+// screen is a frame of the ui, it is built line by line and written in one go so the terminal doesn't flicker
+type screen struct {
+	width, height int
+	lines         []string
+}
+
+func newScreen(width, height int) *screen {
+	return &screen{width: width, height: height, lines: make([]string, height)}
+}
+
+// set puts an already fitted and styled line on row y, rows outside the screen are ignored
+func (s *screen) set(y int, line string) {
+	if y >= 0 && y < s.height {
+		s.lines[y] = line
+	}
+}
+
+func (s *screen) draw(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString(cursorHome)
+	for i, line := range s.lines {
+		bw.WriteString(line)
+		bw.WriteString(clearLine)
+		if i < len(s.lines)-1 {
+			bw.WriteString("\r\n")
+		}
+	}
+	bw.WriteString(clearBelow)
+	return bw.Flush()
+}
+
+// fit cuts or pads the text to exactly width columns. Control characters and escape sequences are removed first so
+// output from nodes can't move the cursor or change colors.
+func fit(text string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	text = sanitize(text)
+	n := utf8.RuneCountInString(text)
+	if n > width {
+		runes := []rune(text)
+		if width == 1 {
+			return "…"
+		}
+		return string(runes[:width-1]) + "…"
+	}
+	return text + strings.Repeat(" ", width-n)
+}
+
+// sanitize removes escape sequences and control characters and expands tabs
+func sanitize(text string) string {
+	if !strings.ContainsFunc(text, unicode.IsControl) {
+		return text
+	}
+	var b strings.Builder
+	inEscape := false
+	for _, r := range text {
+		switch {
+		case inEscape:
+			//CSI sequences end with a letter, anything else ends a two character sequence
+			if r != '[' && r != '?' && r != ';' && !unicode.IsDigit(r) {
+				inEscape = false
+			}
+		case r == '\x1b':
+			inEscape = true
+		case r == '\t':
+			b.WriteString("    ")
+		case !unicode.IsControl(r):
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// outputPane is the scrollback of one host shown in a split pane
+type outputPane struct {
+	title   string
+	status  string
+	lines   []string
+	partial string
+	//offset is how many lines the pane is scrolled back from the end
+	offset int
+}
+
+// maxPaneLines is how much output each pane keeps
+const maxPaneLines = 2000
+
+// write adds output to the pane, a line is only complete once its newline arrives
+func (p *outputPane) write(data []byte) {
+	text := p.partial + strings.ReplaceAll(string(data), "\r\n", "\n")
+	parts := strings.Split(text, "\n")
+	p.partial = parts[len(parts)-1]
+	p.lines = append(p.lines, parts[:len(parts)-1]...)
+	if over := len(p.lines) - maxPaneLines; over > 0 {
+		p.lines = p.lines[over:]
+	}
+}
+
+// tail returns the n lines of the pane that end offset lines before the last, including an unterminated one
+func (p *outputPane) tail(n int) []string {
+	lines := p.lines
+	if p.partial != "" {
+		lines = append(lines[:len(lines):len(lines)], p.partial)
+	}
+	p.offset = max(0, min(p.offset, len(lines)-n))
+	lines = lines[:len(lines)-p.offset]
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return lines
+}
+
+// renderPanes lays the panes out in a grid of width by height, the focused pane has a highlighted title
+func renderPanes(panes []*outputPane, width, height, focus int) []string {
+	out := make([]string, height)
+	if len(panes) == 0 || height <= 0 {
+		for i := range out {
+			out[i] = fit("", width)
+		}
+		return out
+	}
+	cols := 1
+	for cols*cols < len(panes) {
+		cols++
+	}
+	//wide terminals fit more panes side by side than tall ones
+	for cols > 1 && width/cols < 30 {
+		cols--
+	}
+	rows := (len(panes) + cols - 1) / cols
+	paneHeight := height / rows
+	if paneHeight < 2 {
+		paneHeight = 2
+		rows = height / paneHeight
+	}
+	paneWidth := (width - (cols - 1)) / cols
+
+	y := 0
+	for r := 0; r < rows && y < height; r++ {
+		h := paneHeight
+		if r == rows-1 {
+			h = height - y
+		}
+		cells := make([][]string, cols)
+		for c := 0; c < cols; c++ {
+			i := r*cols + c
+			if i >= len(panes) {
+				cells[c] = blankCell(paneWidth, h)
+				continue
+			}
+			cells[c] = paneCell(panes[i], paneWidth, h, i == focus)
+		}
+		for line := 0; line < h && y < height; line++ {
+			parts := make([]string, cols)
+			for c := range cells {
+				parts[c] = cells[c][line]
+			}
+			out[y] = strings.Join(parts, dim("│"))
+			y++
+		}
+	}
+	for ; y < height; y++ {
+		out[y] = fit("", width)
+	}
+	return out
+}
+
+func paneCell(p *outputPane, width, height int, focused bool) []string {
+	cell := make([]string, 0, height)
+	title := fit(" "+p.title+"  "+p.status, width)
+	if focused {
+		title = reverse(title)
+	} else {
+		title = bold(title)
+	}
+	cell = append(cell, title)
+	for _, line := range p.tail(height - 1) {
+		cell = append(cell, fit(line, width))
+	}
+	for len(cell) < height {
+		cell = append(cell, fit("", width))
+	}
+	return cell
+}
+
+func blankCell(width, height int) []string {
+	cell := make([]string, height)
+	for i := range cell {
+		cell[i] = fit("", width)
+	}
+	return cell
+}
+
+// readKeys turns raw terminal input into key names like up, enter or esc, and printable characters as themselves
+func readKeys(r io.Reader, keys chan<- string) {
+	buf := make([]byte, 256)
+	for {
+		n, err := r.Read(buf)
+		if err != nil {
+			close(keys)
+			return
+		}
+		for _, k := range parseKeys(buf[:n]) {
+			keys <- k
+		}
+	}
+}
+
+var escapeKeys = map[string]string{
+	"\x1b[A": "up", "\x1b[B": "down", "\x1b[C": "right", "\x1b[D": "left",
+	"\x1bOA": "up", "\x1bOB": "down", "\x1bOC": "right", "\x1bOD": "left",
+	"\x1b[5~": "pgup", "\x1b[6~": "pgdown", "\x1b[H": "home", "\x1b[F": "end",
+}
+
+func parseKeys(b []byte) []string {
+	keys := make([]string, 0, len(b))
+	for len(b) > 0 {
+		if b[0] == 0x1b {
+			if len(b) == 1 {
+				return append(keys, "esc")
+			}
+			matched := false
+			for seq, name := range escapeKeys {
+				if strings.HasPrefix(string(b), seq) {
+					keys = append(keys, name)
+					b = b[len(seq):]
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				//an escape sequence that isn't handled, skip it whole
+				end := 1
+				for end < len(b) && b[end] != 0x1b && !(b[end] >= '@' && b[end] <= '~' && end > 1) {
+					end++
+				}
+				if end < len(b) && b[end] != 0x1b {
+					end++
+				}
+				b = b[end:]
+			}
+			continue
+		}
+		switch b[0] {
+		case '\r', '\n':
+			keys = append(keys, "enter")
+		case '\t':
+			keys = append(keys, "tab")
+		case 0x7f, 0x08:
+			keys = append(keys, "backspace")
+		case 0x03:
+			keys = append(keys, "ctrl+c")
+		default:
+			r, size := utf8.DecodeRune(b)
+			if unicode.IsPrint(r) {
+				keys = append(keys, string(r))
+			}
+			b = b[size:]
+			continue
+		}
+		b = b[1:]
+	}
+	return keys
+}
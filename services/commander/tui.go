@@ -0,0 +1,751 @@
+package commander
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	pb "github.com/charles-d-burton/tailsys/commands"
+	"golang.org/x/term"
+)
+
+// nodeRefreshInterval is how often the node list of the interactive ui is loaded again
+const nodeRefreshInterval = 10 * time.Second
+
+type view int
+
+const (
+	viewNodes view = iota
+	viewRun
+	viewJobs
+	viewJob
+)
+
+// nodeRow is a node in the node list, its health and the inventory it last reported
+type nodeRow struct {
+	hostname string
+	status   *pb.NodeStatus
+	info     *pb.SysInfo
+}
+
+// panes is output from several hosts shown side by side, either a command that is running or a job from the history
+type panes struct {
+	title  string
+	panes  []*outputPane
+	byHost map[string]*outputPane
+	focus  int
+	zoom   bool
+	//cancel stops the command the panes are showing, nil when it isn't running
+	cancel context.CancelFunc
+	jobID  string
+	done   int
+	failed int
+}
+
+func newPanes(title string, hosts []string) *panes {
+	p := &panes{title: title, byHost: make(map[string]*outputPane)}
+	for _, host := range hosts {
+		p.pane(host).status = "pending"
+	}
+	return p
+}
+
+// pane returns the pane of the host, adding one when the host has none yet
+func (p *panes) pane(host string) *outputPane {
+	if pane, ok := p.byHost[host]; ok {
+		return pane
+	}
+	pane := &outputPane{title: host}
+	p.byHost[host] = pane
+	p.panes = append(p.panes, pane)
+	return pane
+}
+
+// prompt is a line of text being typed in the status line
+type prompt struct {
+	label  string
+	text   string
+	submit func(t *tui, text string)
+}
+
+// tui is the state of the interactive ui. Only the event loop touches it, anything running in the background hands
+// its result to the loop as a function on updates.
+type tui struct {
+	ctx     context.Context
+	cc      pb.CommandManagerClient
+	server  string
+	width   int
+	height  int
+	view    view
+	updates chan func(t *tui)
+	prompt  *prompt
+	message string
+	failed  bool
+
+	pattern    string
+	nodes      []*nodeRow
+	nodeCursor int
+	picked     map[string]bool
+	command    string
+
+	jobs      []*pb.JobSummary
+	jobCursor int
+
+	run *panes
+	job *panes
+}
+
+// Interactive runs the terminal ui until it is quit. It lists the nodes with their health and inventory, runs
+// commands on the picked nodes with the output of each host in its own pane, and browses the job history.
+func (cl *Client) Interactive(ctx context.Context) error {
+	stdin, stdout := int(os.Stdin.Fd()), int(os.Stdout.Fd())
+	if !term.IsTerminal(stdin) || !term.IsTerminal(stdout) {
+		return errors.New("the interactive ui needs a terminal")
+	}
+	return cl.withManager(ctx, func(cc pb.CommandManagerClient) error {
+		width, height, err := term.GetSize(stdout)
+		if err != nil {
+			return err
+		}
+		state, err := term.MakeRaw(stdin)
+		if err != nil {
+			return err
+		}
+		defer term.Restore(stdin, state)
+		fmt.Fprint(os.Stdout, altScreenOn+cursorHide)
+		defer fmt.Fprint(os.Stdout, cursorShow+altScreenOff)
+
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		t := &tui{
+			ctx:     ctx,
+			cc:      cc,
+			server:  cl.CoordinationServer,
+			width:   width,
+			height:  height,
+			updates: make(chan func(t *tui), 64),
+			picked:  make(map[string]bool),
+		}
+		return t.loop(os.Stdin, os.Stdout)
+	})
+}
+
+// loop draws the ui and handles keys, size changes and background results until the ui is quit
+func (t *tui) loop(in io.Reader, out io.Writer) error {
+	keys := make(chan string)
+	go readKeys(in, keys)
+	resize := make(chan os.Signal, 1)
+	notifyResize(resize)
+	ticker := time.NewTicker(nodeRefreshInterval)
+	defer ticker.Stop()
+
+	t.loadNodes()
+	for {
+		if err := t.render().draw(out); err != nil {
+			return err
+		}
+		select {
+		case <-t.ctx.Done():
+			return nil
+		case k, ok := <-keys:
+			if !ok || t.key(k) {
+				if t.run != nil && t.run.cancel != nil {
+					t.run.cancel()
+				}
+				return nil
+			}
+		case fn := <-t.updates:
+			fn(t)
+		case <-resize:
+			if w, h, err := term.GetSize(int(os.Stdout.Fd())); err == nil {
+				t.width, t.height = w, h
+			}
+		case <-ticker.C:
+			if t.view == viewNodes {
+				t.loadNodes()
+			}
+		}
+		//output arrives in bursts, apply everything that is waiting before drawing again
+		for pending := len(t.updates); pending > 0; pending-- {
+			(<-t.updates)(t)
+		}
+	}
+}
+
+// post hands fn to the event loop
+func (t *tui) post(fn func(t *tui)) {
+	select {
+	case t.updates <- fn:
+	case <-t.ctx.Done():
+	}
+}
+
+func (t *tui) info(format string, args ...any) {
+	t.message, t.failed = fmt.Sprintf(format, args...), false
+}
+
+func (t *tui) fail(err error) {
+	t.message, t.failed = err.Error(), true
+}
+
+// loadNodes gets the health and inventory of the nodes matching the pattern
+func (t *tui) loadNodes() {
+	pattern := t.pattern
+	go func() {
+		statuses, err := t.cc.GetNodeStatus(t.ctx, &pb.NodeStatusQuery{Pattern: pattern})
+		if err != nil {
+			t.post(func(t *tui) { t.fail(fmt.Errorf("unable to load nodes: %w", err)) })
+			return
+		}
+		inventory, err := t.cc.GetInventory(t.ctx, &pb.InventoryQuery{Pattern: pattern})
+		if err != nil {
+			t.post(func(t *tui) { t.fail(fmt.Errorf("unable to load inventory: %w", err)) })
+			return
+		}
+		rows := make(map[string]*nodeRow)
+		for _, s := range statuses.Nodes {
+			rows[s.Hostname] = &nodeRow{hostname: s.Hostname, status: s}
+		}
+		for _, info := range inventory.Nodes {
+			if row, ok := rows[info.Hostname]; ok {
+				row.info = info
+				continue
+			}
+			rows[info.Hostname] = &nodeRow{hostname: info.Hostname, info: info}
+		}
+		nodes := make([]*nodeRow, 0, len(rows))
+		for _, row := range rows {
+			nodes = append(nodes, row)
+		}
+		slices.SortFunc(nodes, func(a, b *nodeRow) int { return strings.Compare(a.hostname, b.hostname) })
+
+		t.post(func(t *tui) {
+			if pattern != t.pattern {
+				//the filter changed while loading, a newer load is on its way
+				return
+			}
+			t.nodes = nodes
+			t.nodeCursor = max(0, min(t.nodeCursor, len(nodes)-1))
+			if t.failed && strings.HasPrefix(t.message, "unable to load") {
+				t.message = ""
+			}
+		})
+	}()
+}
+
+// loadJobs gets the most recent jobs
+func (t *tui) loadJobs() {
+	limit := int32(max(t.height, 50))
+	go func() {
+		res, err := t.cc.ListJobs(t.ctx, &pb.JobQuery{Limit: limit})
+		t.post(func(t *tui) {
+			if err != nil {
+				t.fail(fmt.Errorf("unable to load jobs: %w", err))
+				return
+			}
+			t.jobs = res.Jobs
+			t.jobCursor = max(0, min(t.jobCursor, len(t.jobs)-1))
+		})
+	}()
+}
+
+// openJob shows the output each host of the job recorded
+func (t *tui) openJob(jobID string) {
+	go func() {
+		job, err := t.cc.GetJob(t.ctx, &pb.JobID{JobId: jobID})
+		t.post(func(t *tui) {
+			if err != nil {
+				t.fail(fmt.Errorf("unable to load job %s: %w", jobID, err))
+				return
+			}
+			s := job.Summary
+			p := newPanes(fmt.Sprintf("job %s  %s  on %s  %s", s.JobId, s.Command, s.Pattern, statusName(s.Status.String(), "JOB_")), nil)
+			p.jobID = s.JobId
+			for _, rec := range job.Records {
+				pane := p.pane(rec.Hostname)
+				pane.write(rec.Stdout)
+				pane.write(rec.Stderr)
+				if rec.Error != "" {
+					pane.write([]byte("\nerror: " + rec.Error))
+				}
+				pane.status = statusName(rec.Status.String(), "HOST_")
+				if rec.Finished != nil && rec.Started != nil {
+					pane.status += fmt.Sprintf(" with code %d", rec.ExitCode)
+				}
+			}
+			t.job = p
+			t.view = viewJob
+		})
+	}()
+}
+
+// targets is the pattern commands run on and the hosts it is expected to match, the picked nodes or every node in
+// the list when none are picked
+func (t *tui) targets() (string, []string) {
+	hosts := make([]string, 0, len(t.picked))
+	for _, n := range t.nodes {
+		if t.picked[n.hostname] {
+			hosts = append(hosts, n.hostname)
+		}
+	}
+	if len(hosts) > 0 {
+		return strings.Join(hosts, ","), hosts
+	}
+	for _, n := range t.nodes {
+		hosts = append(hosts, n.hostname)
+	}
+	return t.pattern, hosts
+}
+
+// runCommand runs the command in a shell on the targets and streams the output of each host into its pane
+func (t *tui) runCommand(command string) {
+	if t.run != nil && t.run.cancel != nil {
+		t.fail(errors.New("a command is still running, cancel it with x first"))
+		return
+	}
+	pattern, hosts := t.targets()
+	if len(hosts) == 0 {
+		t.fail(errors.New("no nodes to run the command on"))
+		return
+	}
+	t.command = command
+	ctx, cancel := context.WithCancel(t.ctx)
+	p := newPanes(fmt.Sprintf("%s  on %s", command, orDash(pattern)), hosts)
+	p.cancel = cancel
+	t.run = p
+	t.view = viewRun
+	t.message = ""
+
+	go func() {
+		defer cancel()
+		stream, err := t.cc.SendCommandToNodesStream(ctx, &pb.CommanderRequest{Pattern: pattern, Command: command, Shell: true})
+		if err != nil {
+			t.post(func(t *tui) { p.cancel = nil; t.fail(err) })
+			return
+		}
+		for {
+			r, err := stream.Recv()
+			if err != nil {
+				t.post(func(t *tui) {
+					p.cancel = nil
+					switch {
+					case errors.Is(err, io.EOF):
+						t.info("%s finished on %d of %d nodes, %d failed", command, p.done, len(p.panes), p.failed)
+					case ctx.Err() != nil:
+						for _, pane := range p.panes {
+							if pane.status == "pending" || pane.status == "running" {
+								pane.status = "canceled"
+							}
+						}
+						t.info("%s canceled", command)
+					default:
+						t.fail(fmt.Errorf("lost connection to coordination server: %w", err))
+					}
+				})
+				return
+			}
+			t.post(func(t *tui) { p.receive(r) })
+		}
+	}()
+}
+
+// receive adds a response from the command stream to the pane of its host
+func (p *panes) receive(r *pb.CommandStreamResponse) {
+	if p.jobID == "" {
+		p.jobID = r.JobId
+	}
+	pane := p.pane(r.Hostname)
+	switch payload := r.Payload.(type) {
+	case *pb.CommandStreamResponse_Output:
+		pane.status = "running"
+		pane.write(payload.Output.Data)
+	case *pb.CommandStreamResponse_Exit:
+		res := payload.Exit
+		p.done++
+		if !res.Successful {
+			p.failed++
+		}
+		if res.Error != "" {
+			pane.write([]byte("\nerror: " + res.Error))
+		}
+		switch res.Reason {
+		case pb.TerminationReason_SIGNALED:
+			pane.status = fmt.Sprintf("terminated by %s after %s", res.Signal, res.Duration.AsDuration().Round(time.Millisecond))
+		case pb.TerminationReason_EXITED, pb.TerminationReason_TIMED_OUT, pb.TerminationReason_CANCELED:
+			pane.status = fmt.Sprintf("%s with code %d after %s", strings.ReplaceAll(statusName(res.Reason.String(), ""), "_", " "), res.ExitCode, res.Duration.AsDuration().Round(time.Millisecond))
+		default:
+			pane.status = "did not run"
+		}
+	}
+}
+
+// key handles a key press and reports whether the ui should quit
+func (t *tui) key(k string) bool {
+	if t.prompt != nil {
+		t.promptKey(k)
+		return false
+	}
+	switch k {
+	case "q", "ctrl+c":
+		return true
+	case "tab":
+		switch t.view {
+		case viewNodes:
+			t.show(viewRun)
+		case viewRun:
+			t.show(viewJobs)
+		default:
+			t.show(viewNodes)
+		}
+		return false
+	case "1":
+		t.show(viewNodes)
+		return false
+	case "2":
+		t.show(viewRun)
+		return false
+	case "3":
+		t.show(viewJobs)
+		return false
+	}
+
+	switch t.view {
+	case viewNodes:
+		t.nodesKey(k)
+	case viewJobs:
+		t.jobsKey(k)
+	case viewRun:
+		t.panesKey(t.run, k)
+	case viewJob:
+		t.panesKey(t.job, k)
+	}
+	return false
+}
+
+func (t *tui) show(v view) {
+	if v == viewRun && t.run == nil {
+		t.info("no command has run yet, pick nodes and press enter")
+		return
+	}
+	if v == viewJobs {
+		t.loadJobs()
+	}
+	t.view = v
+}
+
+func (t *tui) nodesKey(k string) {
+	switch k {
+	case "up", "k":
+		t.nodeCursor = max(0, t.nodeCursor-1)
+	case "down", "j":
+		t.nodeCursor = max(0, min(len(t.nodes)-1, t.nodeCursor+1))
+	case "home", "g":
+		t.nodeCursor = 0
+	case "end", "G":
+		t.nodeCursor = max(0, len(t.nodes)-1)
+	case " ":
+		if t.nodeCursor < len(t.nodes) {
+			host := t.nodes[t.nodeCursor].hostname
+			if t.picked[host] {
+				delete(t.picked, host)
+			} else {
+				t.picked[host] = true
+			}
+			t.nodeCursor = min(len(t.nodes)-1, t.nodeCursor+1)
+		}
+	case "a":
+		all := len(t.nodes) > 0
+		for _, n := range t.nodes {
+			all = all && t.picked[n.hostname]
+		}
+		clear(t.picked)
+		if !all {
+			for _, n := range t.nodes {
+				t.picked[n.hostname] = true
+			}
+		}
+	case "/":
+		t.prompt = &prompt{label: "pattern: ", text: t.pattern, submit: func(t *tui, text string) {
+			t.pattern = strings.TrimSpace(text)
+			clear(t.picked)
+			t.nodeCursor = 0
+			t.loadNodes()
+		}}
+	case "enter", "r":
+		t.promptCommand()
+	case "R":
+		t.loadNodes()
+	}
+}
+
+func (t *tui) promptCommand() {
+	t.prompt = &prompt{label: "command: ", text: t.command, submit: func(t *tui, text string) {
+		if text = strings.TrimSpace(text); text != "" {
+			t.runCommand(text)
+		}
+	}}
+}
+
+func (t *tui) jobsKey(k string) {
+	switch k {
+	case "up", "k":
+		t.jobCursor = max(0, t.jobCursor-1)
+	case "down", "j":
+		t.jobCursor = max(0, min(len(t.jobs)-1, t.jobCursor+1))
+	case "home", "g":
+		t.jobCursor = 0
+	case "end", "G":
+		t.jobCursor = max(0, len(t.jobs)-1)
+	case "enter":
+		if t.jobCursor < len(t.jobs) {
+			t.openJob(t.jobs[t.jobCursor].JobId)
+		}
+	case "R":
+		t.loadJobs()
+	case "esc":
+		t.view = viewNodes
+	}
+}
+
+func (t *tui) panesKey(p *panes, k string) {
+	if len(p.panes) == 0 {
+		p = &panes{panes: []*outputPane{{}}}
+	}
+	page := max(1, t.height/2)
+	switch k {
+	case "left", "h":
+		p.focus = max(0, p.focus-1)
+	case "right", "l":
+		p.focus = min(len(p.panes)-1, p.focus+1)
+	case "z":
+		p.zoom = !p.zoom
+	case "up", "k":
+		p.panes[p.focus].offset++
+	case "down", "j":
+		p.panes[p.focus].offset = max(0, p.panes[p.focus].offset-1)
+	case "pgup":
+		p.panes[p.focus].offset += page
+	case "pgdown":
+		p.panes[p.focus].offset = max(0, p.panes[p.focus].offset-page)
+	case "x":
+		if p.cancel != nil {
+			p.cancel()
+		}
+	case "enter", "r":
+		if t.view == viewRun {
+			t.promptCommand()
+		}
+	case "esc":
+		if t.view == viewJob {
+			t.view = viewJobs
+		} else {
+			t.view = viewNodes
+		}
+	}
+}
+
+func (t *tui) promptKey(k string) {
+	p := t.prompt
+	switch k {
+	case "enter":
+		t.prompt = nil
+		p.submit(t, p.text)
+	case "esc", "ctrl+c":
+		t.prompt = nil
+	case "backspace":
+		if r := []rune(p.text); len(r) > 0 {
+			p.text = string(r[:len(r)-1])
+		}
+	default:
+		if len([]rune(k)) == 1 {
+			p.text += k
+		}
+	}
+}
+
+// render draws the header, the current view, the status line and the keys that can be pressed
+func (t *tui) render() *screen {
+	s := newScreen(t.width, t.height)
+	tabs := []string{"1 nodes", "2 run", "3 jobs"}
+	active := map[view]int{viewNodes: 0, viewRun: 1, viewJobs: 2, viewJob: 2}[t.view]
+	header := " tailsys "
+	for i, tab := range tabs {
+		if i == active {
+			tab = "[" + tab + "]"
+		} else {
+			tab = " " + tab + " "
+		}
+		header += " " + tab
+	}
+	s.set(0, reverse(fit(header+"   "+t.server, t.width)))
+
+	body := t.height - 3
+	switch t.view {
+	case viewNodes:
+		t.renderNodes(s, 1, body)
+	case viewJobs:
+		t.renderJobs(s, 1, body)
+	case viewRun:
+		t.renderPanes(s, t.run, 1, body)
+	case viewJob:
+		t.renderPanes(s, t.job, 1, body)
+	}
+
+	switch {
+	case t.prompt != nil:
+		s.set(t.height-2, fit(t.prompt.label+t.prompt.text+"█", t.width))
+	case t.failed:
+		s.set(t.height-2, red(fit(t.message, t.width)))
+	default:
+		s.set(t.height-2, fit(t.message, t.width))
+	}
+	s.set(t.height-1, dim(fit(t.help(), t.width)))
+	return s
+}
+
+func (t *tui) help() string {
+	if t.prompt != nil {
+		return "enter submit  esc cancel"
+	}
+	switch t.view {
+	case viewNodes:
+		return "↑↓ move  space pick  a pick all  / filter  enter run command  R refresh  tab switch  q quit"
+	case viewJobs:
+		return "↑↓ move  enter open  R refresh  esc back  tab switch  q quit"
+	case viewRun:
+		return "←→ pane  ↑↓ pgup pgdn scroll  z zoom  enter run again  x cancel  esc back  q quit"
+	}
+	return "←→ pane  ↑↓ pgup pgdn scroll  z zoom  esc back  q quit"
+}
+
+// columns fits each value to the width of its column, the last column takes what is left
+func columns(width int, widths []int, values ...string) string {
+	var b strings.Builder
+	used := 0
+	for i, v := range values {
+		w := width - used
+		if i < len(widths) && widths[i] < w {
+			w = widths[i]
+		}
+		//a space between columns so values that are cut off don't run into the next one
+		b.WriteString(fit(v, w-1) + " ")
+		used += w
+		if used >= width {
+			break
+		}
+	}
+	return fit(b.String(), width)
+}
+
+func (t *tui) renderNodes(s *screen, top, height int) {
+	hostWidth := len("HOSTNAME")
+	for _, n := range t.nodes {
+		hostWidth = max(hostWidth, len(n.hostname))
+	}
+	widths := []int{4, hostWidth + 2, 10, 10, 24, 9, 6, 12}
+	title := fmt.Sprintf(" %d nodes", len(t.nodes))
+	if t.pattern != "" {
+		title += " matching " + t.pattern
+	}
+	if len(t.picked) > 0 {
+		title += fmt.Sprintf(", %d picked", len(t.picked))
+	}
+	s.set(top, bold(fit(title, t.width)))
+	s.set(top+1, bold(columns(t.width, widths, "", "HOSTNAME", "STATUS", "LATENCY", "OS", "ARCH", "CPUS", "MEMORY", "LAST SEEN")))
+
+	rows := height - 2
+	first := max(0, t.nodeCursor-rows+1)
+	for i := 0; i < rows; i++ {
+		idx := first + i
+		if idx >= len(t.nodes) {
+			s.set(top+2+i, fit("", t.width))
+			continue
+		}
+		n := t.nodes[idx]
+		mark := "  "
+		if t.picked[n.hostname] {
+			mark = " *"
+		}
+		health, lat, seen := "-", "-", "-"
+		if n.status != nil {
+			health = statusName(n.status.Health.String(), "NODE_")
+			seen = localTime(n.status.LastSeen)
+			if n.status.LastChecked != nil {
+				lat = latency(n.status.LatencyMs)
+			}
+		}
+		osn, arch, cpus, mem := "-", "-", "-", "-"
+		if inv := n.info.GetInventory(); inv != nil {
+			osn, arch = orDash(osName(inv)), orDash(inv.Arch)
+			cpus = fmt.Sprint(inv.CpuCount)
+			mem = byteSize(inv.MemoryTotalBytes)
+		}
+		line := columns(t.width, widths, mark, n.hostname, health, lat, osn, arch, cpus, mem, seen)
+		if idx == t.nodeCursor {
+			line = reverse(line)
+		} else if health == "offline" {
+			line = red(line)
+		} else if health == "degraded" {
+			line = yellow(line)
+		}
+		s.set(top+2+i, line)
+	}
+}
+
+func (t *tui) renderJobs(s *screen, top, height int) {
+	widths := []int{38, 21, 10, 14, 6, 7}
+	s.set(top, bold(fit(fmt.Sprintf(" %d most recent jobs", len(t.jobs)), t.width)))
+	s.set(top+1, bold(columns(t.width, widths, " JOB ID", "CREATED", "STATUS", "REQUESTER", "HOSTS", "FAILED", "COMMAND")))
+	rows := height - 2
+	first := max(0, t.jobCursor-rows+1)
+	for i := 0; i < rows; i++ {
+		idx := first + i
+		if idx >= len(t.jobs) {
+			s.set(top+2+i, fit("", t.width))
+			continue
+		}
+		j := t.jobs[idx]
+		line := columns(t.width, widths,
+			" "+j.JobId,
+			localTime(j.Created),
+			statusName(j.Status.String(), "JOB_"),
+			j.Requester,
+			fmt.Sprint(j.Hosts),
+			fmt.Sprint(j.Failed),
+			j.Command,
+		)
+		switch {
+		case idx == t.jobCursor:
+			line = reverse(line)
+		case j.Failed > 0:
+			line = red(line)
+		case j.Status == pb.JobStatus_JOB_RUNNING:
+			line = green(line)
+		}
+		s.set(top+2+i, line)
+	}
+}
+
+func (t *tui) renderPanes(s *screen, p *panes, top, height int) {
+	title := " " + p.title
+	if p.jobID != "" && !strings.Contains(title, p.jobID) {
+		title = fmt.Sprintf(" job %s  %s", p.jobID, p.title)
+	}
+	if p.cancel != nil {
+		title += fmt.Sprintf("  running, %d of %d done", p.done, len(p.panes))
+	}
+	s.set(top, bold(fit(title, t.width)))
+
+	shown, focus := p.panes, p.focus
+	if p.zoom && len(p.panes) > 0 {
+		shown, focus = p.panes[p.focus:p.focus+1], 0
+	}
+	for i, line := range renderPanes(shown, t.width, height-1, focus) {
+		s.set(top+1+i, line)
+	}
+}
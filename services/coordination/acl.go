@@ -0,0 +1,561 @@
+package coordination
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	pb "github.com/charles-d-burton/tailsys/commands"
+	"github.com/charles-d-burton/tailsys/connections"
+	"github.com/charles-d-burton/tailsys/data/queries"
+	"github.com/charles-d-burton/tailsys/services"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gopkg.in/yaml.v3"
+)
+
+// ACLPolicy says who may use the coordination server. A caller may do anything the rules that apply to it
+// allow, everything else is rejected.
+//
+//	rules:
+//	  - users: ["alice@example.com"]
+//	    tags: ["tag:ops"]
+//	    nodes: ["web-*"]
+//	    commands: ["uptime", "systemctl status *"]
+//	    run_as: ["deploy"]
+//	    env: ["LANG", "LC_*"]
+//	    states: true
+//	    files: ["/etc/nginx/*"]
+//	    keys: true
+//	    inventory: true
+//	    status: true
+//	    discovery: true
+type ACLPolicy struct {
+	Rules []ACLRule `yaml:"rules"`
+}
+
+// ACLRule applies to callers logged in as one of the users or with one of the tags. It lets them send the
+// commands to the nodes, with states set apply states to the nodes, copy files to and fetch them from the paths on
+// the nodes and, with keys set, manage the keys of every node. With inventory or status set they may read the
+// inventory or health of the nodes, with discovery set list the devices discovery found on the tailnet. Jobs and
+// shell sessions can be read by whoever started them and by callers with a rule for every node they ran on. All
+// values are globs where * matches anything.
+//
+// Commands are matched word by word against an argv or a command that isn't run in a shell, a last word of * matches
+// any number of arguments. In shell mode the whole command is matched and * doesn't match shell metacharacters, so
+// "systemctl status *" doesn't allow "systemctl status x; rm -rf /". A command may only run as another user, with
+// environment variables, with another interpreter or with stdin when the same rule allows it with run_as, env,
+// interpreters or stdin.
+type ACLRule struct {
+	Users        []string `yaml:"users"`
+	Tags         []string `yaml:"tags"`
+	Nodes        []string `yaml:"nodes"`
+	Commands     []string `yaml:"commands"`
+	RunAs        []string `yaml:"run_as"`
+	Env          []string `yaml:"env"`
+	Interpreters []string `yaml:"interpreters"`
+	Stdin        bool     `yaml:"stdin"`
+	States       bool     `yaml:"states"`
+	Files        []string `yaml:"files"`
+	Keys         bool     `yaml:"keys"`
+	Inventory    bool     `yaml:"inventory"`
+	Status       bool     `yaml:"status"`
+	Discovery    bool     `yaml:"discovery"`
+
+	users, tags, nodes, runAs, env, interpreters, files []*regexp.Regexp
+	commands                                            []*commandGlob
+}
+
+// acl reloads the policy file whenever it changes so rules can be edited without a restart
+type acl struct {
+	log     *slog.Logger
+	path    string
+	mu      sync.Mutex
+	modTime time.Time
+	policy  *ACLPolicy
+}
+
+// WithACL sets the policy file used to authorize callers, ConfigDir/acl.yaml when not set
+func (co *Coordinator) WithACL(path string) Option {
+	return func(co *Coordinator) error {
+		co.acl.path = path
+		return nil
+	}
+}
+
+// current returns the policy, nil when there is no policy file
+func (a *acl) current() (*ACLPolicy, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	fi, err := os.Stat(a.path)
+	if errors.Is(err, os.ErrNotExist) {
+		a.policy = nil
+		a.modTime = time.Time{}
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if a.policy != nil && fi.ModTime().Equal(a.modTime) {
+		return a.policy, nil
+	}
+
+	data, err := os.ReadFile(a.path)
+	if err != nil {
+		return nil, err
+	}
+	policy := &ACLPolicy{}
+	if err := yaml.Unmarshal(data, policy); err != nil {
+		return nil, fmt.Errorf("invalid acl policy %s: %w", a.path, err)
+	}
+	for i := range policy.Rules {
+		if err := policy.Rules[i].compile(); err != nil {
+			return nil, fmt.Errorf("invalid acl policy %s: rule %d: %w", a.path, i+1, err)
+		}
+	}
+	a.log.Info("loaded acl policy", "path", a.path, "rules", len(policy.Rules))
+	a.policy = policy
+	a.modTime = fi.ModTime()
+	return policy, nil
+}
+
+// Authorize is called for every request to the coordination server, only callers of the CommandManager are checked.
+// Requests the rules don't mention are rejected.
+func (co *Coordinator) Authorize(ctx context.Context, method string, req any) error {
+	if !strings.HasPrefix(method, "/tailsys.CommandManager/") {
+		return nil
+	}
+	id, rules, all, err := co.callerRules(ctx, method)
+	if err != nil || all {
+		return err
+	}
+	if len(rules) == 0 {
+		return co.deny(id, method, "no acl rule applies to %s", id)
+	}
+	switch in := req.(type) {
+	case *pb.CommanderRequest:
+		hosts, err := co.matchNodes(ctx, in.Pattern)
+		if err != nil {
+			return err
+		}
+		for _, host := range hosts {
+			if !allowsCommand(rules, host.Hostname, in) {
+				return co.deny(id, method, "%s may not run %q%s on %s", id, commandString(in), commandOptions(in), host.Hostname)
+			}
+		}
+	case *pb.StateApplyRequest:
+		hosts, err := co.matchNodes(ctx, in.Pattern)
+		if err != nil {
+			return err
+		}
+		for _, host := range hosts {
+			if !allowsStates(rules, host.Hostname) {
+				return co.deny(id, method, "%s may not apply states on %s", id, host.Hostname)
+			}
+		}
+	case *pb.FileSend:
+		//only the header says where the file goes, the chunks after it are part of the same request
+		header := in.GetHeader()
+		if header == nil {
+			return nil
+		}
+		hosts, err := co.matchNodes(ctx, header.Pattern)
+		if err != nil {
+			return err
+		}
+		path := header.GetFile().GetPath()
+		for _, host := range hosts {
+			if !allowsFile(rules, host.Hostname, path) {
+				return co.deny(id, method, "%s may not copy files to %s on %s", id, path, host.Hostname)
+			}
+		}
+	case *pb.FileFetchRequest:
+		hosts, err := co.matchNodes(ctx, in.Pattern)
+		if err != nil {
+			return err
+		}
+		for _, host := range hosts {
+			if !allowsFile(rules, host.Hostname, in.Path) {
+				return co.deny(id, method, "%s may not fetch %s from %s", id, in.Path, host.Hostname)
+			}
+		}
+	case *pb.ShellInput:
+		//the session is authorized when it is opened, what is typed into it afterwards isn't checked
+		open := in.GetOpen()
+		if open == nil {
+			return nil
+		}
+		host, err := co.shellHost(ctx, open.Host)
+		if err != nil {
+			return err
+		}
+		//an interactive shell is the command "shell", a command after -- is run as an argv
+		cmd := &pb.CommanderRequest{Argv: open.Command, RunAs: open.User}
+		if len(cmd.Argv) == 0 {
+			cmd.Argv = []string{shellString(open)}
+		}
+		if !allowsCommand(rules, host.Hostname, cmd) {
+			return co.deny(id, method, "%s may not run %q%s on %s", id, shellString(open), commandOptions(cmd), host.Hostname)
+		}
+	case *pb.KeyQuery:
+		for _, rule := range rules {
+			if rule.Keys {
+				return nil
+			}
+		}
+		return co.deny(id, method, "%s may not manage node keys", id)
+	case *pb.NodeQuery:
+		//only lists the hostnames a pattern matches, the cli uses it to show what a command would run on
+		return nil
+	case *pb.JobQuery, *pb.ShellSessionQuery:
+		//the handlers leave out what the caller may not read
+		return nil
+	case *pb.JobID:
+		job, err := queries.GetJob(co.DB, in.JobId)
+		if errors.Is(err, sql.ErrNoRows) {
+			//the handler says it wasn't found
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		hosts, err := queries.GetJobHosts(co.DB, in.JobId)
+		if err != nil {
+			return err
+		}
+		if !mayRead(id, rules, job.Requester, hosts) {
+			return co.deny(id, method, "%s may not read or cancel job %s", id, in.JobId)
+		}
+	case *pb.ShellSessionID:
+		session, err := queries.GetShellSession(co.DB, in.SessionId)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if !mayRead(id, rules, session.Requester, []string{session.Hostname}) {
+			return co.deny(id, method, "%s may not read shell session %s", id, in.SessionId)
+		}
+	case *pb.InventoryQuery:
+		hosts, err := co.matchNodes(ctx, in.Pattern)
+		if err != nil {
+			return err
+		}
+		for _, host := range hosts {
+			if !allowsOn(rules, host.Hostname, func(r *ACLRule) bool { return r.Inventory }) {
+				return co.deny(id, method, "%s may not read the inventory of %s", id, host.Hostname)
+			}
+		}
+	case *pb.NodeStatusQuery:
+		hosts, err := co.matchNodes(ctx, in.Pattern)
+		if err != nil {
+			return err
+		}
+		for _, host := range hosts {
+			if !allowsOn(rules, host.Hostname, func(r *ACLRule) bool { return r.Status }) {
+				return co.deny(id, method, "%s may not read the status of %s", id, host.Hostname)
+			}
+		}
+	case *pb.DiscoveryQuery:
+		if !slices.ContainsFunc(rules, func(r *ACLRule) bool { return r.Discovery }) {
+			return co.deny(id, method, "%s may not discover nodes", id)
+		}
+	default:
+		return co.deny(id, method, "%s may not call %s", id, method)
+	}
+	return nil
+}
+
+// callerRules returns the caller along with the rules that apply to it, all is set when there is no policy in dev mode
+// and the caller may do anything
+func (co *Coordinator) callerRules(ctx context.Context, method string) (id *connections.Identity, rules []*ACLRule, all bool, err error) {
+	id = connections.IdentityFromContext(ctx)
+	if id == nil {
+		return nil, nil, false, status.Errorf(codes.Unauthenticated, "unable to identify the caller of %s", method)
+	}
+	policy, err := co.acl.current()
+	if err != nil {
+		co.Logger().Error("unable to load acl policy", "err", err)
+		return nil, nil, false, status.Error(codes.PermissionDenied, "acl policy could not be loaded")
+	}
+	if policy == nil {
+		if co.devMode {
+			return id, nil, true, nil
+		}
+		return nil, nil, false, status.Errorf(codes.PermissionDenied, "no acl policy at %s, every request is rejected", co.acl.path)
+	}
+	return id, policy.rulesFor(id), false, nil
+}
+
+// readableBy returns whether the caller of ctx may read a job or shell session, for the handlers that list them
+func (co *Coordinator) readableBy(ctx context.Context, method string) (func(requester string, hosts []string) bool, error) {
+	id, rules, all, err := co.callerRules(ctx, method)
+	if err != nil {
+		return nil, err
+	}
+	return func(requester string, hosts []string) bool {
+		return all || mayRead(id, rules, requester, hosts)
+	}, nil
+}
+
+// mayRead reports whether the caller may read or cancel a job or shell session. Callers may always read their own,
+// those of others only when every host they ran on is one of the nodes of a rule that applies to the caller.
+func mayRead(id *connections.Identity, rules []*ACLRule, requester string, hosts []string) bool {
+	if requester == id.String() {
+		return true
+	}
+	//a user may read what they ran from any of their nodes
+	if id.LoginName != "" && (requester == id.LoginName || strings.HasPrefix(requester, id.LoginName+" (")) {
+		return true
+	}
+	if len(hosts) == 0 {
+		return false
+	}
+	for _, host := range hosts {
+		if !allowsOn(rules, host, func(*ACLRule) bool { return true }) {
+			return false
+		}
+	}
+	return true
+}
+
+func (co *Coordinator) deny(id *connections.Identity, method, format string, args ...any) error {
+	err := status.Errorf(codes.PermissionDenied, format, args...)
+	co.Logger().Warn("denied request", "method", method, "caller", id.String(), "err", err)
+	return err
+}
+
+// rulesFor returns the rules that apply to the caller
+func (p *ACLPolicy) rulesFor(id *connections.Identity) []*ACLRule {
+	rules := make([]*ACLRule, 0)
+	for i := range p.Rules {
+		rule := &p.Rules[i]
+		if id.LoginName != "" && matchAny(rule.users, id.LoginName) {
+			rules = append(rules, rule)
+			continue
+		}
+		for _, tag := range id.Tags {
+			if matchAny(rule.tags, tag) {
+				rules = append(rules, rule)
+				break
+			}
+		}
+	}
+	return rules
+}
+
+// allowsCommand reports whether a single rule allows the command on the host along with the user it runs as, its
+// environment, interpreter and stdin
+func allowsCommand(rules []*ACLRule, hostname string, cmd *pb.CommanderRequest) bool {
+	for _, rule := range rules {
+		if matchAny(rule.nodes, hostname) && rule.allowsCommand(cmd) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *ACLRule) allowsCommand(cmd *pb.CommanderRequest) bool {
+	if cmd.RunAs != "" && !matchAny(r.runAs, cmd.RunAs) {
+		return false
+	}
+	for name := range cmd.Env {
+		if !matchAny(r.env, name) {
+			return false
+		}
+	}
+	if len(cmd.Stdin) > 0 && !r.Stdin {
+		return false
+	}
+
+	var words []string
+	switch {
+	case len(cmd.Argv) > 0:
+		words = cmd.Argv
+	case cmd.Shell:
+		if cmd.Interpreter != "" && !matchAny(r.interpreters, cmd.Interpreter) {
+			return false
+		}
+		for _, c := range r.commands {
+			if c.shell.MatchString(cmd.Command) {
+				return true
+			}
+		}
+		return false
+	default:
+		//the node splits the command on whitespace the same way
+		words = strings.Fields(cmd.Command)
+	}
+	for _, c := range r.commands {
+		if c.matchWords(words) {
+			return true
+		}
+	}
+	return false
+}
+
+// commandOptions describes what else a command asks for, for the message when it is denied
+func commandOptions(cmd *pb.CommanderRequest) string {
+	opts := make([]string, 0)
+	if cmd.RunAs != "" {
+		opts = append(opts, "as "+cmd.RunAs)
+	}
+	if len(cmd.Env) > 0 {
+		names := make([]string, 0, len(cmd.Env))
+		for name := range cmd.Env {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		opts = append(opts, "with env "+strings.Join(names, ","))
+	}
+	if cmd.Shell && cmd.Interpreter != "" {
+		opts = append(opts, "with interpreter "+cmd.Interpreter)
+	}
+	if len(cmd.Stdin) > 0 {
+		opts = append(opts, "with stdin")
+	}
+	if len(opts) == 0 {
+		return ""
+	}
+	return " " + strings.Join(opts, " ")
+}
+
+// commandGlob is a command allowed by a rule, compiled once for matching word by word and once for shell mode
+type commandGlob struct {
+	words []*regexp.Regexp
+	//rest is set when the last word is *, it matches one or more remaining words
+	rest  bool
+	shell *regexp.Regexp
+}
+
+// shellMeta are the characters a * in a command glob doesn't match in shell mode
+const shellMeta = ";&|<>()$`\\\"'{}\n\r"
+
+func compileCommand(glob string) (*commandGlob, error) {
+	c := &commandGlob{}
+	words := strings.Fields(glob)
+	if len(words) > 0 && words[len(words)-1] == "*" {
+		c.rest = true
+		words = words[:len(words)-1]
+	}
+	var err error
+	if c.words, err = compileGlobs(words); err != nil {
+		return nil, err
+	}
+
+	expr := regexp.QuoteMeta(glob)
+	notMeta := "[^" + regexp.QuoteMeta(shellMeta) + "]"
+	expr = strings.ReplaceAll(expr, `\*`, notMeta+"*")
+	expr = strings.ReplaceAll(expr, `\?`, notMeta)
+	if c.shell, err = regexp.Compile("^" + expr + "$"); err != nil {
+		return nil, fmt.Errorf("invalid glob %s: %w", glob, err)
+	}
+	return c, nil
+}
+
+func (c *commandGlob) matchWords(words []string) bool {
+	if len(words) < len(c.words) || (!c.rest && len(words) != len(c.words)) || (c.rest && len(words) == len(c.words)) {
+		return false
+	}
+	for i, re := range c.words {
+		if !re.MatchString(words[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *ACLRule) compile() error {
+	var err error
+	if r.users, err = compileGlobs(r.Users); err != nil {
+		return err
+	}
+	if r.tags, err = compileGlobs(r.Tags); err != nil {
+		return err
+	}
+	if r.nodes, err = compileGlobs(r.Nodes); err != nil {
+		return err
+	}
+	r.commands = make([]*commandGlob, 0, len(r.Commands))
+	for _, glob := range r.Commands {
+		c, err := compileCommand(glob)
+		if err != nil {
+			return err
+		}
+		r.commands = append(r.commands, c)
+	}
+	if r.runAs, err = compileGlobs(r.RunAs); err != nil {
+		return err
+	}
+	if r.env, err = compileGlobs(r.Env); err != nil {
+		return err
+	}
+	if r.interpreters, err = compileGlobs(r.Interpreters); err != nil {
+		return err
+	}
+	r.files, err = compileGlobs(r.Files)
+	return err
+}
+
+func allowsStates(rules []*ACLRule, hostname string) bool {
+	return allowsOn(rules, hostname, func(r *ACLRule) bool { return r.States })
+}
+
+// allowsOn reports whether a rule whose nodes match the host allows what allowed checks
+func allowsOn(rules []*ACLRule, hostname string, allowed func(*ACLRule) bool) bool {
+	for _, rule := range rules {
+		if matchAny(rule.nodes, hostname) && allowed(rule) {
+			return true
+		}
+	}
+	return false
+}
+
+// allowsFile is false for a path that isn't clean, a * in a rule matches .. so /etc/nginx/../shadow would match
+// /etc/nginx/*
+func allowsFile(rules []*ACLRule, hostname, path string) bool {
+	if services.CheckPath(path) != nil {
+		return false
+	}
+	for _, rule := range rules {
+		if matchAny(rule.nodes, hostname) && matchAny(rule.files, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// compileGlobs turns globs into regular expressions, unlike path.Match a * also matches / and spaces in commands
+func compileGlobs(globs []string) ([]*regexp.Regexp, error) {
+	res := make([]*regexp.Regexp, 0, len(globs))
+	for _, glob := range globs {
+		expr := regexp.QuoteMeta(glob)
+		expr = strings.ReplaceAll(expr, `\*`, ".*")
+		expr = strings.ReplaceAll(expr, `\?`, ".")
+		re, err := regexp.Compile("^" + expr + "$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid glob %s: %w", glob, err)
+		}
+		res = append(res, re)
+	}
+	return res, nil
+}
+
+func matchAny(globs []*regexp.Regexp, s string) bool {
+	for _, re := range globs {
+		if re.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,298 @@
+package coordination
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	pb "github.com/charles-d-burton/tailsys/commands"
+	"github.com/charles-d-burton/tailsys/connections"
+	"github.com/charles-d-burton/tailsys/data/queries"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const testPolicy = `
+rules:
+  - users: ["alice@example.com"]
+    nodes: ["web*"]
+    commands: ["uptime", "systemctl status *", "shell"]
+    run_as: ["deploy"]
+    env: ["LANG", "LC_*"]
+    interpreters: ["/bin/bash -c"]
+    files: ["/etc/nginx/*"]
+  - users: ["bob@example.com"]
+    nodes: ["db*"]
+    commands: ["uptime"]
+    stdin: true
+    inventory: true
+    status: true
+    states: true
+  - users: ["carol@example.com"]
+    nodes: ["*"]
+    keys: true
+    discovery: true
+`
+
+// newTestCoordinator returns a coordinator with its own database, the policy and the hosts accepted
+func newTestCoordinator(t *testing.T, policy string, hosts ...string) *Coordinator {
+	t.Helper()
+	dir := t.TempDir()
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	co := &Coordinator{}
+	if err := co.WithLogger(log)(&co.Tailnet); err != nil {
+		t.Fatal(err)
+	}
+	if err := co.StartDB(dir, log); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { co.CloseDB() })
+
+	co.acl.log = log
+	co.acl.path = filepath.Join(dir, "acl.yaml")
+	co.nodeGroups.log = log
+	co.nodeGroups.path = filepath.Join(dir, "nodegroups.yaml")
+	if policy != "" {
+		if err := os.WriteFile(co.acl.path, []byte(policy), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, host := range hosts {
+		err := queries.InsertHostRegistration(co.DB, &queries.RegisteredHostsData{
+			Hostname:    host,
+			Key:         host,
+			Status:      queries.NodeAccepted,
+			Fingerprint: host,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return co
+}
+
+func as(login, node string) context.Context {
+	return connections.ContextWithIdentity(context.Background(), &connections.Identity{LoginName: login, Node: node})
+}
+
+func alice() context.Context { return as("alice@example.com", "laptop") }
+func bob() context.Context   { return as("bob@example.com", "desktop") }
+func carol() context.Context { return as("carol@example.com", "laptop") }
+
+const sendCommand = "/tailsys.CommandManager/SendCommandToNodes"
+
+type aclCase struct {
+	name   string
+	ctx    context.Context
+	method string
+	req    any
+	allow  bool
+}
+
+func checkAuthorize(t *testing.T, co *Coordinator, tests []aclCase) {
+	t.Helper()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := co.Authorize(tt.ctx, tt.method, tt.req)
+			if tt.allow && err != nil {
+				t.Fatalf("expected request to be allowed, got %v", err)
+			}
+			if !tt.allow && status.Code(err) != codes.PermissionDenied {
+				t.Fatalf("expected request to be denied, got %v", err)
+			}
+		})
+	}
+}
+
+func TestAuthorizeCommands(t *testing.T) {
+	co := newTestCoordinator(t, testPolicy, "web1", "web2", "db1")
+	cmd := func(c *pb.CommanderRequest) *pb.CommanderRequest {
+		if c.Pattern == "" {
+			c.Pattern = "web1"
+		}
+		return c
+	}
+	checkAuthorize(t, co, []aclCase{
+		{"command", alice(), sendCommand, cmd(&pb.CommanderRequest{Command: "uptime"}), true},
+		{"command on every matching node", alice(), sendCommand, cmd(&pb.CommanderRequest{Pattern: "web*", Command: "uptime"}), true},
+		{"command on a node outside the rule", alice(), sendCommand, cmd(&pb.CommanderRequest{Pattern: "db1", Command: "uptime"}), false},
+		{"command on some nodes outside the rule", alice(), sendCommand, cmd(&pb.CommanderRequest{Pattern: "*", Command: "uptime"}), false},
+		{"command not allowed", alice(), sendCommand, cmd(&pb.CommanderRequest{Command: "reboot"}), false},
+		{"extra argument", alice(), sendCommand, cmd(&pb.CommanderRequest{Command: "uptime -p"}), false},
+		{"trailing glob", alice(), sendCommand, cmd(&pb.CommanderRequest{Command: "systemctl status nginx"}), true},
+		{"trailing glob many arguments", alice(), sendCommand, cmd(&pb.CommanderRequest{Command: "systemctl status nginx sshd"}), true},
+		{"trailing glob needs an argument", alice(), sendCommand, cmd(&pb.CommanderRequest{Command: "systemctl status"}), false},
+		{"separator without a shell is an argument", alice(), sendCommand, cmd(&pb.CommanderRequest{Command: "uptime; reboot"}), false},
+		{"argv", alice(), sendCommand, cmd(&pb.CommanderRequest{Argv: []string{"systemctl", "status", "nginx"}}), true},
+		{"argv with a space in an argument", alice(), sendCommand, cmd(&pb.CommanderRequest{Argv: []string{"systemctl", "status", "x; reboot"}}), true},
+		{"argv other binary", alice(), sendCommand, cmd(&pb.CommanderRequest{Argv: []string{"systemctl", "stop", "nginx"}}), false},
+		{"argv ignores command", alice(), sendCommand, cmd(&pb.CommanderRequest{Command: "uptime", Argv: []string{"reboot"}}), false},
+		{"shell", alice(), sendCommand, cmd(&pb.CommanderRequest{Shell: true, Command: "systemctl status nginx"}), true},
+		{"shell separator", alice(), sendCommand, cmd(&pb.CommanderRequest{Shell: true, Command: "systemctl status x; reboot"}), false},
+		{"shell pipe", alice(), sendCommand, cmd(&pb.CommanderRequest{Shell: true, Command: "systemctl status x | sh"}), false},
+		{"shell and", alice(), sendCommand, cmd(&pb.CommanderRequest{Shell: true, Command: "systemctl status x && reboot"}), false},
+		{"shell substitution", alice(), sendCommand, cmd(&pb.CommanderRequest{Shell: true, Command: "systemctl status $(reboot)"}), false},
+		{"shell backticks", alice(), sendCommand, cmd(&pb.CommanderRequest{Shell: true, Command: "systemctl status `reboot`"}), false},
+		{"shell newline", alice(), sendCommand, cmd(&pb.CommanderRequest{Shell: true, Command: "systemctl status x\nreboot"}), false},
+		{"shell redirect", alice(), sendCommand, cmd(&pb.CommanderRequest{Shell: true, Command: "systemctl status x > /etc/passwd"}), false},
+		{"interpreter", alice(), sendCommand, cmd(&pb.CommanderRequest{Shell: true, Interpreter: "/bin/bash -c", Command: "uptime"}), true},
+		{"interpreter not allowed", alice(), sendCommand, cmd(&pb.CommanderRequest{Shell: true, Interpreter: "/usr/bin/python3 -c", Command: "uptime"}), false},
+		{"run as", alice(), sendCommand, cmd(&pb.CommanderRequest{Command: "uptime", RunAs: "deploy"}), true},
+		{"run as root", alice(), sendCommand, cmd(&pb.CommanderRequest{Command: "uptime", RunAs: "root"}), false},
+		{"run as without run_as", bob(), sendCommand, cmd(&pb.CommanderRequest{Pattern: "db1", Command: "uptime", RunAs: "postgres"}), false},
+		{"env", alice(), sendCommand, cmd(&pb.CommanderRequest{Command: "uptime", Env: map[string]string{"LANG": "C", "LC_ALL": "C"}}), true},
+		{"env not allowed", alice(), sendCommand, cmd(&pb.CommanderRequest{Command: "uptime", Env: map[string]string{"LANG": "C", "LD_PRELOAD": "/tmp/x.so"}}), false},
+		{"stdin not allowed", alice(), sendCommand, cmd(&pb.CommanderRequest{Command: "uptime", Stdin: []byte("x")}), false},
+		{"stdin", bob(), sendCommand, cmd(&pb.CommanderRequest{Pattern: "db1", Command: "uptime", Stdin: []byte("x")}), true},
+		{"no rule applies", as("dave@example.com", "laptop"), sendCommand, cmd(&pb.CommanderRequest{Command: "uptime"}), false},
+		{"rules of others", bob(), sendCommand, cmd(&pb.CommanderRequest{Command: "uptime"}), false},
+	})
+}
+
+func TestAuthorizeShells(t *testing.T) {
+	co := newTestCoordinator(t, testPolicy, "web1", "db1")
+	const method = "/tailsys.CommandManager/Shell"
+	open := func(o *pb.ShellOpen) *pb.ShellInput {
+		return &pb.ShellInput{Payload: &pb.ShellInput_Open{Open: o}}
+	}
+	checkAuthorize(t, co, []aclCase{
+		{"login shell", alice(), method, open(&pb.ShellOpen{Host: "web1"}), true},
+		{"login shell as an allowed user", alice(), method, open(&pb.ShellOpen{Host: "web1", User: "deploy"}), true},
+		{"login shell as root", alice(), method, open(&pb.ShellOpen{Host: "web1", User: "root"}), false},
+		{"command", alice(), method, open(&pb.ShellOpen{Host: "web1", Command: []string{"uptime"}}), true},
+		{"command not allowed", alice(), method, open(&pb.ShellOpen{Host: "web1", Command: []string{"bash"}}), false},
+		{"node outside the rule", alice(), method, open(&pb.ShellOpen{Host: "db1"}), false},
+		{"input after the shell was opened", alice(), method, &pb.ShellInput{Payload: &pb.ShellInput_Data{Data: []byte("ls\n")}}, true},
+	})
+}
+
+func TestAuthorizeDefaultDeny(t *testing.T) {
+	co := newTestCoordinator(t, testPolicy, "web1", "db1")
+	checkAuthorize(t, co, []aclCase{
+		{"unknown request", alice(), "/tailsys.CommandManager/Unknown", &pb.PingRequest{}, false},
+		{"keys", alice(), "/tailsys.CommandManager/ListKeys", &pb.KeyQuery{}, false},
+		{"keys allowed", carol(), "/tailsys.CommandManager/AcceptKeys", &pb.KeyQuery{}, true},
+		{"inventory", bob(), "/tailsys.CommandManager/GetInventory", &pb.InventoryQuery{Pattern: "db1"}, true},
+		{"inventory of nodes outside the rule", bob(), "/tailsys.CommandManager/GetInventory", &pb.InventoryQuery{Pattern: "*"}, false},
+		{"inventory without inventory", alice(), "/tailsys.CommandManager/GetInventory", &pb.InventoryQuery{Pattern: "web1"}, false},
+		{"status", bob(), "/tailsys.CommandManager/GetNodeStatus", &pb.NodeStatusQuery{Pattern: "db1"}, true},
+		{"status without status", alice(), "/tailsys.CommandManager/GetNodeStatus", &pb.NodeStatusQuery{Pattern: "web1"}, false},
+		{"discovery", carol(), "/tailsys.CommandManager/DiscoverNodes", &pb.DiscoveryQuery{}, true},
+		{"discovery without discovery", bob(), "/tailsys.CommandManager/DiscoverNodes", &pb.DiscoveryQuery{}, false},
+		{"states", bob(), "/tailsys.CommandManager/ApplyState", &pb.StateApplyRequest{Pattern: "db1"}, true},
+		{"states without states", alice(), "/tailsys.CommandManager/ApplyState", &pb.StateApplyRequest{Pattern: "web1"}, false},
+		{"fetch", alice(), "/tailsys.CommandManager/FetchFile", &pb.FileFetchRequest{Pattern: "web1", Path: "/etc/nginx/nginx.conf"}, true},
+		{"fetch outside files", alice(), "/tailsys.CommandManager/FetchFile", &pb.FileFetchRequest{Pattern: "web1", Path: "/etc/shadow"}, false},
+		{"fetch with traversal", alice(), "/tailsys.CommandManager/FetchFile", &pb.FileFetchRequest{Pattern: "web1", Path: "/etc/nginx/../shadow"}, false},
+		{"fetch with traversal past the root", alice(), "/tailsys.CommandManager/FetchFile", &pb.FileFetchRequest{Pattern: "web1", Path: "/etc/nginx/../../../etc/shadow"}, false},
+		{"listing nodes", alice(), "/tailsys.CommandManager/GetNodes", &pb.NodeQuery{Pattern: "*"}, true},
+	})
+}
+
+// sendFile is the header of a copy of a file to path on the nodes
+func sendFile(pattern, path string) *pb.FileSend {
+	return &pb.FileSend{Payload: &pb.FileSend_Header{Header: &pb.FileSendHeader{
+		Pattern: pattern,
+		File:    &pb.FileHeader{Path: path},
+	}}}
+}
+
+func TestAuthorizeSendFile(t *testing.T) {
+	co := newTestCoordinator(t, testPolicy, "web1", "db1")
+	method := "/tailsys.CommandManager/SendFile"
+	checkAuthorize(t, co, []aclCase{
+		{"send", alice(), method, sendFile("web1", "/etc/nginx/nginx.conf"), true},
+		{"send in a sub directory", alice(), method, sendFile("web1", "/etc/nginx/conf.d/site.conf"), true},
+		{"send outside files", alice(), method, sendFile("web1", "/root/.ssh/authorized_keys"), false},
+		{"send outside nodes", alice(), method, sendFile("db1", "/etc/nginx/nginx.conf"), false},
+		{"send with traversal", alice(), method, sendFile("web1", "/etc/nginx/../../root/.ssh/authorized_keys"), false},
+		{"send with a dot", alice(), method, sendFile("web1", "/etc/nginx/./nginx.conf"), false},
+		{"send with a double slash", alice(), method, sendFile("web1", "/etc/nginx//nginx.conf"), false},
+		{"send relative", alice(), method, sendFile("web1", "etc/nginx/nginx.conf"), false},
+		{"data after the header", alice(), method, &pb.FileSend{Payload: &pb.FileSend_Data{Data: []byte("x")}}, true},
+	})
+}
+
+func TestAuthorizeWithoutPolicy(t *testing.T) {
+	co := newTestCoordinator(t, "", "web1")
+	req := &pb.CommanderRequest{Pattern: "web1", Command: "reboot"}
+	if err := co.Authorize(alice(), sendCommand, req); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected every request to be denied without a policy, got %v", err)
+	}
+	co.devMode = true
+	if err := co.Authorize(alice(), sendCommand, req); err != nil {
+		t.Fatalf("expected every request to be allowed without a policy in dev mode, got %v", err)
+	}
+	if err := co.Authorize(context.Background(), sendCommand, req); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("expected a caller without an identity to be turned away, got %v", err)
+	}
+	if err := co.Authorize(context.Background(), "/tailsys.Registration/Register", &pb.NodeRegistrationRequest{}); err != nil {
+		t.Fatalf("expected methods outside the command manager to be left alone, got %v", err)
+	}
+}
+
+func TestAuthorizeJobsAndSessions(t *testing.T) {
+	co := newTestCoordinator(t, testPolicy, "web1", "db1")
+	addJob := func(id, requester string, hosts ...string) {
+		err := queries.InsertJob(co.DB, &queries.CommandJobRow{JobID: id, Requester: requester, Command: "uptime", Created: time.Now()})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, host := range hosts {
+			if _, err := queries.InsertCommandRecord(co.DB, id, host); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	addJob("web-job", "alice@example.com (laptop)", "web1")
+	addJob("both-job", "alice@example.com (laptop)", "web1", "db1")
+	addJob("empty-job", "dave@example.com (laptop)")
+	err := queries.InsertShellSession(co.DB, &queries.ShellSessionRow{SessionID: "db-shell", Requester: "bob@example.com (desktop)", Hostname: "db1", Started: time.Now()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const getJob = "/tailsys.CommandManager/GetJob"
+	const cancelJob = "/tailsys.CommandManager/CancelJob"
+	const getSession = "/tailsys.CommandManager/GetShellSession"
+	checkAuthorize(t, co, []aclCase{
+		{"own job", alice(), getJob, &pb.JobID{JobId: "web-job"}, true},
+		{"own job from another node", as("alice@example.com", "phone"), getJob, &pb.JobID{JobId: "both-job"}, true},
+		{"job on nodes outside the rules", bob(), getJob, &pb.JobID{JobId: "web-job"}, false},
+		{"job partly outside the rules", bob(), cancelJob, &pb.JobID{JobId: "both-job"}, false},
+		{"job on nodes of a rule", carol(), cancelJob, &pb.JobID{JobId: "both-job"}, true},
+		{"job without hosts of someone else", carol(), getJob, &pb.JobID{JobId: "empty-job"}, false},
+		{"job that doesn't exist", alice(), getJob, &pb.JobID{JobId: "missing"}, true},
+		{"login that is a prefix of another", as("alice@example.co", "laptop"), getJob, &pb.JobID{JobId: "empty-job"}, false},
+		{"own session", bob(), getSession, &pb.ShellSessionID{SessionId: "db-shell"}, true},
+		{"session on a node outside the rules", alice(), getSession, &pb.ShellSessionID{SessionId: "db-shell"}, false},
+		{"session on nodes of a rule", carol(), getSession, &pb.ShellSessionID{SessionId: "db-shell"}, true},
+	})
+
+	c := &CommanderServer{DB: co.DB, CO: co}
+	jobs, err := c.ListJobs(bob(), &pb.JobQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs.Jobs) != 0 {
+		t.Fatalf("expected bob to see no jobs, got %d", len(jobs.Jobs))
+	}
+	jobs, err = c.ListJobs(alice(), &pb.JobQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs.Jobs) != 2 {
+		t.Fatalf("expected alice to see her 2 jobs, got %d", len(jobs.Jobs))
+	}
+	sessions, err := c.ListShellSessions(alice(), &pb.ShellSessionQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sessions.Sessions) != 0 {
+		t.Fatalf("expected alice to see no shell sessions, got %d", len(sessions.Sessions))
+	}
+}
@@ -0,0 +1,101 @@
+package coordination
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// maxTranscript caps how much of a shell session is recorded, the rest of a session past it is only marked
+const maxTranscript = 16 << 20
+
+// transcript records a shell session in the asciicast v2 format so it can be replayed with asciinema. Only what the
+// terminal shows is recorded, input that isn't echoed like passwords stays out of the audit log.
+type transcript struct {
+	mu        sync.Mutex
+	buf       bytes.Buffer
+	start     time.Time
+	partial   []byte
+	truncated bool
+}
+
+// castHeader is the first line of an asciicast v2 recording
+type castHeader struct {
+	Version   int               `json:"version"`
+	Width     uint32            `json:"width"`
+	Height    uint32            `json:"height"`
+	Timestamp int64             `json:"timestamp"`
+	Title     string            `json:"title,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+func newTranscript(title, term string, cols, rows uint32) *transcript {
+	t := &transcript{start: time.Now()}
+	header := castHeader{
+		Version:   2,
+		Width:     cols,
+		Height:    rows,
+		Timestamp: t.start.Unix(),
+		Title:     title,
+	}
+	if term != "" {
+		header.Env = map[string]string{"TERM": term}
+	}
+	data, _ := json.Marshal(header)
+	t.buf.Write(data)
+	t.buf.WriteByte('\n')
+	return t
+}
+
+// output records what the terminal printed. Chunks can end in the middle of a character, those bytes are held
+// until the rest of the character arrives since the events have to be valid UTF-8.
+func (t *transcript) output(data []byte) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	data = append(t.partial, data...)
+	end := len(data)
+	//a character is at most 4 bytes, look back that far for one that isn't complete yet
+	for i := len(data) - 1; i >= 0 && i >= len(data)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(data[i]) {
+			if !utf8.FullRune(data[i:]) {
+				end = i
+			}
+			break
+		}
+	}
+	t.partial = append([]byte(nil), data[end:]...)
+	if end > 0 {
+		t.event("o", string(data[:end]))
+	}
+}
+
+// resize records the new size of the terminal
+func (t *transcript) resize(cols, rows uint32) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.event("r", fmt.Sprintf("%dx%d", cols, rows))
+}
+
+// event appends an event to the recording, once it is full a marker says the rest of the session is missing
+func (t *transcript) event(kind, data string) {
+	if t.truncated {
+		return
+	}
+	line, _ := json.Marshal([]any{time.Since(t.start).Seconds(), kind, data})
+	if t.buf.Len()+len(line) > maxTranscript {
+		t.truncated = true
+		line, _ = json.Marshal([]any{time.Since(t.start).Seconds(), "m", "transcript truncated"})
+	}
+	t.buf.Write(line)
+	t.buf.WriteByte('\n')
+}
+
+// bytes returns the recording so far
+func (t *transcript) bytes() []byte {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return bytes.Clone(t.buf.Bytes())
+}
@@ -0,0 +1,141 @@
+package coordination
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	pb "github.com/charles-d-burton/tailsys/commands"
+	"github.com/charles-d-burton/tailsys/connections"
+	"github.com/charles-d-burton/tailsys/data/queries"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// MethodRoles are the certificates allowed to call each method on the coordination server. Nodes can register
+// before they have a certificate, only operators can send commands.
+var MethodRoles = connections.MethodRoles{
+	"/tailsys.Registration/Register": {connections.RolePublic},
+	"/tailsys.Registration/Renew":    {connections.RoleNode},
+	"/tailsys.Tunnel/Connect":        {connections.RoleNode},
+	"/tailsys.CommandManager/":       {connections.RoleOperator},
+}
+
+// StartCertificateAuthority loads the ca, creating it the first time, and issues the coordinators own certificate
+func (co *Coordinator) StartCertificateAuthority(ctx context.Context) error {
+	ca, err := connections.LoadCA(co.ConfigDir + "/ca")
+	if err != nil {
+		return fmt.Errorf("unable to load certificate authority: %w", err)
+	}
+	co.CA = ca
+	if err := co.refreshCertificate(); err != nil {
+		return err
+	}
+	go co.rotateCertificate(ctx)
+	return nil
+}
+
+// refreshCertificate reissues our certificate when it is close to expiring and picks up any new revocations,
+// including the ones made with tailsys ca revoke while the server is running
+func (co *Coordinator) refreshCertificate() error {
+	crl, err := co.CA.CRL()
+	if err != nil {
+		return err
+	}
+	cert := ""
+	if co.CertificateExpiring() {
+		csr, err := co.CertificateRequest()
+		if err != nil {
+			return err
+		}
+		cert, err = co.CA.Sign(csr, co.Hostname, connections.RoleCoordinator, connections.SystemCertLifetime)
+		if err != nil {
+			return fmt.Errorf("unable to issue coordinator certificate: %w", err)
+		}
+	}
+	return co.SetCertificate(cert, co.CA.Bundle(), crl)
+}
+
+func (co *Coordinator) rotateCertificate(ctx context.Context) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := co.refreshCertificate(); err != nil {
+				co.Logger().Error("unable to refresh coordinator certificate", "err", err)
+			}
+		}
+	}
+}
+
+// revokeNode revokes every certificate issued to the node so it can't connect anymore
+func (co *Coordinator) revokeNode(hostname string) error {
+	co.conns.remove(hostname)
+	n, err := co.CA.RevokeName(hostname, connections.RoleNode)
+	if err != nil || n == 0 {
+		return err
+	}
+	return co.refreshCertificate()
+}
+
+// certificate signs the csr for the node and returns it along with the ca bundle and crl
+func (r *RegistrationServer) certificate(csr []byte, hostname string) (*pb.Certificate, error) {
+	res := &pb.Certificate{
+		Ca: r.CA.Bundle(),
+	}
+	crl, err := r.CA.CRL()
+	if err != nil {
+		return nil, err
+	}
+	res.Crl = crl
+	if len(csr) == 0 {
+		return res, nil
+	}
+	res.Cert, err = r.CA.Sign(csr, hostname, connections.RoleNode, connections.SystemCertLifetime)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return res, nil
+}
+
+// Renew issues a new certificate to a node that is still allowed to connect
+func (r *RegistrationServer) Renew(ctx context.Context, in *pb.RenewRequest) (*pb.Certificate, error) {
+	hostname := connections.PeerCertificate(ctx).Subject.CommonName
+	host, err := queries.GetRegisteredHost(r.DB, hostname)
+	if err != nil {
+		return nil, status.Errorf(codes.PermissionDenied, "%s is not registered", hostname)
+	}
+	if host.Status == queries.NodeRejected || host.Status == queries.NodeRevoked {
+		return nil, status.Errorf(codes.PermissionDenied, "%s has been %s", hostname, host.Status)
+	}
+	if len(in.Csr) > 0 {
+		fp, err := csrFingerprint(in.Csr)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		if fp != host.Fingerprint {
+			return nil, status.Errorf(codes.PermissionDenied, "%s registered with a different key", hostname)
+		}
+	}
+	return r.certificate(in.Csr, hostname)
+}
+
+// csrFingerprint identifies the key a node registered with
+func csrFingerprint(der []byte) (string, error) {
+	csr, err := x509.ParseCertificateRequest(der)
+	if err != nil {
+		return "", fmt.Errorf("invalid certificate request: %w", err)
+	}
+	pub, err := x509.MarshalPKIXPublicKey(csr.PublicKey)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(pub)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -3,36 +3,37 @@ package coordination
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"io"
 	"sync"
 	"time"
 
 	"github.com/charles-d-burton/tailsys/commands"
 	pb "github.com/charles-d-burton/tailsys/commands"
-	"github.com/charles-d-burton/tailsys/connections"
 	"github.com/charles-d-burton/tailsys/data/queries"
-	"google.golang.org/protobuf/proto"
+	"github.com/charles-d-burton/tailsys/services"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type CommanderServer struct {
-	sync.WaitGroup
 	pb.UnimplementedCommandManagerServer
 	DB *sql.DB
 	CO *Coordinator
 	ID string
+
+	jobs *jobManager
 }
 
 func (c *CommanderServer) GetNodes(ctx context.Context, in *pb.NodeQuery) (*pb.NodeQueryResponse, error) {
 	res := &pb.NodeQueryResponse{}
-	nodes, err := queries.GetMatchRegisteredHosts(c.DB, in.Pattern)
+	nodes, err := c.CO.matchNodes(ctx, in.Pattern)
 	if err != nil {
 		return nil, err
 	}
 
-	names := make([]string, 0)
-	for node := range nodes {
-    fmt.Println("looked up node: ", node.Hostname)
+	names := make([]string, 0, len(nodes))
+	for _, node := range nodes {
 		names = append(names, node.Hostname)
 	}
 	res.Nodes = names
@@ -41,82 +42,234 @@ func (c *CommanderServer) GetNodes(ctx context.Context, in *pb.NodeQuery) (*pb.N
 
 func (c *CommanderServer) SendCommandToNodes(ctx context.Context, cmd *pb.CommanderRequest) (*pb.AggregateResponses, error) {
 	agg := &commands.AggregateResponses{}
-	hosts, err := queries.GetMatchRegisteredHosts(c.DB, cmd.Pattern)
+
+	//without a command timeout fall back to the default, otherwise leave the client time to report the timeout
+	timeout := time.Second * 10
+	if t := cmd.GetTimeout().AsDuration(); t > 0 {
+		timeout = t + time.Second*10
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	job, err := c.startJob(ctx, ctx, cmd)
 	if err != nil {
 		return nil, err
 	}
+	agg.JobId = job.id
+	w := job.watch(ctx.Done(), false)
+	go c.runJob(job)
 
-	cmds := c.streamCommand(cmd.Command, hosts, 50)
-	for rcmd := range cmds {
-		agg.Response = append(agg.Response, rcmd)
+	//collect the streamed output for each host into a single response
+	stdout := make(map[string][]byte)
+	stderr := make(map[string][]byte)
+	for ev := range w.events {
+		switch payload := ev.Event.(type) {
+		case *pb.JobEvent_Output:
+			if payload.Output.Stream == pb.OutputStream_STDERR {
+				stderr[ev.Hostname] = append(stderr[ev.Hostname], payload.Output.Data...)
+			} else {
+				stdout[ev.Hostname] = append(stdout[ev.Hostname], payload.Output.Data...)
+			}
+		case *pb.JobEvent_Exit:
+			res := payload.Exit
+			res.Stdout = stdout[ev.Hostname]
+			res.Stderr = stderr[ev.Hostname]
+			delete(stdout, ev.Hostname)
+			delete(stderr, ev.Hostname)
+			agg.Response = append(agg.Response, res)
+		}
+	}
+	if err := w.err(); err != nil {
+		return nil, err
 	}
 
 	return agg, nil
 }
 
 func (c *CommanderServer) SendCommandToNodesStream(cmd *commands.CommanderRequest, stream pb.CommandManager_SendCommandToNodesStreamServer) error {
-	hosts, err := queries.GetMatchRegisteredHosts(c.DB, cmd.Pattern)
+	ctx := stream.Context()
+	job, err := c.startJob(ctx, ctx, cmd)
 	if err != nil {
 		return err
 	}
-	cmds := c.streamCommand(cmd.Command, hosts, 50)
-	for rcmd := range cmds {
-		if err := stream.Send(rcmd); err != nil {
+	w := job.watch(ctx.Done(), false)
+	go c.runJob(job)
+
+	for ev := range w.events {
+		res := &pb.CommandStreamResponse{
+			Hostname: ev.Hostname,
+			JobId:    ev.JobId,
+		}
+		switch payload := ev.Event.(type) {
+		case *pb.JobEvent_Output:
+			res.Payload = &pb.CommandStreamResponse_Output{Output: payload.Output}
+		case *pb.JobEvent_Exit:
+			res.Payload = &pb.CommandStreamResponse_Exit{Exit: payload.Exit}
+		default:
+			continue
+		}
+		if err := stream.Send(res); err != nil {
 			return err
 		}
 	}
-	return nil
+	return w.err()
 }
 
-func (c *CommanderServer) streamCommand(cmd string, hosts chan *queries.RegisteredHostsData, limit uint16) chan *commands.CommandResponse {
+// streamCommand fans the job out to its hosts, running at most limit at a time, and returns once every host is finished.
+// Hosts that haven't been sent the command by the time the job is canceled are never sent it.
+func (c *CommanderServer) streamCommand(job *runningJob, limit uint16) {
 	if limit < 1 {
 		limit = 50
 	}
-	responses := make(chan *commands.CommandResponse, 100)
-	go func(hosts chan *queries.RegisteredHostsData, responses chan *commands.CommandResponse) {
-		//create the semaphore pool
-		sem := make(chan struct{}, limit)
-		fmt.Println("starting command processor stream")
-		for host := range hosts {
-			c.Add(1) //increment the waitgroup
-			sem <- struct{}{}
-			go c.sendCommand(cmd, host, sem, responses)
+	//create the semaphore pool
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	job.log.Debug("sending command to hosts", "hosts", len(job.hosts))
+	for _, host := range job.hosts {
+		select {
+		case sem <- struct{}{}:
+		case <-job.ctx.Done():
+		}
+		if job.ctx.Err() != nil {
+			c.skipHost(job, host.Hostname)
+			continue
 		}
-		close(sem)
-		c.Wait()         //wait for all worker processes to finish
-		close(responses) //producer closes
-	}(hosts, responses)
+		wg.Add(1)
+		go func(host *queries.RegisteredHostsData) {
+			defer wg.Done()          //decrement the wait group
+			defer func() { <-sem }() //make space in the semaphore channel
+			c.sendCommand(job, host)
+		}(host)
+	}
+	wg.Wait() //wait for all worker processes to finish
+	close(sem)
+}
 
-	return responses
+// skipHost records a host that was still pending when the job was canceled
+func (c *CommanderServer) skipHost(job *runningJob, hostname string) {
+	res := &pb.CommandResponse{
+		Timestamp: timestamppb.Now(),
+		ExitCode:  -1,
+		Hostname:  hostname,
+		Reason:    pb.TerminationReason_CANCELED,
+		Error:     "job canceled before it was sent to the host",
+	}
+	job.records[hostname].finish(res, pb.HostStatus_HOST_CANCELED)
+	job.publish(&pb.JobEvent{
+		Hostname: hostname,
+		Event:    &pb.JobEvent_Exit{Exit: res},
+	})
+	job.hostStatus(hostname, pb.HostStatus_HOST_CANCELED)
 }
 
-func (c *CommanderServer) sendCommand(cmd string, node *queries.RegisteredHostsData, sem chan struct{}, results chan *commands.CommandResponse) {
-	defer c.Done()           //decrement the wait group
-	defer func() { <-sem }() //make space in the semaphore channel
-	req := &pb.NodeRegistrationRequest{}
-	if err := proto.Unmarshal(node.Data, req); err != nil {
-		fmt.Println(fmt.Errorf("unable to unmarshal req: %w", err))
+// sendCommand runs the command on a single node, publishes every chunk of output with the hostname attached
+// and records the result against the job
+func (c *CommanderServer) sendCommand(job *runningJob, node *queries.RegisteredHostsData) {
+	hostname := node.Hostname
+	log := job.log.With("host", hostname)
+	rec := job.records[hostname]
+	rec.start()
+	job.hostStatus(hostname, pb.HostStatus_HOST_RUNNING)
+	commandsDispatched.With(hostname).Inc()
+	started := time.Now()
+
+	done := func(res *pb.CommandResponse) {
+		res.Hostname = hostname
+		hs := pb.HostStatus_HOST_FAILED
+		switch {
+		case res.Successful:
+			hs = pb.HostStatus_HOST_SUCCEEDED
+		case res.Reason == pb.TerminationReason_CANCELED, job.ctx.Err() != nil:
+			hs = pb.HostStatus_HOST_CANCELED
+		}
+		rec.finish(res, hs)
+		commandsFinished.With(hostname, commandResult(res.Successful, hs == pb.HostStatus_HOST_CANCELED)).Inc()
+		commandDuration.With(hostname).Observe(time.Since(started).Seconds())
+		job.publish(&pb.JobEvent{
+			Hostname: hostname,
+			Event:    &pb.JobEvent_Exit{Exit: res},
+		})
+		job.hostStatus(hostname, hs)
+	}
+	failed := func(err error) {
+		log.Warn("command failed", "err", err)
+		done(&pb.CommandResponse{
+			Timestamp:  timestamppb.Now(),
+			Successful: false,
+			ExitCode:   -1,
+			Error:      err.Error(),
+		})
+	}
+
+	log.Debug("connecting to node")
+	conn, err := c.CO.dialNode(job.ctx, node)
+	if err != nil {
+		failed(err)
 		return
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
-	defer cancel()
-  fmt.Println("connecting to rpc client: ", req.Info.Hostname)
-	conn, err := c.CO.DialContext(ctx, req.Info.Hostname+":"+req.Info.Port, &connections.TLSConfig{TLSKey: req.Tlskey, TLSCert: req.Tlscert})
+	defer conn.Close()
+
+	cmdReq, err := c.commandRequest(job.cmd, hostname)
 	if err != nil {
-		fmt.Println(fmt.Errorf("unable to connect to client: %s with err %w", req.Info.Hostname, err))
+		failed(fmt.Errorf("unable to sign command for host %s: %w", hostname, err))
 		return
 	}
 	cc := pb.NewCommandRunnerClient(conn)
-	r, err := cc.Command(ctx, &pb.CommandRequest{
-		Requested: timestamppb.Now(),
-		Command:   cmd,
-		Key:       &commands.Key{Key: c.ID},
-	})
-
+	stream, err := cc.CommandStream(job.ctx, cmdReq)
 	if err != nil {
-		fmt.Println(fmt.Errorf("unable to send command: %s to host %s with err: %w", cmd, req.Info.Hostname, err))
+		failed(fmt.Errorf("unable to send command: %s to host %s with err: %w", job.cmd.Command, hostname, err))
 		return
 	}
-	results <- r
-	fmt.Printf("successfully ran command %s on host %s with output %s\n", cmd, req.Info.Hostname, string(r.Output))
+
+	for {
+		r, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			failed(fmt.Errorf("host %s closed the stream without an exit status", hostname))
+			return
+		}
+		if err != nil && job.ctx.Err() != nil {
+			//tearing down the stream is what interrupts the command on the client
+			done(&pb.CommandResponse{
+				Timestamp: timestamppb.Now(),
+				ExitCode:  -1,
+				Reason:    pb.TerminationReason_CANCELED,
+				Error:     "job canceled while the command was running",
+			})
+			return
+		}
+		if err != nil {
+			failed(fmt.Errorf("command: %s on host %s failed with err: %w", job.cmd.Command, hostname, err))
+			return
+		}
+		if out := r.GetOutput(); out != nil {
+			rec.output(out)
+			job.publish(&pb.JobEvent{
+				Hostname: hostname,
+				Event:    &pb.JobEvent_Output{Output: out},
+			})
+		}
+		if exit := r.GetExit(); exit != nil {
+			log.Info("command finished", "exit_code", exit.ExitCode, "reason", exit.Reason.String())
+			done(exit)
+			return
+		}
+	}
+}
+
+// commandRequest builds the signed request sent to a client from the request made to the coordinator
+func (c *CommanderServer) commandRequest(cmd *pb.CommanderRequest, hostname string) (*pb.CommandRequest, error) {
+	req := &pb.CommandRequest{
+		Hostname:    hostname,
+		Command:     cmd.Command,
+		Key:         &commands.Key{Key: c.ID},
+		Timeout:     cmd.Timeout,
+		Argv:        cmd.Argv,
+		Shell:       cmd.Shell,
+		Interpreter: cmd.Interpreter,
+		Cwd:         cmd.Cwd,
+		Env:         cmd.Env,
+		RunAs:       cmd.RunAs,
+		Stdin:       cmd.Stdin,
+	}
+	return req, services.SignRequest(c.CO.identity.Key, req)
 }
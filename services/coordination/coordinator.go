@@ -4,23 +4,32 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	pb "github.com/charles-d-burton/tailsys/commands"
 	"github.com/charles-d-burton/tailsys/connections"
 	"github.com/charles-d-burton/tailsys/data/queries"
 	"github.com/charles-d-burton/tailsys/services"
-	"github.com/google/uuid"
-	"google.golang.org/protobuf/proto"
-	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 // Coordinator holds the runtime variables for the coordination server
 type Coordinator struct {
 	connections.Tailnet
 	services.DataManagement
-	devMode bool
-	ID      string
+	CA         *connections.CA
+	acl        acl
+	nodeGroups nodeGroups
+	discovery  discovery
+	health     HealthThresholds
+	tunnels    tunnels
+	conns      connPool
+	maxSend    uint64
+	background sync.WaitGroup
+	devMode    bool
+	ID         string
+
+	identity *services.NodeIdentity
 }
 
 // Options defines the configuration options function for configuration injection
@@ -35,21 +44,31 @@ func (co *Coordinator) NewCoordinator(ctx context.Context, opts ...Option) error
 			return err
 		}
 	}
-	co.ID = uuid.NewString()
+	return nil
+}
+
+// LoadIdentity loads the identity of the coordinator from the data directory, nodes use it to verify our commands
+func (co *Coordinator) LoadIdentity() error {
+	id, err := services.LoadIdentity(co.ConfigDir, co.Logger())
+	if err != nil {
+		return fmt.Errorf("unable to load identity: %w", err)
+	}
+	co.identity = id
+	co.ID = id.ID
+	co.Logger().Info("loaded identity", "id", co.ID)
 	return nil
 }
 
 // WithDevMode enable the server to run in dev mode
 func (co *Coordinator) WithDevMode(mode bool) Option {
 	return func(co *Coordinator) error {
-		fmt.Println("setting dev mode to: ", mode)
 		co.devMode = mode
 		return nil
 	}
 }
 
 func (co *Coordinator) StartDatabase(ctx context.Context) error {
-	return co.StartDB(co.ConfigDir)
+	return co.StartDB(co.ConfigDir, co.Logger())
 }
 
 // StartRPCCoordinationServer Register the gRPC server endpoints and start the server
@@ -57,110 +76,83 @@ func (co *Coordinator) StartRPCCoordinationServer(ctx context.Context) error {
 	if co.DB == nil {
 		return errors.New("datastore not initialized")
 	}
-	pb.RegisterPingerServer(co.GRPCServer, &services.Pinger{})
-	pb.RegisterRegistrationServer(co.GRPCServer, &RegistrationServer{
-		DevMode:  co.devMode,
-		DB:       co.DB,
-		Hostname: co.Hostname,
-		//TODO: This is randomized on startup, we should persist and load
-		ID: co.ID,
-	})
-
-	pb.RegisterCommandManagerServer(co.GRPCServer, &CommanderServer{
-		DB: co.DB,
-		CO: co,
-		ID: co.ID,
-	})
-
-	fmt.Println("rpc server starting to serve traffic")
-	co.StartPingService(ctx)
-	return co.GRPCServer.Serve(co.Listener)
-}
-
-func (co *Coordinator) StartPingService(ctx context.Context) {
-	fmt.Println("starting node pings in the background")
-	go co.pingNodes(ctx, 10)
-}
-
-func (co *Coordinator) pingNodes(ctx context.Context, limit uint16) {
-	if limit < 1 {
-		limit = 50
+	if co.CA == nil {
+		return errors.New("certificate authority not initialized")
 	}
-
-	//Fill the semaphore pool
-	sem := make(chan struct{}, limit)
-	defer close(sem)
-
-	fmt.Println("starting ping ticker")
-	ticker := time.NewTicker(time.Second * 15)
-	pingRunning := false
-	for range ticker.C {
-		if !pingRunning {
-			pingRunning = true
-			hosts := queries.GetRegisteredHosts(co.DB)
-			for host := range hosts {
-				sem <- struct{}{} //Block until sem has space
-				go co.ping(ctx, sem, host)
-			}
-			pingRunning = false
-			continue
-		}
-		fmt.Println("ping already running, waiting on previous attempt")
+	if co.identity == nil {
+		return errors.New("identity not loaded")
 	}
-}
-
-func (co *Coordinator) ping(ctx context.Context, sem chan struct{}, hostRow *queries.RegisteredHostsData) {
-	defer func() { <-sem }() //make space in sem
-
-	var node pb.NodeRegistrationRequest
-	err := proto.Unmarshal(hostRow.Data, &node)
-	if err != nil {
-		fmt.Println(fmt.Errorf("error parsing host proto: %w\n", err))
-		return
+	co.acl.log = co.Logger()
+	co.nodeGroups.log = co.Logger()
+	if co.acl.path == "" {
+		co.acl.path = co.ConfigDir + "/acl.yaml"
 	}
-
-	lastSeen := time.Now().Sub(node.Info.LastSeen.AsTime())
-	if lastSeen.Minutes() > 10 {
-		fmt.Printf("node %s has not been seen in more than ten minutes, not pinging\n", hostRow.Hostname)
-		return
+	if _, err := co.acl.current(); err != nil {
+		return err
 	}
-	fmt.Println("pinging node")
-	fmt.Println(node.GetInfo().Ip)
-	host := hostRow.Hostname
-	ctxTo, cancel := context.WithTimeout(ctx, time.Second*2)
-	defer cancel()
-	conn, err := co.DialContext(ctxTo, host+":"+node.Info.Port, &connections.TLSConfig{TLSKey: node.Tlskey, TLSCert: node.Tlscert})
-	//TODO: Probably need to set the tailnet fqdn at some point
-	defer conn.Close()
-
-	if err != nil {
-		fmt.Println(fmt.Errorf("error parsing host proto: %w\n", err))
-		return
+	if co.nodeGroups.path == "" {
+		co.nodeGroups.path = co.ConfigDir + "/nodegroups.yaml"
 	}
-
-	p := pb.NewPingerClient(conn)
-	r, err := p.Ping(ctx, &pb.PingRequest{
-		Ping: timestamppb.Now(),
-	})
-	if err != nil {
-		fmt.Println(fmt.Errorf("unable to ping host %s with error: %w", host, err))
-		return
+	if _, err := co.nodeGroups.current(); err != nil {
+		return err
 	}
+	pb.RegisterPingerServer(co.GRPCServer, &services.Pinger{Log: co.Logger()})
+	pb.RegisterRegistrationServer(co.GRPCServer, &RegistrationServer{
+		DevMode:    co.devMode,
+		DB:         co.DB,
+		CA:         co.CA,
+		SigningKey: co.identity.PublicKey(),
+		Hostname:   co.Hostname,
+		ID:         co.ID,
+		Log:        co.Logger(),
+	})
 
-	fmt.Println("latency: ", r.InboundLatency)
-	node.Info.LastSeen = timestamppb.Now()
-	data, err := proto.Marshal(&node)
-	if err != nil {
-		fmt.Println("unable to marshal registration proto")
+	//jobs can't outlive the server that was running them
+	if err := queries.AbandonJobs(co.DB, time.Now().UTC()); err != nil {
+		return fmt.Errorf("unable to clean up unfinished jobs: %w", err)
 	}
+	if err := queries.AbandonShells(co.DB, time.Now().UTC()); err != nil {
+		return fmt.Errorf("unable to clean up unfinished shell sessions: %w", err)
+	}
+	pb.RegisterTunnelServer(co.GRPCServer, &TunnelServer{CO: co})
+	commander := &CommanderServer{
+		DB:   co.DB,
+		CO:   co,
+		ID:   co.ID,
+		jobs: newJobManager(),
+	}
+	pb.RegisterCommandManagerServer(co.GRPCServer, commander)
 
-	err = queries.UpdateRegisteredHost(co.DB, &queries.RegisteredHostsData{
-		Hostname: host,
-		Key:      node.GetKey().Key,
-		Data:     data,
-	})
-
-	if err != nil {
-		fmt.Println(fmt.Errorf("unable to update registration record: %w", err))
+	co.Logger().Info("coordination server serving", "addr", co.Addr, "dev_mode", co.devMode)
+	defer co.registerMetrics()()
+	co.conns.start(ctx, co.Logger())
+	co.StartPingService(ctx)
+	co.StartDiscovery(ctx)
+
+	//jobs run in the background so the server stopping doesn't wait for them, they are drained on their own
+	drained := make(chan struct{})
+	go func() {
+		<-ctx.Done()
+		commander.jobs.drain(co.ShutdownTimeout())
+		//the tunnels were carrying the commands of the jobs, they can go now
+		co.tunnels.shutdown()
+		close(drained)
+	}()
+	err := co.Serve(ctx, co.Listener)
+	if ctx.Err() != nil {
+		<-drained
 	}
+	co.background.Wait()
+	co.conns.closeAll()
+	return err
+}
+
+// StartPingService pings the accepted nodes in the background to keep track of their health
+func (co *Coordinator) StartPingService(ctx context.Context) {
+	co.Logger().Debug("starting node pings in the background")
+	co.background.Add(1)
+	go func() {
+		defer co.background.Done()
+		co.pingNodes(ctx, 10)
+	}()
 }
@@ -0,0 +1,233 @@
+package coordination
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	pb "github.com/charles-d-burton/tailsys/commands"
+	"github.com/charles-d-burton/tailsys/data/queries"
+	"github.com/tailscale/tailscale-client-go/tailscale"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// DefaultDiscoveryInterval is how often the tailnet is listed when no interval is configured
+const DefaultDiscoveryInterval = 5 * time.Minute
+
+// discovery finds devices on the tailnet that should be running tailsys and registered nodes that have left it
+type discovery struct {
+	tags     []string
+	interval time.Duration
+	// mu keeps the background discovery and one asked for through the api from running at the same time
+	mu      sync.Mutex
+	checked time.Time
+}
+
+// WithDiscovery sets the tags a device needs for discovery to expect it to register and how often to look,
+// tag:tailsys every five minutes when not set
+func (co *Coordinator) WithDiscovery(tags []string, interval time.Duration) Option {
+	return func(co *Coordinator) error {
+		for _, tag := range tags {
+			if !strings.HasPrefix(tag, "tag:") {
+				return fmt.Errorf("discovery tag %s must be in format tag:<tag>", tag)
+			}
+		}
+		co.discovery.tags = tags
+		co.discovery.interval = interval
+		return nil
+	}
+}
+
+// StartDiscovery lists the tailnet devices in the background, it does nothing without a way to list them
+func (co *Coordinator) StartDiscovery(ctx context.Context) {
+	if len(co.discovery.tags) == 0 {
+		co.discovery.tags = []string{"tag:tailsys"}
+	}
+	if co.discovery.interval <= 0 {
+		co.discovery.interval = DefaultDiscoveryInterval
+	}
+	if !co.CanListDevices() {
+		co.Logger().Info("no tailscale api client, node discovery disabled")
+		return
+	}
+	co.Logger().Info("discovering nodes in the background", "tags", strings.Join(co.discovery.tags, ","), "interval", co.discovery.interval)
+	co.background.Add(1)
+	go func() {
+		defer co.background.Done()
+		ticker := time.NewTicker(co.discovery.interval)
+		defer ticker.Stop()
+		for {
+			if err := co.discover(ctx); err != nil {
+				co.Logger().Error("node discovery failed", "err", err)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// discover compares the tailnet devices with the registered nodes and stores what it finds. Devices carrying a
+// discovery tag are unregistered until they register, accepted nodes without a device are missing.
+func (co *Coordinator) discover(ctx context.Context) error {
+	co.discovery.mu.Lock()
+	defer co.discovery.mu.Unlock()
+
+	devices, err := co.GetDevices(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to list tailnet devices: %w", err)
+	}
+	registered := make(map[string]*queries.RegisteredHostsData)
+	for host := range queries.GetRegisteredHosts(co.DB) {
+		registered[host.Hostname] = host
+	}
+
+	rows := make([]*queries.DiscoveredDeviceRow, 0)
+	present := make(map[string]struct{}, len(devices))
+	unregistered := 0
+	for _, device := range devices {
+		names := deviceNames(device)
+		for _, name := range names {
+			present[name] = struct{}{}
+		}
+		if !co.discoveryTagged(device) || slices.Contains(names, co.Hostname) {
+			continue
+		}
+		row := discoveredDevice(device)
+		row.State = queries.DeviceUnregistered
+		for _, name := range names {
+			if _, ok := registered[name]; ok {
+				row.Hostname = name
+				row.State = queries.DeviceRegistered
+				break
+			}
+		}
+		if row.State == queries.DeviceUnregistered {
+			unregistered++
+		}
+		rows = append(rows, row)
+	}
+
+	missing := 0
+	for hostname, host := range registered {
+		if _, ok := present[hostname]; ok || host.Status != queries.NodeAccepted {
+			continue
+		}
+		rows = append(rows, &queries.DiscoveredDeviceRow{Hostname: hostname, State: queries.DeviceMissing})
+		missing++
+	}
+
+	checked := time.Now().UTC()
+	if err := queries.SetDiscoveredDevices(co.DB, rows, checked); err != nil {
+		return fmt.Errorf("unable to store discovered devices: %w", err)
+	}
+	co.discovery.checked = checked
+	if unregistered > 0 || missing > 0 {
+		co.Logger().Info("discovery found nodes that need attention", "unregistered", unregistered, "missing", missing)
+	}
+	return nil
+}
+
+// discoveryTagged is true when the device carries any of the discovery tags
+func (co *Coordinator) discoveryTagged(device tailscale.Device) bool {
+	for _, tag := range device.Tags {
+		if slices.Contains(co.discovery.tags, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// deviceNames are the names a node on the device could have registered with, its hostname and the first label of
+// its MagicDNS name which differ when tailscale had to make the name unique
+func deviceNames(device tailscale.Device) []string {
+	names := make([]string, 0, 2)
+	if device.Hostname != "" {
+		names = append(names, device.Hostname)
+	}
+	if name, _, _ := strings.Cut(device.Name, "."); name != "" && name != device.Hostname {
+		names = append(names, name)
+	}
+	return names
+}
+
+func discoveredDevice(device tailscale.Device) *queries.DiscoveredDeviceRow {
+	row := &queries.DiscoveredDeviceRow{
+		Hostname:      device.Hostname,
+		DeviceID:      device.ID,
+		Name:          device.Name,
+		Addresses:     device.Addresses,
+		Tags:          device.Tags,
+		OS:            device.OS,
+		ClientVersion: device.ClientVersion,
+	}
+	if !device.LastSeen.IsZero() {
+		row.LastSeen = sql.NullTime{Time: device.LastSeen.UTC(), Valid: true}
+	}
+	return row
+}
+
+// DiscoverNodes returns what the last discovery found, listing the tailnet first when refresh is set
+func (c *CommanderServer) DiscoverNodes(ctx context.Context, in *pb.DiscoveryQuery) (*pb.DiscoveryList, error) {
+	if in.Refresh {
+		if !c.CO.CanListDevices() {
+			return nil, status.Error(codes.FailedPrecondition, "node discovery is disabled, the coordination server has no tailscale api client")
+		}
+		if err := c.CO.discover(ctx); err != nil {
+			return nil, status.Error(codes.Unavailable, err.Error())
+		}
+	}
+
+	rows, err := queries.GetDiscoveredDevices(c.DB)
+	if err != nil {
+		return nil, err
+	}
+	res := &pb.DiscoveryList{}
+	c.CO.discovery.mu.Lock()
+	checked := c.CO.discovery.checked
+	c.CO.discovery.mu.Unlock()
+	for _, row := range rows {
+		if checked.IsZero() || row.Checked.After(checked) {
+			checked = row.Checked
+		}
+		node := &pb.DiscoveredNode{
+			Hostname:      row.Hostname,
+			DeviceId:      row.DeviceID,
+			Name:          row.Name,
+			Addresses:     row.Addresses,
+			Tags:          row.Tags,
+			Os:            row.OS,
+			ClientVersion: row.ClientVersion,
+			State:         discoveryState(row.State),
+			FirstSeen:     timestamppb.New(row.FirstSeen),
+		}
+		if row.LastSeen.Valid {
+			node.LastSeen = timestamppb.New(row.LastSeen.Time)
+		}
+		res.Nodes = append(res.Nodes, node)
+	}
+	if !checked.IsZero() {
+		res.Checked = timestamppb.New(checked)
+	}
+	return res, nil
+}
+
+func discoveryState(state string) pb.DiscoveryState {
+	switch state {
+	case queries.DeviceUnregistered:
+		return pb.DiscoveryState_DISCOVERY_UNREGISTERED
+	case queries.DeviceRegistered:
+		return pb.DiscoveryState_DISCOVERY_REGISTERED
+	case queries.DeviceMissing:
+		return pb.DiscoveryState_DISCOVERY_MISSING
+	}
+	return pb.DiscoveryState_DISCOVERY_STATE_UNSPECIFIED
+}
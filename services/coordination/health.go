@@ -0,0 +1,280 @@
+package coordination
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"time"
+
+	pb "github.com/charles-d-burton/tailsys/commands"
+	"github.com/charles-d-burton/tailsys/data/queries"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// pingHistoryRetention is how long pings are kept for the latency history
+const pingHistoryRetention = 24 * time.Hour
+
+// HealthThresholds decide how often nodes are pinged and when they count as degraded or offline
+type HealthThresholds struct {
+	// Interval is how often a healthy node is pinged
+	Interval time.Duration
+	// Timeout is how long a ping may take before it counts as failed
+	Timeout time.Duration
+	// DegradedLatency is the round trip time at which an answering node is degraded
+	DegradedLatency time.Duration
+	// OfflineFailures is how many pings in a row have to fail for a node to be offline, fewer make it degraded
+	OfflineFailures int
+	// MaxBackoff is the longest an offline node goes without being pinged, the wait doubles with every failure
+	MaxBackoff time.Duration
+}
+
+// DefaultHealthThresholds are used for every threshold that isn't set
+var DefaultHealthThresholds = HealthThresholds{
+	Interval:        15 * time.Second,
+	Timeout:         2 * time.Second,
+	DegradedLatency: 500 * time.Millisecond,
+	OfflineFailures: 3,
+	MaxBackoff:      10 * time.Minute,
+}
+
+// WithHealthThresholds sets the thresholds for node health, zero values keep the defaults
+func (co *Coordinator) WithHealthThresholds(t HealthThresholds) Option {
+	return func(co *Coordinator) error {
+		if t.Interval < 0 || t.Timeout < 0 || t.DegradedLatency < 0 || t.OfflineFailures < 0 || t.MaxBackoff < 0 {
+			return errors.New("health thresholds can't be negative")
+		}
+		co.health = t
+		return nil
+	}
+}
+
+// healthThresholds fills in the defaults for the thresholds that weren't set
+func (co *Coordinator) healthThresholds() HealthThresholds {
+	t := co.health
+	d := DefaultHealthThresholds
+	if t.Interval == 0 {
+		t.Interval = d.Interval
+	}
+	if t.Timeout == 0 {
+		t.Timeout = d.Timeout
+	}
+	if t.DegradedLatency == 0 {
+		t.DegradedLatency = d.DegradedLatency
+	}
+	if t.OfflineFailures == 0 {
+		t.OfflineFailures = d.OfflineFailures
+	}
+	if t.MaxBackoff == 0 {
+		t.MaxBackoff = d.MaxBackoff
+	}
+	return t
+}
+
+// pingNodes pings every accepted node that is due each interval, at most limit at a time. A round finishes before
+// the next one starts so a slow round delays the next instead of piling up.
+func (co *Coordinator) pingNodes(ctx context.Context, limit uint16) {
+	if limit < 1 {
+		limit = 50
+	}
+	t := co.healthThresholds()
+	sem := make(chan struct{}, limit)
+
+	co.Logger().Debug("starting ping ticker", "interval", t.Interval)
+	ticker := time.NewTicker(t.Interval)
+	defer ticker.Stop()
+	for {
+		var now time.Time
+		select {
+		case <-ctx.Done():
+			return
+		case tick := <-ticker.C:
+			now = tick.UTC()
+		}
+
+		health, err := queries.GetAllNodeHealth(co.DB)
+		if err != nil {
+			co.Logger().Error("unable to load node health", "err", err)
+			continue
+		}
+		var wg sync.WaitGroup
+		for host := range queries.GetRegisteredHosts(co.DB) {
+			if host.Status != queries.NodeAccepted {
+				continue
+			}
+			//ticks jitter, a node is due when its next ping is closer to this tick than to the next one
+			prev := health[host.Hostname]
+			if prev != nil && prev.NextProbe.After(now.Add(t.Interval/2)) {
+				continue
+			}
+			sem <- struct{}{}
+			wg.Add(1)
+			go func(host *queries.RegisteredHostsData) {
+				defer wg.Done()
+				defer func() { <-sem }()
+				co.ping(ctx, t, now, host, prev)
+			}(host)
+		}
+		wg.Wait()
+
+		if err := queries.DeletePingsBefore(co.DB, now.Add(-pingHistoryRetention)); err != nil {
+			co.Logger().Error("unable to prune ping history", "err", err)
+		}
+	}
+}
+
+// ping pings the node once and records its health, round is when the round of pings it is part of started
+func (co *Coordinator) ping(ctx context.Context, t HealthThresholds, round time.Time, host *queries.RegisteredHostsData, prev *queries.NodeHealthRow) {
+	ctxTo, cancel := context.WithTimeout(ctx, t.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := func() error {
+		conn, err := co.dialNode(ctxTo, host)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+		_, err = pb.NewPingerClient(conn).Ping(ctxTo, &pb.PingRequest{Ping: timestamppb.Now()})
+		return err
+	}()
+	ping := &queries.PingRow{
+		Hostname:   host.Hostname,
+		Pinged:     start.UTC(),
+		Successful: err == nil,
+	}
+	if err != nil {
+		ping.Error = err.Error()
+		pingsFailed.With(host.Hostname).Inc()
+	} else {
+		rtt := time.Since(start)
+		ping.LatencyMs = float64(rtt.Microseconds()) / 1000
+		pingDuration.With(host.Hostname).Observe(rtt.Seconds())
+	}
+
+	next := nextHealth(t, round, prev, ping)
+	if prev == nil || prev.Status != next.Status {
+		co.Logger().Info("node health changed", "host", host.Hostname, "health", next.Status, "failures", next.Failures)
+	}
+	if err := queries.SetNodeHealth(co.DB, next, ping); err != nil {
+		co.Logger().Error("unable to store node health", "host", host.Hostname, "err", err)
+	}
+}
+
+// nextHealth works out the health of a node after the ping. A node that answers is online, or degraded when it is
+// slow. A node that doesn't is degraded until it missed OfflineFailures pings in a row, then offline and pinged
+// less and less often until it answers again. The next ping is counted from round so it lines up with the ticker.
+func nextHealth(t HealthThresholds, round time.Time, prev *queries.NodeHealthRow, ping *queries.PingRow) *queries.NodeHealthRow {
+	now := ping.Pinged
+	next := &queries.NodeHealthRow{
+		Hostname:    ping.Hostname,
+		Status:      queries.HealthUnknown,
+		LastChecked: now,
+		NextProbe:   round.Add(t.Interval),
+	}
+	if prev != nil {
+		next.LastSeen = prev.LastSeen
+		next.LatencyMs = prev.LatencyMs
+		next.Failures = prev.Failures
+	}
+
+	if ping.Successful {
+		next.Failures = 0
+		next.LatencyMs = ping.LatencyMs
+		next.LastSeen = sql.NullTime{Time: now, Valid: true}
+		next.Status = queries.HealthOnline
+		if time.Duration(ping.LatencyMs*float64(time.Millisecond)) >= t.DegradedLatency {
+			next.Status = queries.HealthDegraded
+		}
+	} else {
+		next.Failures++
+		next.Error = ping.Error
+		switch {
+		case next.Failures >= t.OfflineFailures:
+			next.Status = queries.HealthOffline
+			next.NextProbe = round.Add(backoff(t, next.Failures-t.OfflineFailures+1))
+		case next.LastSeen.Valid:
+			next.Status = queries.HealthDegraded
+		}
+	}
+
+	next.Since = now
+	if prev != nil && prev.Status == next.Status {
+		next.Since = prev.Since
+	}
+	return next
+}
+
+// backoff doubles the ping interval for each ping an offline node missed, up to MaxBackoff
+func backoff(t HealthThresholds, missed int) time.Duration {
+	wait := t.Interval
+	for i := 0; i < missed && wait < t.MaxBackoff; i++ {
+		wait *= 2
+	}
+	return min(wait, t.MaxBackoff)
+}
+
+// GetNodeStatus returns the health of the accepted nodes matching the pattern along with their latest pings
+func (c *CommanderServer) GetNodeStatus(ctx context.Context, in *pb.NodeStatusQuery) (*pb.NodeStatusList, error) {
+	hosts, err := c.CO.matchNodes(ctx, in.Pattern)
+	if err != nil {
+		return nil, err
+	}
+	health, err := queries.GetAllNodeHealth(c.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &pb.NodeStatusList{}
+	for _, host := range hosts {
+		status := &pb.NodeStatus{Hostname: host.Hostname, Health: pb.NodeHealth_NODE_UNKNOWN}
+		if row, ok := health[host.Hostname]; ok {
+			status = nodeStatus(row)
+		}
+		if in.History > 0 {
+			pings, err := queries.GetPings(c.DB, host.Hostname, int(in.History))
+			if err != nil {
+				return nil, err
+			}
+			for _, p := range pings {
+				status.History = append(status.History, &pb.PingResult{
+					Pinged:     timestamppb.New(p.Pinged),
+					Successful: p.Successful,
+					LatencyMs:  p.LatencyMs,
+					Error:      p.Error,
+				})
+			}
+		}
+		res.Nodes = append(res.Nodes, status)
+	}
+	return res, nil
+}
+
+func nodeStatus(row *queries.NodeHealthRow) *pb.NodeStatus {
+	status := &pb.NodeStatus{
+		Hostname:    row.Hostname,
+		Health:      nodeHealth(row.Status),
+		Since:       timestamppb.New(row.Since),
+		LatencyMs:   row.LatencyMs,
+		LastChecked: timestamppb.New(row.LastChecked),
+		NextProbe:   timestamppb.New(row.NextProbe),
+		Failures:    int32(row.Failures),
+		Error:       row.Error,
+	}
+	if row.LastSeen.Valid {
+		status.LastSeen = timestamppb.New(row.LastSeen.Time)
+	}
+	return status
+}
+
+func nodeHealth(status string) pb.NodeHealth {
+	switch status {
+	case queries.HealthOnline:
+		return pb.NodeHealth_NODE_ONLINE
+	case queries.HealthDegraded:
+		return pb.NodeHealth_NODE_DEGRADED
+	case queries.HealthOffline:
+		return pb.NodeHealth_NODE_OFFLINE
+	}
+	return pb.NodeHealth_NODE_UNKNOWN
+}
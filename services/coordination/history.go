@@ -0,0 +1,304 @@
+package coordination
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	pb "github.com/charles-d-burton/tailsys/commands"
+	"github.com/charles-d-burton/tailsys/connections"
+	"github.com/charles-d-burton/tailsys/data/queries"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// maxRecordedOutput caps how much of each stream is kept per host so a chatty command can't bloat the database
+const maxRecordedOutput = 1 << 20
+
+// createJob records the request as a new job and returns the job id
+func (c *CommanderServer) createJob(ctx context.Context, cmd *pb.CommanderRequest) (string, error) {
+	data, err := proto.Marshal(redactRequest(cmd))
+	if err != nil {
+		return "", err
+	}
+	job := &queries.CommandJobRow{
+		JobID:     uuid.NewString(),
+		Requester: requester(ctx),
+		Pattern:   cmd.Pattern,
+		Command:   commandString(cmd),
+		Request:   data,
+		Created:   time.Now().UTC(),
+	}
+	if err := queries.InsertJob(c.DB, job); err != nil {
+		return "", fmt.Errorf("unable to record job: %w", err)
+	}
+	c.CO.Logger().Info("created job", "job", job.JobID, "requester", job.Requester, "pattern", job.Pattern)
+	return job.JobID, nil
+}
+
+// redactRequest returns a copy of the request that is safe to keep in the job history. Environment variables and
+// stdin often carry secrets, only the names of the variables are kept.
+func redactRequest(cmd *pb.CommanderRequest) *pb.CommanderRequest {
+	redacted := proto.Clone(cmd).(*pb.CommanderRequest)
+	for name := range redacted.Env {
+		redacted.Env[name] = ""
+	}
+	redacted.Stdin = nil
+	return redacted
+}
+
+// requester identifies who made the request, by their tailscale identity when it is known
+func requester(ctx context.Context) string {
+	if id := connections.IdentityFromContext(ctx); id != nil {
+		return id.String()
+	}
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return "unknown"
+	}
+	return p.Addr.String()
+}
+
+// commandString is how the command is displayed in the job history
+func commandString(cmd *pb.CommanderRequest) string {
+	if len(cmd.Argv) > 0 {
+		return strings.Join(cmd.Argv, " ")
+	}
+	return cmd.Command
+}
+
+// hostRecord collects the output of a job on a single host until it can be written to the database
+type hostRecord struct {
+	mu     sync.Mutex
+	db     *sql.DB
+	log    *slog.Logger
+	id     int64
+	stdout []byte
+	stderr []byte
+}
+
+// newRecord records the host as pending for the job
+func (c *CommanderServer) newRecord(jobID, hostname string) *hostRecord {
+	rec := &hostRecord{db: c.DB, log: c.CO.Logger().With("job", jobID, "host", hostname)}
+	id, err := queries.InsertCommandRecord(c.DB, jobID, hostname)
+	if err != nil {
+		rec.log.Error("unable to record host for job", "err", err)
+		return rec
+	}
+	rec.id = id
+	return rec
+}
+
+// start records that the job has been dispatched to the host
+func (hr *hostRecord) start() {
+	if hr.id == 0 {
+		return
+	}
+	if err := queries.StartCommandRecord(hr.db, hr.id, time.Now().UTC()); err != nil {
+		hr.log.Error("unable to record command start", "record", hr.id, "err", err)
+	}
+}
+
+func (hr *hostRecord) output(out *pb.CommandOutput) {
+	hr.mu.Lock()
+	defer hr.mu.Unlock()
+	if out.Stream == pb.OutputStream_STDERR {
+		hr.stderr = appendCapped(hr.stderr, out.Data)
+		return
+	}
+	hr.stdout = appendCapped(hr.stdout, out.Data)
+}
+
+// finish writes the result and collected output of the host to the database
+func (hr *hostRecord) finish(res *pb.CommandResponse, hs pb.HostStatus) {
+	hr.mu.Lock()
+	defer hr.mu.Unlock()
+	if hr.id == 0 {
+		return
+	}
+	//unary results come back with their output attached rather than streamed
+	stdout := appendCapped(hr.stdout, res.Stdout)
+	stderr := appendCapped(hr.stderr, res.Stderr)
+	err := queries.FinishCommandRecord(hr.db, &queries.CommandRecordRow{
+		ID:       hr.id,
+		Status:   hostStatusName(hs),
+		Success:  sql.NullBool{Bool: res.Successful, Valid: true},
+		ExitCode: sql.NullInt32{Int32: res.ExitCode, Valid: true},
+		Stdout:   stdout,
+		Stderr:   stderr,
+		Error:    sql.NullString{String: res.Error, Valid: res.Error != ""},
+		Signal:   res.Signal,
+		Reason:   reasonName(res.Reason),
+		Duration: sql.NullInt64{Int64: res.Duration.AsDuration().Milliseconds(), Valid: res.Duration != nil},
+		Finished: sql.NullTime{Time: time.Now().UTC(), Valid: true},
+	})
+	if err != nil {
+		hr.log.Error("unable to record command result", "record", hr.id, "err", err)
+	}
+}
+
+// reasonName is how the reason is recorded, nothing is recorded when the node didn't say
+func reasonName(r pb.TerminationReason) string {
+	if r == pb.TerminationReason_TERMINATION_REASON_UNSPECIFIED {
+		return ""
+	}
+	return r.String()
+}
+
+func appendCapped(buf, data []byte) []byte {
+	if room := maxRecordedOutput - len(buf); room < len(data) {
+		if room <= 0 {
+			return buf
+		}
+		data = data[:room]
+	}
+	return append(buf, data...)
+}
+
+// ListJobs returns the most recent jobs the caller may read
+func (c *CommanderServer) ListJobs(ctx context.Context, in *pb.JobQuery) (*pb.JobList, error) {
+	limit := int(in.Limit)
+	if limit < 1 {
+		limit = 25
+	}
+	readable, err := c.CO.readableBy(ctx, "/tailsys.CommandManager/ListJobs")
+	if err != nil {
+		return nil, err
+	}
+	jobs, err := queries.GetJobs(c.DB, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &pb.JobList{}
+	for _, job := range jobs {
+		hosts, err := queries.GetJobHosts(c.DB, job.JobID)
+		if err != nil {
+			return nil, err
+		}
+		if readable(job.Requester, hosts) {
+			res.Jobs = append(res.Jobs, jobSummary(job))
+		}
+	}
+	return res, nil
+}
+
+// GetJob returns a job with the progress or result of every host it was sent to
+func (c *CommanderServer) GetJob(ctx context.Context, in *pb.JobID) (*pb.Job, error) {
+	return c.loadJob(in.JobId)
+}
+
+func (c *CommanderServer) loadJob(jobID string) (*pb.Job, error) {
+	job, err := queries.GetJob(c.DB, jobID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, status.Errorf(codes.NotFound, "job %s not found", jobID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	records, err := queries.GetCommandRecords(c.DB, jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &pb.Job{Summary: jobSummary(job)}
+	for _, r := range records {
+		job.Hosts++
+		if r.Status == queries.HostFailed {
+			job.Failed++
+		}
+		rec := &pb.CommandRecord{
+			Hostname:   r.Hostname,
+			Status:     hostStatus(r.Status),
+			Successful: r.Success.Bool,
+			ExitCode:   r.ExitCode.Int32,
+			Stdout:     r.Stdout,
+			Stderr:     r.Stderr,
+			Error:      r.Error.String,
+			Signal:     r.Signal,
+			Reason:     pb.TerminationReason(pb.TerminationReason_value[r.Reason]),
+		}
+		if r.Duration.Valid {
+			rec.Duration = durationpb.New(time.Duration(r.Duration.Int64) * time.Millisecond)
+		}
+		if r.Started.Valid {
+			rec.Started = timestamppb.New(r.Started.Time)
+		}
+		if r.Finished.Valid {
+			rec.Finished = timestamppb.New(r.Finished.Time)
+		}
+		res.Records = append(res.Records, rec)
+	}
+	res.Summary.Hosts = int32(job.Hosts)
+	res.Summary.Failed = int32(job.Failed)
+	return res, nil
+}
+
+func jobSummary(job *queries.CommandJobRow) *pb.JobSummary {
+	s := &pb.JobSummary{
+		JobId:     job.JobID,
+		Requester: job.Requester,
+		Pattern:   job.Pattern,
+		Command:   job.Command,
+		Created:   timestamppb.New(job.Created),
+		Hosts:     int32(job.Hosts),
+		Failed:    int32(job.Failed),
+		Status:    jobStatus(job.Status),
+	}
+	if job.Finished.Valid {
+		s.Finished = timestamppb.New(job.Finished.Time)
+	}
+	return s
+}
+
+func jobStatus(s string) pb.JobStatus {
+	switch s {
+	case queries.JobRunning:
+		return pb.JobStatus_JOB_RUNNING
+	case queries.JobFinished:
+		return pb.JobStatus_JOB_FINISHED
+	case queries.JobCanceled:
+		return pb.JobStatus_JOB_CANCELED
+	}
+	return pb.JobStatus_JOB_STATUS_UNSPECIFIED
+}
+
+func hostStatus(s string) pb.HostStatus {
+	switch s {
+	case queries.HostPending:
+		return pb.HostStatus_HOST_PENDING
+	case queries.HostRunning:
+		return pb.HostStatus_HOST_RUNNING
+	case queries.HostSucceeded:
+		return pb.HostStatus_HOST_SUCCEEDED
+	case queries.HostFailed:
+		return pb.HostStatus_HOST_FAILED
+	case queries.HostCanceled:
+		return pb.HostStatus_HOST_CANCELED
+	}
+	return pb.HostStatus_HOST_STATUS_UNSPECIFIED
+}
+
+func hostStatusName(s pb.HostStatus) string {
+	switch s {
+	case pb.HostStatus_HOST_PENDING:
+		return queries.HostPending
+	case pb.HostStatus_HOST_RUNNING:
+		return queries.HostRunning
+	case pb.HostStatus_HOST_SUCCEEDED:
+		return queries.HostSucceeded
+	case pb.HostStatus_HOST_CANCELED:
+		return queries.HostCanceled
+	}
+	return queries.HostFailed
+}
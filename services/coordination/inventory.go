@@ -0,0 +1,165 @@
+package coordination
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	pb "github.com/charles-d-burton/tailsys/commands"
+	"github.com/charles-d-burton/tailsys/data/queries"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// inventoryRefreshLimit is how many nodes are asked for their inventory at the same time
+const inventoryRefreshLimit = 10
+
+// GetInventory returns the inventory of the accepted nodes matching the pattern, asking them for it first when refresh is set
+func (c *CommanderServer) GetInventory(ctx context.Context, in *pb.InventoryQuery) (*pb.InventoryList, error) {
+	hosts, err := c.CO.matchNodes(ctx, in.Pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &pb.InventoryList{Errors: make(map[string]string)}
+	if in.Refresh {
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+		sem := make(chan struct{}, inventoryRefreshLimit)
+		for _, host := range hosts {
+			wg.Add(1)
+			sem <- struct{}{}
+			go func(host *queries.RegisteredHostsData) {
+				defer wg.Done()
+				defer func() { <-sem }()
+				if err := c.refreshInventory(ctx, host); err != nil {
+					c.CO.Logger().Warn("unable to refresh inventory", "host", host.Hostname, "err", err)
+					mu.Lock()
+					res.Errors[host.Hostname] = err.Error()
+					mu.Unlock()
+				}
+			}(host)
+		}
+		wg.Wait()
+	}
+
+	for _, host := range hosts {
+		info := &pb.SysInfo{Hostname: host.Hostname}
+		row, err := queries.GetInventory(c.DB, host.Hostname)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+		case err != nil:
+			return nil, fmt.Errorf("unable to load inventory of %s: %w", host.Hostname, err)
+		default:
+			info = inventoryInfo(row)
+		}
+		res.Nodes = append(res.Nodes, info)
+	}
+	return res, nil
+}
+
+// refreshInventory asks the node for its inventory and stores it
+func (c *CommanderServer) refreshInventory(ctx context.Context, host *queries.RegisteredHostsData) error {
+	ctxTo, cancel := context.WithTimeout(ctx, time.Second*10)
+	defer cancel()
+	conn, err := c.CO.dialNode(ctxTo, host)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	info, err := pb.NewSystemInfoClient(conn).SysInfo(ctxTo, &pb.SysInfoRequest{})
+	if err != nil {
+		return fmt.Errorf("unable to get inventory of %s: %w", host.Hostname, err)
+	}
+	if info.GetInventory() == nil {
+		return fmt.Errorf("%s did not send an inventory", host.Hostname)
+	}
+	return storeInventory(c.DB, host.Hostname, info.Ip, info.Inventory)
+}
+
+func storeInventory(db *sql.DB, hostname, ip string, inv *pb.Inventory) error {
+	row := &queries.InventoryRow{
+		Hostname:        hostname,
+		OS:              inv.Os,
+		Distro:          inv.Distro,
+		DistroVersion:   inv.DistroVersion,
+		DistroName:      inv.DistroName,
+		Kernel:          inv.Kernel,
+		Arch:            inv.Arch,
+		CPUCount:        inv.CpuCount,
+		CPUModel:        inv.CpuModel,
+		MemoryTotal:     inv.MemoryTotalBytes,
+		MemoryAvailable: inv.MemoryAvailableBytes,
+		UptimeSeconds:   inv.UptimeSeconds,
+		TailscaleIPs:    inv.TailscaleIps,
+		TailscaleTags:   inv.TailscaleTags,
+		TailsysVersion:  inv.TailsysVersion,
+		IP:              ip,
+		Collected:       inv.GetCollected().AsTime().UTC(),
+	}
+	for _, d := range inv.Disks {
+		row.Disks = append(row.Disks, &queries.DiskRow{
+			Device:     d.Device,
+			Mountpoint: d.Mountpoint,
+			Fstype:     d.Fstype,
+			TotalBytes: d.TotalBytes,
+			FreeBytes:  d.FreeBytes,
+		})
+	}
+	for _, i := range inv.Interfaces {
+		row.Interfaces = append(row.Interfaces, &queries.InterfaceRow{
+			Name:      i.Name,
+			MAC:       i.Mac,
+			Addresses: i.Addresses,
+			MTU:       i.Mtu,
+			Up:        i.Up,
+		})
+	}
+	return queries.SetInventory(db, row)
+}
+
+func inventoryInfo(row *queries.InventoryRow) *pb.SysInfo {
+	inv := &pb.Inventory{
+		Os:                   row.OS,
+		Distro:               row.Distro,
+		DistroVersion:        row.DistroVersion,
+		DistroName:           row.DistroName,
+		Kernel:               row.Kernel,
+		Arch:                 row.Arch,
+		CpuCount:             row.CPUCount,
+		CpuModel:             row.CPUModel,
+		MemoryTotalBytes:     row.MemoryTotal,
+		MemoryAvailableBytes: row.MemoryAvailable,
+		UptimeSeconds:        row.UptimeSeconds,
+		TailscaleIps:         row.TailscaleIPs,
+		TailscaleTags:        row.TailscaleTags,
+		TailsysVersion:       row.TailsysVersion,
+		Collected:            timestamppb.New(row.Collected),
+	}
+	for _, d := range row.Disks {
+		inv.Disks = append(inv.Disks, &pb.Disk{
+			Device:     d.Device,
+			Mountpoint: d.Mountpoint,
+			Fstype:     d.Fstype,
+			TotalBytes: d.TotalBytes,
+			FreeBytes:  d.FreeBytes,
+		})
+	}
+	for _, i := range row.Interfaces {
+		inv.Interfaces = append(inv.Interfaces, &pb.NetworkInterface{
+			Name:      i.Name,
+			Mac:       i.MAC,
+			Addresses: i.Addresses,
+			Mtu:       i.MTU,
+			Up:        i.Up,
+		})
+	}
+	return &pb.SysInfo{
+		Hostname:  row.Hostname,
+		Ip:        row.IP,
+		Inventory: inv,
+	}
+}
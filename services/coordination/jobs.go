@@ -0,0 +1,364 @@
+package coordination
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	pb "github.com/charles-d-burton/tailsys/commands"
+	"github.com/charles-d-burton/tailsys/data/queries"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// jobManager tracks the jobs that are still being dispatched so they can be watched and canceled
+type jobManager struct {
+	mu      sync.Mutex
+	jobs    map[string]*runningJob
+	running sync.WaitGroup
+	closed  bool
+}
+
+func newJobManager() *jobManager {
+	return &jobManager{
+		jobs: make(map[string]*runningJob),
+	}
+}
+
+func (jm *jobManager) add(job *runningJob) error {
+	jm.mu.Lock()
+	defer jm.mu.Unlock()
+	if jm.closed {
+		return status.Error(codes.Unavailable, "coordination server is shutting down")
+	}
+	jm.running.Add(1)
+	jm.jobs[job.id] = job
+	return nil
+}
+
+func (jm *jobManager) get(id string) *runningJob {
+	jm.mu.Lock()
+	defer jm.mu.Unlock()
+	return jm.jobs[id]
+}
+
+func (jm *jobManager) remove(id string) {
+	jm.mu.Lock()
+	defer jm.mu.Unlock()
+	delete(jm.jobs, id)
+	jm.running.Done()
+}
+
+// drain refuses new jobs and waits for the running ones to finish, the ones still running after timeout are canceled
+func (jm *jobManager) drain(timeout time.Duration) {
+	jm.mu.Lock()
+	jm.closed = true
+	jm.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		jm.running.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return
+	case <-time.After(timeout):
+	}
+	jm.mu.Lock()
+	for _, job := range jm.jobs {
+		job.log.Warn("canceling job still running at shutdown")
+		job.cancel()
+	}
+	jm.mu.Unlock()
+	<-done
+}
+
+// runningJob is a job being sent to its hosts. Everything that happens on the hosts is published
+// as events to whoever is watching.
+type runningJob struct {
+	id       string
+	cmd      *pb.CommanderRequest
+	hosts    []*queries.RegisteredHostsData
+	records  map[string]*hostRecord
+	log      *slog.Logger
+	ctx      context.Context
+	cancel   context.CancelFunc
+	done     chan struct{}
+	mu       sync.Mutex
+	watchers map[*jobWatcher]struct{}
+}
+
+// jobWatcher receives the events of a job until the job finishes or the watcher goes away. A detached watcher, one
+// watching a job started by another request, that falls a full buffer behind is dropped instead of holding up the
+// job. The watcher of the request that started the job holds the job up instead, none of its output can be lost.
+type jobWatcher struct {
+	events   chan *pb.JobEvent
+	done     <-chan struct{}
+	detached bool
+	//lagged is set before events is closed when the watcher was dropped for falling behind
+	lagged bool
+}
+
+// err is why the events channel was closed early, nil when the job finished
+func (w *jobWatcher) err() error {
+	if w.lagged {
+		return status.Error(codes.ResourceExhausted, "fell too far behind the job and was dropped, use job get for its result")
+	}
+	return nil
+}
+
+// watch subscribes to the events of the job, the events channel is closed once the job is finished
+func (j *runningJob) watch(done <-chan struct{}, detached bool) *jobWatcher {
+	w := &jobWatcher{
+		events:   make(chan *pb.JobEvent, 100),
+		done:     done,
+		detached: detached,
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	select {
+	case <-j.done:
+		//the job finished before we got here, there is nothing left to send
+		close(w.events)
+	default:
+		j.watchers[w] = struct{}{}
+	}
+	return w
+}
+
+// publish hands the event to every watcher. It only waits on the watcher of the request that started the job, which
+// slows the job down to the pace of that request rather than losing its output.
+func (j *runningJob) publish(ev *pb.JobEvent) {
+	ev.JobId = j.id
+	ev.Timestamp = timestamppb.Now()
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	for w := range j.watchers {
+		select {
+		case <-w.done:
+			delete(j.watchers, w)
+			close(w.events)
+			continue
+		default:
+		}
+		if !w.detached {
+			select {
+			case w.events <- ev:
+			case <-w.done:
+				delete(j.watchers, w)
+				close(w.events)
+			}
+			continue
+		}
+		select {
+		case w.events <- ev:
+		default:
+			j.log.Warn("dropping job watcher that fell behind")
+			w.lagged = true
+			delete(j.watchers, w)
+			close(w.events)
+		}
+	}
+}
+
+func (j *runningJob) hostStatus(hostname string, hs pb.HostStatus) {
+	j.publish(&pb.JobEvent{
+		Hostname: hostname,
+		Event:    &pb.JobEvent_HostStatus{HostStatus: hs},
+	})
+}
+
+// finish closes out every watcher, no events can be published after this
+func (j *runningJob) finish() {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	for w := range j.watchers {
+		close(w.events)
+	}
+	j.watchers = map[*jobWatcher]struct{}{}
+	close(j.done)
+}
+
+// startJob looks up the hosts for the request and records them as pending on a new job. The job lives until parent
+// is canceled, it won't be sent to any host until runJob is called.
+func (c *CommanderServer) startJob(ctx, parent context.Context, cmd *pb.CommanderRequest) (*runningJob, error) {
+	hosts, err := c.CO.matchNodes(ctx, cmd.Pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	jobID, err := c.createJob(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	job := &runningJob{
+		id:       jobID,
+		cmd:      cmd,
+		hosts:    hosts,
+		records:  make(map[string]*hostRecord, len(hosts)),
+		log:      c.CO.Logger().With("job", jobID),
+		done:     make(chan struct{}),
+		watchers: make(map[*jobWatcher]struct{}),
+	}
+	for _, host := range hosts {
+		job.records[host.Hostname] = c.newRecord(jobID, host.Hostname)
+	}
+	job.ctx, job.cancel = context.WithCancel(parent)
+	if err := c.jobs.add(job); err != nil {
+		job.cancel()
+		queries.FinishJob(c.DB, jobID, queries.JobCanceled, time.Now().UTC())
+		return nil, err
+	}
+	return job, nil
+}
+
+// runJob sends the job to all of its hosts and records the outcome once every host is finished
+func (c *CommanderServer) runJob(job *runningJob) {
+	defer job.cancel()
+	defer c.jobs.remove(job.id)
+
+	c.streamCommand(job, 50)
+
+	js := pb.JobStatus_JOB_FINISHED
+	if job.ctx.Err() != nil {
+		js = pb.JobStatus_JOB_CANCELED
+	}
+	name := queries.JobFinished
+	if js == pb.JobStatus_JOB_CANCELED {
+		name = queries.JobCanceled
+	}
+	if err := queries.FinishJob(c.DB, job.id, name, time.Now().UTC()); err != nil {
+		job.log.Error("unable to record job status", "err", err)
+	}
+	job.publish(&pb.JobEvent{
+		Event: &pb.JobEvent_JobStatus{JobStatus: js},
+	})
+	job.finish()
+	job.log.Info("job finished", "status", js.String())
+}
+
+// SubmitJob starts sending the command to the matching nodes in the background and returns the job id right away
+func (c *CommanderServer) SubmitJob(ctx context.Context, cmd *pb.CommanderRequest) (*pb.JobID, error) {
+	job, err := c.startJob(ctx, context.Background(), cmd)
+	if err != nil {
+		return nil, err
+	}
+	go c.runJob(job)
+	return &pb.JobID{JobId: job.id}, nil
+}
+
+// WatchJob streams the current state of every host on the job followed by everything that happens until the job finishes
+func (c *CommanderServer) WatchJob(in *pb.JobID, stream pb.CommandManager_WatchJobServer) error {
+	job := c.jobs.get(in.JobId)
+	if job == nil {
+		return c.replayJob(in.JobId, stream)
+	}
+
+	//subscribe before taking the snapshot so nothing is missed in between
+	w := job.watch(stream.Context().Done(), true)
+	snapshot, err := c.loadJob(in.JobId)
+	if err != nil {
+		return err
+	}
+	for _, rec := range snapshot.Records {
+		if err := stream.Send(&pb.JobEvent{
+			JobId:     in.JobId,
+			Hostname:  rec.Hostname,
+			Timestamp: timestamppb.Now(),
+			Event:     &pb.JobEvent_HostStatus{HostStatus: rec.Status},
+		}); err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case ev, ok := <-w.events:
+			if !ok {
+				return w.err()
+			}
+			if err := stream.Send(ev); err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		}
+	}
+}
+
+// replayJob sends the recorded result of a job that is no longer running as if it was being watched
+func (c *CommanderServer) replayJob(jobID string, stream pb.CommandManager_WatchJobServer) error {
+	job, err := c.loadJob(jobID)
+	if err != nil {
+		return err
+	}
+
+	send := func(hostname string, ev *pb.JobEvent) error {
+		ev.JobId = jobID
+		ev.Hostname = hostname
+		ev.Timestamp = timestamppb.Now()
+		return stream.Send(ev)
+	}
+	for _, rec := range job.Records {
+		if len(rec.Stdout) > 0 {
+			if err := send(rec.Hostname, &pb.JobEvent{Event: &pb.JobEvent_Output{
+				Output: &pb.CommandOutput{Stream: pb.OutputStream_STDOUT, Data: rec.Stdout},
+			}}); err != nil {
+				return err
+			}
+		}
+		if len(rec.Stderr) > 0 {
+			if err := send(rec.Hostname, &pb.JobEvent{Event: &pb.JobEvent_Output{
+				Output: &pb.CommandOutput{Stream: pb.OutputStream_STDERR, Data: rec.Stderr},
+			}}); err != nil {
+				return err
+			}
+		}
+		if rec.Finished != nil {
+			if err := send(rec.Hostname, &pb.JobEvent{Event: &pb.JobEvent_Exit{
+				Exit: &pb.CommandResponse{
+					Timestamp:  rec.Finished,
+					Successful: rec.Successful,
+					ExitCode:   rec.ExitCode,
+					Hostname:   rec.Hostname,
+					Error:      rec.Error,
+					Signal:     rec.Signal,
+					Reason:     rec.Reason,
+					Duration:   rec.Duration,
+				},
+			}}); err != nil {
+				return err
+			}
+		}
+		if err := send(rec.Hostname, &pb.JobEvent{Event: &pb.JobEvent_HostStatus{HostStatus: rec.Status}}); err != nil {
+			return err
+		}
+	}
+	return send("", &pb.JobEvent{Event: &pb.JobEvent_JobStatus{JobStatus: job.Summary.Status}})
+}
+
+// CancelJob stops the job from being sent to any more hosts and kills it on the hosts where it is still running
+func (c *CommanderServer) CancelJob(ctx context.Context, in *pb.JobID) (*pb.Job, error) {
+	job := c.jobs.get(in.JobId)
+	if job == nil {
+		current, err := c.loadJob(in.JobId)
+		if err != nil {
+			return nil, err
+		}
+		return nil, status.Errorf(codes.FailedPrecondition, "job %s is not running, status is %s", in.JobId, current.Summary.Status)
+	}
+
+	job.log.Info("canceling job")
+	job.cancel()
+	select {
+	case <-job.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return c.loadJob(in.JobId)
+}
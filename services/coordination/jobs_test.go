@@ -0,0 +1,150 @@
+package coordination
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	pb "github.com/charles-d-burton/tailsys/commands"
+	"github.com/charles-d-burton/tailsys/data/queries"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func newTestJob() *runningJob {
+	return &runningJob{
+		id:       "job",
+		log:      slog.New(slog.NewTextHandler(io.Discard, nil)),
+		done:     make(chan struct{}),
+		watchers: make(map[*jobWatcher]struct{}),
+	}
+}
+
+func output(i int) *pb.JobEvent {
+	return &pb.JobEvent{Hostname: "web1", Event: &pb.JobEvent_Output{
+		Output: &pb.CommandOutput{Data: []byte{byte(i)}},
+	}}
+}
+
+func TestPublishWaitsForTheRequest(t *testing.T) {
+	job := newTestJob()
+	w := job.watch(make(chan struct{}), false)
+	go func() {
+		for i := 0; i < 1000; i++ {
+			job.publish(output(i))
+		}
+		job.finish()
+	}()
+
+	received := 0
+	for ev := range w.events {
+		if got := ev.GetOutput().Data[0]; got != byte(received) {
+			t.Fatalf("event %d arrived as %d", received, got)
+		}
+		received++
+		if received%100 == 0 {
+			//a slow client falls a full buffer behind
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	if received != 1000 || w.err() != nil {
+		t.Fatalf("expected every event to reach the request, got %d %v", received, w.err())
+	}
+}
+
+func TestPublishStopsWaitingWhenTheRequestIsGone(t *testing.T) {
+	job := newTestJob()
+	done := make(chan struct{})
+	w := job.watch(done, false)
+	published := make(chan struct{})
+	go func() {
+		for i := 0; i < 200; i++ {
+			job.publish(output(i))
+		}
+		close(published)
+	}()
+	close(done)
+	select {
+	case <-published:
+	case <-time.After(5 * time.Second):
+		t.Fatal("publish is still waiting on a request that went away")
+	}
+	for range w.events {
+	}
+}
+
+func TestPublishDropsDetachedWatchers(t *testing.T) {
+	job := newTestJob()
+	w := job.watch(make(chan struct{}), true)
+	for i := 0; i < 101; i++ {
+		job.publish(output(i))
+	}
+	received := 0
+	for range w.events {
+		received++
+	}
+	if received != 100 || status.Code(w.err()) != codes.ResourceExhausted {
+		t.Fatalf("expected a lagging watcher to be dropped after 100 events, got %d %v", received, w.err())
+	}
+	job.finish()
+}
+
+// watchStream collects what is sent to a job watcher
+type watchStream struct {
+	grpc.ServerStream
+	events []*pb.JobEvent
+}
+
+func (s *watchStream) Context() context.Context { return context.Background() }
+
+func (s *watchStream) Send(ev *pb.JobEvent) error {
+	s.events = append(s.events, ev)
+	return nil
+}
+
+func TestReplayJobExit(t *testing.T) {
+	co := newTestCoordinator(t, "", "web1")
+	c := &CommanderServer{DB: co.DB, CO: co, jobs: newJobManager()}
+	jobID, err := c.createJob(context.Background(), &pb.CommanderRequest{Pattern: "web1", Command: "sleep 100"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	exit := &pb.CommandResponse{
+		Hostname: "web1",
+		ExitCode: -1,
+		Signal:   "killed",
+		Reason:   pb.TerminationReason_TIMED_OUT,
+		Duration: durationpb.New(1500 * time.Millisecond),
+		Error:    "command timed out after 1.5s",
+	}
+	rec := c.newRecord(jobID, "web1")
+	rec.start()
+	rec.finish(exit, pb.HostStatus_HOST_FAILED)
+	if err := queries.FinishJob(c.DB, jobID, queries.JobFinished, time.Now().UTC()); err != nil {
+		t.Fatal(err)
+	}
+
+	stream := &watchStream{}
+	if err := c.WatchJob(&pb.JobID{JobId: jobID}, stream); err != nil {
+		t.Fatal(err)
+	}
+	var replayed *pb.CommandResponse
+	for _, ev := range stream.events {
+		if ev.GetExit() != nil {
+			replayed = ev.GetExit()
+		}
+	}
+	if replayed == nil {
+		t.Fatal("expected the exit of the host to be replayed")
+	}
+	//the time the host finished is when it was recorded, it isn't part of what the node sent
+	replayed.Timestamp = nil
+	if !proto.Equal(replayed, exit) {
+		t.Fatalf("replayed exit %v, want %v", replayed, exit)
+	}
+}
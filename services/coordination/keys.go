@@ -0,0 +1,176 @@
+package coordination
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	pb "github.com/charles-d-burton/tailsys/commands"
+	"github.com/charles-d-burton/tailsys/data/queries"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ListKeys returns the nodes matching the glob along with whether they are allowed to receive commands
+func (c *CommanderServer) ListKeys(ctx context.Context, in *pb.KeyQuery) (*pb.KeyList, error) {
+	res := &pb.KeyList{}
+	hosts, err := c.matchKeys(in, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, host := range hosts {
+		res.Keys = append(res.Keys, nodeKey(host))
+	}
+	return res, nil
+}
+
+// AcceptKeys allows the matching pending nodes to receive commands. Rejected or revoked nodes are only accepted
+// when the query asks for that status explicitly.
+func (c *CommanderServer) AcceptKeys(ctx context.Context, in *pb.KeyQuery) (*pb.KeyList, error) {
+	return c.changeKeys(in, []string{queries.NodePending}, func(current string) string {
+		if current == queries.NodeAccepted {
+			return ""
+		}
+		return queries.NodeAccepted
+	})
+}
+
+// RejectKeys turns away the matching pending nodes and revokes the matching accepted ones along with their certificates
+func (c *CommanderServer) RejectKeys(ctx context.Context, in *pb.KeyQuery) (*pb.KeyList, error) {
+	return c.changeKeys(in, []string{queries.NodePending, queries.NodeAccepted}, func(current string) string {
+		switch current {
+		case queries.NodePending:
+			return queries.NodeRejected
+		case queries.NodeAccepted:
+			return queries.NodeRevoked
+		}
+		return ""
+	})
+}
+
+// DeleteKeys forgets the matching nodes and revokes their certificates, they show up as pending again the next time they register
+func (c *CommanderServer) DeleteKeys(ctx context.Context, in *pb.KeyQuery) (*pb.KeyList, error) {
+	res := &pb.KeyList{}
+	hosts, err := c.matchKeys(in, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, host := range hosts {
+		if err := queries.DeleteHost(c.DB, host.Hostname); err != nil {
+			return res, fmt.Errorf("unable to delete %s: %w", host.Hostname, err)
+		}
+		if err := queries.DeleteInventory(c.DB, host.Hostname); err != nil {
+			return res, fmt.Errorf("unable to delete inventory of %s: %w", host.Hostname, err)
+		}
+		if err := queries.DeleteDiscoveredDevice(c.DB, host.Hostname); err != nil {
+			return res, fmt.Errorf("unable to delete discovered device %s: %w", host.Hostname, err)
+		}
+		if err := queries.DeleteNodeHealth(c.DB, host.Hostname); err != nil {
+			return res, fmt.Errorf("unable to delete health of %s: %w", host.Hostname, err)
+		}
+		if err := c.CO.revokeNode(host.Hostname); err != nil {
+			return res, fmt.Errorf("unable to revoke certificate for %s: %w", host.Hostname, err)
+		}
+		c.CO.Logger().Info("deleted key", "host", host.Hostname)
+		res.Keys = append(res.Keys, nodeKey(host))
+	}
+	return res, nil
+}
+
+// changeKeys moves every matching node to the status returned by next and returns the nodes that were changed.
+// next returns an empty status to leave a node alone.
+func (c *CommanderServer) changeKeys(in *pb.KeyQuery, defaults []string, next func(current string) string) (*pb.KeyList, error) {
+	res := &pb.KeyList{}
+	hosts, err := c.matchKeys(in, defaults)
+	if err != nil {
+		return nil, err
+	}
+	for _, host := range hosts {
+		to := next(host.Status)
+		if to == "" {
+			continue
+		}
+		if err := queries.SetHostStatus(c.DB, host.Hostname, to); err != nil {
+			return res, fmt.Errorf("unable to set %s to %s: %w", host.Hostname, to, err)
+		}
+		if to == queries.NodeRejected || to == queries.NodeRevoked {
+			if err := c.CO.revokeNode(host.Hostname); err != nil {
+				return res, fmt.Errorf("unable to revoke certificate for %s: %w", host.Hostname, err)
+			}
+		}
+		c.CO.Logger().Info("key status changed", "host", host.Hostname, "from", host.Status, "to", to)
+		host.Status = to
+		res.Keys = append(res.Keys, nodeKey(host))
+	}
+	return res, nil
+}
+
+// matchKeys returns the nodes whose hostname matches the glob in the query. Only nodes with the status in the
+// query are returned, or with one of the default statuses when the query doesn't have one. No defaults means any status.
+func (c *CommanderServer) matchKeys(in *pb.KeyQuery, defaults []string) ([]*queries.RegisteredHostsData, error) {
+	pattern := in.Pattern
+	if pattern == "" {
+		pattern = "*"
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid pattern %s: %v", pattern, err)
+	}
+	statuses := defaults
+	if in.Status != pb.KeyStatus_KEY_STATUS_UNSPECIFIED {
+		statuses = []string{keyStatusName(in.Status)}
+	}
+
+	hosts := make([]*queries.RegisteredHostsData, 0)
+	for host := range queries.GetRegisteredHosts(c.DB) {
+		if ok, _ := path.Match(pattern, host.Hostname); !ok {
+			continue
+		}
+		if statuses != nil && !slices.Contains(statuses, host.Status) {
+			continue
+		}
+		hosts = append(hosts, host)
+	}
+	slices.SortFunc(hosts, func(a, b *queries.RegisteredHostsData) int {
+		return strings.Compare(a.Hostname, b.Hostname)
+	})
+	return hosts, nil
+}
+
+func nodeKey(host *queries.RegisteredHostsData) *pb.NodeKey {
+	return &pb.NodeKey{
+		Hostname:    host.Hostname,
+		KeyId:       host.Key,
+		Fingerprint: host.Fingerprint,
+		Status:      keyStatus(host.Status),
+	}
+}
+
+func keyStatus(s string) pb.KeyStatus {
+	switch s {
+	case queries.NodePending:
+		return pb.KeyStatus_KEY_PENDING
+	case queries.NodeAccepted:
+		return pb.KeyStatus_KEY_ACCEPTED
+	case queries.NodeRejected:
+		return pb.KeyStatus_KEY_REJECTED
+	case queries.NodeRevoked:
+		return pb.KeyStatus_KEY_REVOKED
+	}
+	return pb.KeyStatus_KEY_STATUS_UNSPECIFIED
+}
+
+func keyStatusName(s pb.KeyStatus) string {
+	switch s {
+	case pb.KeyStatus_KEY_PENDING:
+		return queries.NodePending
+	case pb.KeyStatus_KEY_ACCEPTED:
+		return queries.NodeAccepted
+	case pb.KeyStatus_KEY_REJECTED:
+		return queries.NodeRejected
+	case pb.KeyStatus_KEY_REVOKED:
+		return queries.NodeRevoked
+	}
+	return ""
+}
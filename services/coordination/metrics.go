@@ -0,0 +1,79 @@
+package coordination
+
+import (
+	"github.com/charles-d-burton/tailsys/data/queries"
+	"github.com/charles-d-burton/tailsys/metrics"
+)
+
+// commandBuckets go up to the length of a long running job, most commands finish well within a minute
+var commandBuckets = []float64{.05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60, 300, 900, 3600}
+
+var (
+	commandsDispatched = metrics.NewCounterVec("tailsys_commands_dispatched_total",
+		"Commands sent to nodes, by host.", "host")
+	commandsFinished = metrics.NewCounterVec("tailsys_commands_finished_total",
+		"Commands that finished on nodes, by host and result.", "host", "result")
+	commandDuration = metrics.NewHistogramVec("tailsys_command_duration_seconds",
+		"Time from sending a command to a node until it finished, by host.", commandBuckets, "host")
+	pingDuration = metrics.NewHistogramVec("tailsys_ping_duration_seconds",
+		"Round trip time of the health pings that nodes answered, by host.", nil, "host")
+	pingsFailed = metrics.NewCounterVec("tailsys_pings_failed_total",
+		"Health pings that nodes didn't answer, by host.", "host")
+	registrations = metrics.NewCounterVec("tailsys_registrations_total",
+		"Registration requests from nodes, by the status they got.", "status")
+)
+
+// Names of the metrics that are read from the coordinator when they are served
+const (
+	nodesMetric      = "tailsys_nodes"
+	nodeHealthMetric = "tailsys_node_health"
+	nodeConnsMetric  = "tailsys_node_connections"
+)
+
+// commandResult is the result label of a finished command
+func commandResult(succeeded, canceled bool) string {
+	switch {
+	case succeeded:
+		return "succeeded"
+	case canceled:
+		return "canceled"
+	}
+	return "failed"
+}
+
+// registerMetrics adds the metrics that are looked up in the database and the connection pool, the returned func
+// removes them again
+func (co *Coordinator) registerMetrics() func() {
+	metrics.NewGaugeFunc(nodesMetric, "Registered nodes, by registration status.", []string{"status"},
+		func(emit func(float64, ...string)) {
+			counts, err := queries.CountHostsByStatus(co.DB)
+			if err != nil {
+				co.Logger().Warn("unable to count nodes for metrics", "err", err)
+				return
+			}
+			//every status is always there so a status going to zero shows up as zero instead of missing
+			for _, s := range []string{queries.NodePending, queries.NodeAccepted, queries.NodeRejected, queries.NodeRevoked} {
+				emit(float64(counts[s]), s)
+			}
+		})
+	metrics.NewGaugeFunc(nodeHealthMetric, "Pinged nodes, by health.", []string{"health"},
+		func(emit func(float64, ...string)) {
+			counts, err := queries.CountNodesByHealth(co.DB)
+			if err != nil {
+				co.Logger().Warn("unable to count node health for metrics", "err", err)
+				return
+			}
+			for _, h := range []string{queries.HealthUnknown, queries.HealthOnline, queries.HealthDegraded, queries.HealthOffline} {
+				emit(float64(counts[h]), h)
+			}
+		})
+	metrics.NewGaugeFunc(nodeConnsMetric, "Connections the coordinator holds open to nodes.", nil,
+		func(emit func(float64, ...string)) {
+			emit(float64(co.conns.size()))
+		})
+	return func() {
+		metrics.Default.Unregister(nodesMetric)
+		metrics.Default.Unregister(nodeHealthMetric)
+		metrics.Default.Unregister(nodeConnsMetric)
+	}
+}
@@ -2,63 +2,153 @@ package coordination
 
 import (
 	"context"
+	"crypto/ed25519"
 	"database/sql"
+	"errors"
 	"fmt"
+	"log/slog"
+	"strings"
 
 	pb "github.com/charles-d-burton/tailsys/commands"
+	"github.com/charles-d-burton/tailsys/connections"
 	"github.com/charles-d-burton/tailsys/data/queries"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
 )
 
 // RegistrationServer struct to contain proto for gRPC
 type RegistrationServer struct {
 	pb.UnimplementedRegistrationServer
-	DevMode  bool
-	ID       string
-	Hostname string
-	DB       *sql.DB
+	DevMode    bool
+	ID         string
+	Hostname   string
+	DB         *sql.DB
+	CA         *connections.CA
+	SigningKey ed25519.PublicKey
+	Log        *slog.Logger
 }
 
-func (r *RegistrationServer) createRegistration(nrr *pb.NodeRegistrationRequest) error {
+// createRegistration stores the registration and returns the status of the node. A node keeps its status when it
+// registers again with the same key, a different key is turned away until the old one is deleted.
+func (r *RegistrationServer) createRegistration(nrr *pb.NodeRegistrationRequest) (string, error) {
+	clientName := nrr.GetInfo().Hostname
+	if len(nrr.Csr) == 0 {
+		return "", status.Error(codes.InvalidArgument, "registration must include a certificate request")
+	}
+	fp, err := csrFingerprint(nrr.Csr)
+	if err != nil {
+		return "", status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	nodeStatus := queries.NodePending
+	existing, err := queries.GetRegisteredHost(r.DB, clientName)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+	case err != nil:
+		return "", err
+	case existing.Status == queries.NodeRejected, existing.Status == queries.NodeRevoked:
+		//a node that was turned away stays that way until its key is deleted
+		nodeStatus = existing.Status
+	case existing.Fingerprint == fp:
+		nodeStatus = existing.Status
+	default:
+		return "", status.Errorf(codes.AlreadyExists, "%s is already registered with a different key, delete it with tailsys keys delete to register again", clientName)
+	}
+	if nodeStatus == queries.NodePending && r.DevMode {
+		r.Log.Info("dev mode, accepting node without review", "host", clientName)
+		nodeStatus = queries.NodeAccepted
+	}
+	nrr.Accepted = nodeStatus == queries.NodeAccepted
+	//older nodes sent their private key, it must never be stored
+	nrr.Tlskey = ""
+	nrr.Tlscert = ""
+	//the inventory is kept in its own tables where it can be queried
+	inv := nrr.GetInfo().GetInventory()
+	if nrr.Info != nil {
+		nrr.Info.Inventory = nil
+	}
 
 	data, err := proto.Marshal(nrr)
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	clientName := nrr.GetInfo().Hostname
-
 	nhr := &queries.RegisteredHostsData{
-		Hostname: nrr.GetInfo().Hostname,
-		Key:      nrr.GetKey().Key,
-		Data:     data,
+		Hostname:    clientName,
+		Key:         nrr.GetKey().Key,
+		Data:        data,
+		Status:      nodeStatus,
+		Fingerprint: fp,
 	}
-	// clientKey := nrr.Key.GetKey()
-	fmt.Printf("registering %s\n", clientName)
+	r.Log.Info("registering node", "host", clientName, "status", nodeStatus)
 	err = queries.InsertHostRegistration(r.DB, nhr)
 
 	if err != nil {
-		fmt.Println("could not create bucket")
-		return err
+		return "", fmt.Errorf("unable to store registration of %s: %w", clientName, err)
 	}
-	return nil
+	if inv != nil {
+		if err := storeInventory(r.DB, clientName, nrr.Info.Ip, inv); err != nil {
+			r.Log.Error("unable to store inventory", "host", clientName, "err", err)
+		}
+	}
+	return nodeStatus, nil
 }
 
 // Register registers a node with the database when a node sends a request.  Returns the server id so the node can verify further requests
+// along with a certificate for the node once it has been accepted. Until then the node registers again to find out.
 func (r *RegistrationServer) Register(ctx context.Context, in *pb.NodeRegistrationRequest) (*pb.NodeRegistrationResponse, error) {
-	fmt.Println("received coordination request")
-	fmt.Println(in)
-	in.Accepted = r.DevMode
-	if r.DevMode {
-		fmt.Println("running in dev mode, accepting all incoming connections")
+	hostname := in.GetInfo().GetHostname()
+	r.Log.Debug("received registration", "host", hostname)
+	if err := r.verifyCaller(ctx, hostname); err != nil {
+		registrations.With("error").Inc()
+		return nil, err
 	}
-	if err := r.createRegistration(in); err != nil {
+	nodeStatus, err := r.createRegistration(in)
+	if err != nil {
+		registrations.With("error").Inc()
+		return nil, err
+	}
+	registrations.With(nodeStatus).Inc()
+
+	switch nodeStatus {
+	case queries.NodeRejected, queries.NodeRevoked:
+		return nil, status.Errorf(codes.PermissionDenied, "%s has been %s", hostname, nodeStatus)
+	case queries.NodePending:
+		return &pb.NodeRegistrationResponse{
+			Key:        &pb.Key{Key: r.ID},
+			Hostname:   r.Hostname,
+			SigningKey: r.SigningKey,
+		}, nil
+	}
+
+	res := &pb.NodeRegistrationResponse{
+		Accepted:   true,
+		Key:        &pb.Key{Key: r.ID},
+		Hostname:   r.Hostname,
+		SigningKey: r.SigningKey,
+	}
+	res.Certificate, err = r.certificate(in.Csr, hostname)
+	if err != nil {
 		return nil, err
 	}
+	return res, nil
+}
 
-	return &pb.NodeRegistrationResponse{
-		Accepted: r.DevMode,
-		Key:      &pb.Key{Key: r.ID},
-		Hostname: r.Hostname,
-	}, nil
+// verifyCaller checks that the node is registering under its own name on the tailnet, so it can't be issued a
+// certificate for another node. Without a tailnet nodes can't be identified, that is only allowed in dev mode.
+func (r *RegistrationServer) verifyCaller(ctx context.Context, hostname string) error {
+	id := connections.IdentityFromContext(ctx)
+	if id == nil {
+		if r.DevMode {
+			r.Log.Warn("dev mode, registering node that couldn't be identified", "host", hostname)
+			return nil
+		}
+		return status.Errorf(codes.Unauthenticated, "unable to identify the node registering as %s", hostname)
+	}
+	if !strings.EqualFold(id.Node, hostname) {
+		r.Log.Warn("denied registration", "host", hostname, "caller", id.String())
+		return status.Errorf(codes.PermissionDenied, "%s may not register as %s", id, hostname)
+	}
+	return nil
 }
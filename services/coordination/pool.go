@@ -0,0 +1,167 @@
+package coordination
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+)
+
+// DefaultConnIdleTimeout is how long a connection to a node is kept open without being used
+const DefaultConnIdleTimeout = 5 * time.Minute
+
+// connPool caches a connection to each node, shared by pings, commands and inventory requests so they don't each pay
+// for a new connection and tls handshake. Connections are dropped when they fail or go unused for idleTimeout.
+type connPool struct {
+	log         *slog.Logger
+	mu          sync.Mutex
+	conns       map[string]*pooledConn
+	idleTimeout time.Duration
+}
+
+// pooledConn is closed once it has been evicted and nobody is using it anymore
+type pooledConn struct {
+	conn     *grpc.ClientConn
+	addr     string
+	inUse    int
+	lastUsed time.Time
+	evicted  bool
+}
+
+// WithConnIdleTimeout sets how long connections to nodes are kept open without being used
+func (co *Coordinator) WithConnIdleTimeout(d time.Duration) Option {
+	return func(co *Coordinator) error {
+		co.conns.idleTimeout = d
+		return nil
+	}
+}
+
+// start evicts idle connections in the background until ctx is done, then closes every connection
+func (p *connPool) start(ctx context.Context, log *slog.Logger) {
+	p.log = log
+	if p.idleTimeout <= 0 {
+		p.idleTimeout = DefaultConnIdleTimeout
+	}
+	go func() {
+		ticker := time.NewTicker(p.idleTimeout / 2)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				p.closeAll()
+				return
+			case <-ticker.C:
+				p.evictIdle()
+			}
+		}
+	}()
+}
+
+// get returns the connection to the node at addr, dialing it with dial when there is none or the node moved to
+// another address. Every connection returned has to be given back with release.
+func (p *connPool) get(hostname, addr string, dial func() (*grpc.ClientConn, error)) (*pooledConn, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.conns == nil {
+		p.conns = make(map[string]*pooledConn)
+	}
+	if pc, ok := p.conns[hostname]; ok {
+		if pc.addr == addr && usable(pc.conn.GetState()) {
+			pc.inUse++
+			return pc, nil
+		}
+		p.evictLocked(hostname, pc)
+	}
+
+	//dialing doesn't block, the connection is made on first use
+	conn, err := dial()
+	if err != nil {
+		return nil, err
+	}
+	pc := &pooledConn{conn: conn, addr: addr, inUse: 1}
+	p.conns[hostname] = pc
+	go p.watch(hostname, pc)
+	return pc, nil
+}
+
+// size is how many connections the pool holds
+func (p *connPool) size() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.conns)
+}
+
+// release gives the connection back, closing it if it was evicted while in use
+func (p *connPool) release(pc *pooledConn) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	pc.inUse--
+	pc.lastUsed = time.Now()
+	if pc.evicted && pc.inUse == 0 {
+		pc.conn.Close()
+	}
+}
+
+// remove drops the connection to the node, a node that was rejected or deleted mustn't be reached through it anymore
+func (p *connPool) remove(hostname string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if pc, ok := p.conns[hostname]; ok {
+		p.evictLocked(hostname, pc)
+	}
+}
+
+// watch evicts the connection as soon as it fails, so the next caller dials the node again
+func (p *connPool) watch(hostname string, pc *pooledConn) {
+	for {
+		state := pc.conn.GetState()
+		if !usable(state) {
+			p.mu.Lock()
+			if p.conns[hostname] == pc {
+				p.log.Debug("dropping failed connection", "host", hostname, "state", state.String())
+				p.evictLocked(hostname, pc)
+			}
+			p.mu.Unlock()
+			return
+		}
+		pc.conn.WaitForStateChange(context.Background(), state)
+	}
+}
+
+func (p *connPool) evictIdle() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for hostname, pc := range p.conns {
+		if pc.inUse == 0 && time.Since(pc.lastUsed) > p.idleTimeout {
+			p.evictLocked(hostname, pc)
+		}
+	}
+}
+
+func (p *connPool) closeAll() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for hostname, pc := range p.conns {
+		p.evictLocked(hostname, pc)
+	}
+}
+
+func (p *connPool) evictLocked(hostname string, pc *pooledConn) {
+	if p.conns[hostname] == pc {
+		delete(p.conns, hostname)
+	}
+	if pc.evicted {
+		return
+	}
+	pc.evicted = true
+	if pc.inUse == 0 {
+		pc.conn.Close()
+	}
+}
+
+func usable(state connectivity.State) bool {
+	return state != connectivity.TransientFailure && state != connectivity.Shutdown
+}
@@ -0,0 +1,224 @@
+package coordination
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	pb "github.com/charles-d-burton/tailsys/commands"
+	"github.com/charles-d-burton/tailsys/data/queries"
+	"github.com/charles-d-burton/tailsys/services"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// shellString is how a shell session is named in acl rules and the session history, an interactive login shell is
+// the command "shell"
+func shellString(open *pb.ShellOpen) string {
+	if len(open.Command) > 0 {
+		return strings.Join(open.Command, " ")
+	}
+	return "shell"
+}
+
+// shellHost resolves the host of the session, a shell is only ever opened on a single node
+func (co *Coordinator) shellHost(ctx context.Context, host string) (*queries.RegisteredHostsData, error) {
+	hosts, err := co.matchNodes(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	switch len(hosts) {
+	case 0:
+		return nil, status.Errorf(codes.NotFound, "no accepted node matches %s", host)
+	case 1:
+		return hosts[0], nil
+	}
+	return nil, status.Errorf(codes.InvalidArgument, "%s matches %d nodes, a shell is opened on a single node", host, len(hosts))
+}
+
+// Shell relays an interactive shell between the operator and a node. The session is recorded as it happens and its
+// transcript is written to the database when it ends.
+func (c *CommanderServer) Shell(stream pb.CommandManager_ShellServer) error {
+	first, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	open := first.GetOpen()
+	if open == nil {
+		return status.Error(codes.InvalidArgument, "session has to start with open")
+	}
+	host, err := c.CO.shellHost(stream.Context(), open.Host)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+
+	session := &queries.ShellSessionRow{
+		SessionID: uuid.NewString(),
+		Requester: requester(ctx),
+		Hostname:  host.Hostname,
+		User:      open.User,
+		Command:   shellString(open),
+		Started:   time.Now().UTC(),
+	}
+	if err := queries.InsertShellSession(c.DB, session); err != nil {
+		return fmt.Errorf("unable to record shell session: %w", err)
+	}
+	log := c.CO.Logger().With("session", session.SessionID, "host", host.Hostname)
+	log.Info("shell session started", "requester", session.Requester, "user", open.User, "command", session.Command)
+
+	//the size the node gives a terminal when the operator's isn't known
+	if open.GetSize().GetRows() == 0 || open.GetSize().GetCols() == 0 {
+		open.Size = &pb.WindowSize{Rows: 24, Cols: 80}
+	}
+	rows, cols := open.Size.Rows, open.Size.Cols
+	rec := newTranscript(session.Requester+" on "+host.Hostname, open.Term, cols, rows)
+	exit, err := c.relayShell(ctx, stream, open, host, session.SessionID, rec)
+	if err != nil {
+		log.Warn("shell session failed", "err", err)
+	}
+
+	session.Ended = sql.NullTime{Time: time.Now().UTC(), Valid: true}
+	session.Transcript = rec.bytes()
+	switch {
+	case exit != nil:
+		session.ExitCode = sql.NullInt32{Int32: exit.ExitCode, Valid: true}
+		session.Error = exit.Error
+	case err != nil:
+		session.Error = err.Error()
+	}
+	if ferr := queries.FinishShellSession(c.DB, session); ferr != nil {
+		log.Error("unable to record shell session", "err", ferr)
+	}
+	log.Info("shell session ended", "exit_code", session.ExitCode.Int32, "duration", time.Since(session.Started))
+	return err
+}
+
+// relayShell opens the shell on the node and copies the session between it and the operator until the shell exits
+func (c *CommanderServer) relayShell(ctx context.Context, stream pb.CommandManager_ShellServer, open *pb.ShellOpen, host *queries.RegisteredHostsData, sessionID string, rec *transcript) (*pb.ShellExit, error) {
+	conn, err := c.CO.dialNode(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	req := &pb.ShellRequest{
+		Open:      open,
+		SessionId: sessionID,
+		Hostname:  host.Hostname,
+		Key:       &pb.Key{Key: c.ID},
+	}
+	if err := services.SignRequest(c.CO.identity.Key, req); err != nil {
+		return nil, fmt.Errorf("unable to sign shell for host %s: %w", host.Hostname, err)
+	}
+	node, err := pb.NewShellClient(conn).Session(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open shell on host %s: %w", host.Hostname, err)
+	}
+	if err := node.Send(&pb.ShellInput{Payload: &pb.ShellInput_Request{Request: req}}); err != nil {
+		return nil, fmt.Errorf("unable to open shell on host %s: %w", host.Hostname, err)
+	}
+	if err := stream.Send(&pb.ShellOutput{Payload: &pb.ShellOutput_SessionId{SessionId: sessionID}}); err != nil {
+		return nil, err
+	}
+
+	go func() {
+		//closing the input tells the node the operator is gone, it hangs up the shell
+		defer node.CloseSend()
+		for {
+			in, err := stream.Recv()
+			if err != nil {
+				return
+			}
+			if in.GetOpen() != nil || in.GetRequest() != nil {
+				continue
+			}
+			if resize := in.GetResize(); resize != nil {
+				rec.resize(resize.Cols, resize.Rows)
+			}
+			if err := node.Send(in); err != nil {
+				return
+			}
+		}
+	}()
+
+	for {
+		out, err := node.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("shell on host %s ended without an exit status", host.Hostname)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("lost shell on host %s: %w", host.Hostname, err)
+		}
+		if data := out.GetData(); data != nil {
+			rec.output(data)
+		}
+		if err := stream.Send(out); err != nil {
+			return nil, err
+		}
+		if out.GetExit() != nil {
+			return out.GetExit(), nil
+		}
+	}
+}
+
+// ListShellSessions returns the most recent shell sessions the caller may read without their transcripts
+func (c *CommanderServer) ListShellSessions(ctx context.Context, in *pb.ShellSessionQuery) (*pb.ShellSessionList, error) {
+	limit := int(in.Limit)
+	if limit < 1 {
+		limit = 25
+	}
+	readable, err := c.CO.readableBy(ctx, "/tailsys.CommandManager/ListShellSessions")
+	if err != nil {
+		return nil, err
+	}
+	sessions, err := queries.GetShellSessions(c.DB, limit)
+	if err != nil {
+		return nil, err
+	}
+	res := &pb.ShellSessionList{}
+	for _, s := range sessions {
+		if readable(s.Requester, []string{s.Hostname}) {
+			res.Sessions = append(res.Sessions, shellSession(s))
+		}
+	}
+	return res, nil
+}
+
+// GetShellSession returns a shell session along with its transcript
+func (c *CommanderServer) GetShellSession(ctx context.Context, in *pb.ShellSessionID) (*pb.ShellSession, error) {
+	s, err := queries.GetShellSession(c.DB, in.SessionId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, status.Errorf(codes.NotFound, "shell session %s not found", in.SessionId)
+	}
+	if err != nil {
+		return nil, err
+	}
+	res := shellSession(s)
+	res.Transcript = s.Transcript
+	return res, nil
+}
+
+func shellSession(s *queries.ShellSessionRow) *pb.ShellSession {
+	res := &pb.ShellSession{
+		SessionId: s.SessionID,
+		Requester: s.Requester,
+		Hostname:  s.Hostname,
+		User:      s.User,
+		Command:   s.Command,
+		Started:   timestamppb.New(s.Started),
+		ExitCode:  s.ExitCode.Int32,
+		Error:     s.Error,
+	}
+	if s.Ended.Valid {
+		res.Ended = timestamppb.New(s.Ended.Time)
+	}
+	return res
+}
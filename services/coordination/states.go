@@ -0,0 +1,110 @@
+package coordination
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	pb "github.com/charles-d-burton/tailsys/commands"
+	"github.com/charles-d-burton/tailsys/data/queries"
+	"github.com/charles-d-burton/tailsys/services"
+	"github.com/charles-d-burton/tailsys/services/state"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// DefaultStateTimeout is how long a node may take to apply a state when the request doesn't say
+const DefaultStateTimeout = 10 * time.Minute
+
+// ApplyState sends the state to every node the pattern matches and streams back how each node applied it. The
+// state is parsed here first so a broken state file isn't sent anywhere.
+func (c *CommanderServer) ApplyState(req *pb.StateApplyRequest, stream pb.CommandManager_ApplyStateServer) error {
+	if _, err := state.Parse(req.State); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	hosts, err := c.CO.matchNodes(stream.Context(), req.Pattern)
+	if err != nil {
+		return err
+	}
+	timeout := req.GetTimeout().AsDuration()
+	if timeout <= 0 {
+		timeout = DefaultStateTimeout
+	}
+	c.CO.Logger().Info("applying state", "pattern", req.Pattern, "hosts", len(hosts), "test", req.Test)
+
+	ctx := stream.Context()
+	var mu sync.Mutex
+	var sendErr error
+	send := func(res *pb.StateResponse) {
+		mu.Lock()
+		defer mu.Unlock()
+		if sendErr == nil {
+			sendErr = stream.Send(res)
+		}
+	}
+
+	sem := make(chan struct{}, 50)
+	var wg sync.WaitGroup
+	for _, host := range hosts {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func(host *queries.RegisteredHostsData) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			send(c.sendState(ctx, req, host, timeout))
+		}(host)
+	}
+	wg.Wait()
+	if sendErr != nil {
+		return sendErr
+	}
+	return ctx.Err()
+}
+
+// sendState applies the state on a single node, failing to reach the node is reported as the error of its response
+func (c *CommanderServer) sendState(ctx context.Context, req *pb.StateApplyRequest, host *queries.RegisteredHostsData, timeout time.Duration) *pb.StateResponse {
+	hostname := host.Hostname
+	log := c.CO.Logger().With("host", hostname)
+	failed := func(err error) *pb.StateResponse {
+		log.Warn("unable to apply state", "err", err)
+		return &pb.StateResponse{
+			Hostname:  hostname,
+			Timestamp: timestamppb.Now(),
+			Test:      req.Test,
+			Error:     err.Error(),
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	conn, err := c.CO.dialNode(ctx, host)
+	if err != nil {
+		return failed(err)
+	}
+	defer conn.Close()
+
+	sr := &pb.StateRequest{
+		Hostname: hostname,
+		Key:      &pb.Key{Key: c.ID},
+		State:    req.State,
+		Test:     req.Test,
+	}
+	if err := services.SignRequest(c.CO.identity.Key, sr); err != nil {
+		return failed(fmt.Errorf("unable to sign state for host %s: %w", hostname, err))
+	}
+	res, err := pb.NewStateRunnerClient(conn).ApplyState(ctx, sr)
+	if err != nil {
+		return failed(fmt.Errorf("unable to apply state on host %s: %w", hostname, err))
+	}
+	res.Hostname = hostname
+	log.Info("state applied", "successful", res.Successful, "test", res.Test)
+	return res
+}
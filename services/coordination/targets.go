@@ -0,0 +1,164 @@
+package coordination
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charles-d-burton/tailsys/connections"
+	"github.com/charles-d-burton/tailsys/data/queries"
+	"github.com/charles-d-burton/tailsys/services/targeting"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gopkg.in/yaml.v3"
+)
+
+// nodeGroups are named target expressions, reloaded whenever the file changes
+//
+//	groups:
+//	  web: "web-* and os=ubuntu"
+//	  big: "mem_gb>=64"
+type nodeGroups struct {
+	log     *slog.Logger
+	path    string
+	mu      sync.Mutex
+	modTime time.Time
+	groups  map[string]string
+}
+
+// WithNodeGroups sets the file node groups are defined in, ConfigDir/nodegroups.yaml when not set
+func (co *Coordinator) WithNodeGroups(path string) Option {
+	return func(co *Coordinator) error {
+		co.nodeGroups.path = path
+		return nil
+	}
+}
+
+// current returns the node groups, there are none when the file doesn't exist
+func (ng *nodeGroups) current() (map[string]string, error) {
+	ng.mu.Lock()
+	defer ng.mu.Unlock()
+
+	fi, err := os.Stat(ng.path)
+	if errors.Is(err, os.ErrNotExist) {
+		ng.groups = nil
+		ng.modTime = time.Time{}
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if ng.groups != nil && fi.ModTime().Equal(ng.modTime) {
+		return ng.groups, nil
+	}
+
+	data, err := os.ReadFile(ng.path)
+	if err != nil {
+		return nil, err
+	}
+	file := struct {
+		Groups map[string]string `yaml:"groups"`
+	}{}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid node groups %s: %w", ng.path, err)
+	}
+	if file.Groups == nil {
+		file.Groups = make(map[string]string)
+	}
+	ng.log.Info("loaded node groups", "path", ng.path, "groups", len(file.Groups))
+	ng.groups = file.Groups
+	ng.modTime = fi.ModTime()
+	return ng.groups, nil
+}
+
+// matchNodes returns the accepted nodes the target expression matches, sorted by hostname. Listing nodes,
+// sending commands and checking the acl all go through here so they always agree on which nodes are targeted.
+func (co *Coordinator) matchNodes(ctx context.Context, expr string) ([]*queries.RegisteredHostsData, error) {
+	groups, err := co.nodeGroups.current()
+	if err != nil {
+		return nil, err
+	}
+	target, err := targeting.Parse(expr, func(name string) (string, bool) {
+		g, ok := groups[name]
+		return g, ok
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid target %q: %v", expr, err)
+	}
+
+	hosts, err := queries.GetAcceptedHosts(co.DB)
+	if err != nil {
+		return nil, err
+	}
+	invs, err := queries.GetInventories(co.DB)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load inventory: %w", err)
+	}
+	peers, err := co.TailnetPeers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list tailnet devices: %w", err)
+	}
+	matched := make([]*queries.RegisteredHostsData, 0)
+	for _, host := range hosts {
+		if target.Match(targetNode(host.Hostname, invs[host.Hostname], peers[strings.ToLower(host.Hostname)])) {
+			matched = append(matched, host)
+		}
+	}
+	slices.SortFunc(matched, func(a, b *queries.RegisteredHostsData) int {
+		return strings.Compare(a.Hostname, b.Hostname)
+	})
+	return matched, nil
+}
+
+// targetNode builds the facts of a node from its last inventory, a node that never sent one only has a hostname.
+// Tags and ips pick which nodes are sent commands and files so they come from the tailnet, a node could claim any
+// tag in its inventory.
+func targetNode(hostname string, inv *queries.InventoryRow, peer *connections.TailnetPeer) *targeting.Node {
+	node := &targeting.Node{
+		Hostname: hostname,
+		Facts:    map[string][]string{"hostname": {hostname}},
+	}
+	set := func(key string, values ...string) {
+		for _, v := range values {
+			if v != "" {
+				node.Facts[key] = append(node.Facts[key], v)
+			}
+		}
+	}
+	if peer != nil {
+		set("ip", peer.Addresses...)
+		set("tag", peer.Tags...)
+	}
+	if inv == nil {
+		return node
+	}
+
+	osName := inv.Distro
+	if osName == "" {
+		osName = inv.OS
+	}
+	const gib = 1 << 30
+	set("os", osName)
+	set("os_version", inv.DistroVersion)
+	set("platform", inv.OS)
+	set("kernel", inv.Kernel)
+	set("arch", inv.Arch)
+	set("cpus", strconv.Itoa(int(inv.CPUCount)))
+	set("cpu_model", inv.CPUModel)
+	set("mem_gb", formatFloat(float64(inv.MemoryTotal)/gib))
+	set("mem_free_gb", formatFloat(float64(inv.MemoryAvailable)/gib))
+	set("uptime_hours", formatFloat(float64(inv.UptimeSeconds)/3600))
+	set("version", inv.TailsysVersion)
+	return node
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', 2, 64)
+}
@@ -0,0 +1,64 @@
+package coordination
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/charles-d-burton/tailsys/connections"
+	"github.com/charles-d-burton/tailsys/data/queries"
+)
+
+const testDevices = `{"devices": [
+	{"hostname": "web1", "name": "web1.example.ts.net", "addresses": ["100.64.0.1"], "tags": ["tag:prod"]},
+	{"hostname": "db", "name": "db1.example.ts.net", "addresses": ["100.64.0.2"], "tags": ["tag:database"]}
+]}`
+
+func TestMatchNodesTailnetFacts(t *testing.T) {
+	co := newTestCoordinator(t, "", "web1", "db1", "rogue")
+	devices := filepath.Join(t.TempDir(), "devices.json")
+	if err := os.WriteFile(devices, []byte(testDevices), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := co.WithDeviceLister(connections.DeviceFile(devices))(&co.Tailnet); err != nil {
+		t.Fatal(err)
+	}
+	//the inventory of a node is what the node says about itself, its tags and ips are not trusted
+	err := queries.SetInventory(co.DB, &queries.InventoryRow{
+		Hostname:      "rogue",
+		OS:            "linux",
+		TailscaleIPs:  []string{"100.64.0.1"},
+		TailscaleTags: []string{"tag:prod", "tag:database"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{"tag:prod", "web1"},
+		{"tag:database", "db1"},
+		{"ip=100.64.0.1", "web1"},
+		{"ip=100.64.0.*", "db1,web1"},
+		{"platform=linux", "rogue"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			hosts, err := co.matchNodes(context.Background(), tt.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			names := make([]string, 0, len(hosts))
+			for _, host := range hosts {
+				names = append(names, host.Hostname)
+			}
+			if got := strings.Join(names, ","); got != tt.want {
+				t.Fatalf("%s matched %s, want %s", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
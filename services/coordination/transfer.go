@@ -0,0 +1,338 @@
+package coordination
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	pb "github.com/charles-d-burton/tailsys/commands"
+	"github.com/charles-d-burton/tailsys/data/queries"
+	"github.com/charles-d-burton/tailsys/services"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ChunkSize is how much of a file goes in each message of a transfer
+const ChunkSize = 64 * 1024
+
+// DefaultMaxSendSize is the largest file SendFile accepts, unless the coordination server is configured otherwise
+const DefaultMaxSendSize = 1024 * 1024 * 1024
+
+// WithMaxSendSize sets the largest file SendFile accepts. Files are buffered on disk before they are sent on, this
+// bounds how much disk a single upload can take.
+func (co *Coordinator) WithMaxSendSize(size uint64) Option {
+	return func(co *Coordinator) error {
+		co.maxSend = size
+		return nil
+	}
+}
+
+// maxSendSize is the largest file SendFile accepts
+func (co *Coordinator) maxSendSize() uint64 {
+	if co.maxSend == 0 {
+		return DefaultMaxSendSize
+	}
+	return co.maxSend
+}
+
+// SendFile receives a file and copies it to every node the pattern matches, streaming back how each node wrote it.
+// The whole file is received and its checksum verified before anything is sent, nodes are sent the same copy.
+func (c *CommanderServer) SendFile(stream pb.CommandManager_SendFileServer) error {
+	first, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	header := first.GetHeader()
+	if header == nil || header.GetFile() == nil {
+		return status.Error(codes.InvalidArgument, "transfer has to start with a header")
+	}
+	file := header.GetFile()
+	if err := services.CheckPath(file.Path); err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid destination: %v", err)
+	}
+	if limit := c.CO.maxSendSize(); file.Size > limit {
+		return status.Errorf(codes.ResourceExhausted, "file of %d bytes is larger than the limit of %d bytes", file.Size, limit)
+	}
+
+	tmp, err := os.CreateTemp("", "tailsys-transfer-*")
+	if err != nil {
+		return fmt.Errorf("unable to buffer file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+	if err := receiveFile(tmp, stream, file); err != nil {
+		return err
+	}
+
+	hosts, err := c.CO.matchNodes(stream.Context(), header.Pattern)
+	if err != nil {
+		return err
+	}
+	c.CO.Logger().Info("sending file", "pattern", header.Pattern, "path", file.Path, "size", file.Size, "hosts", len(hosts))
+
+	ctx := stream.Context()
+	var mu sync.Mutex
+	var sendErr error
+	send := func(res *pb.FileUploadResponse) {
+		mu.Lock()
+		defer mu.Unlock()
+		if sendErr == nil {
+			sendErr = stream.Send(res)
+		}
+	}
+
+	sem := make(chan struct{}, 50)
+	var wg sync.WaitGroup
+	for _, host := range hosts {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func(host *queries.RegisteredHostsData) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			//every node reads the buffered file on its own, a section reader keeps their offsets apart
+			send(c.uploadFile(ctx, file, io.NewSectionReader(tmp, 0, int64(file.Size)), host))
+		}(host)
+	}
+	wg.Wait()
+	if sendErr != nil {
+		return sendErr
+	}
+	return ctx.Err()
+}
+
+// receiveFile writes the chunks of the stream to w and checks they are the file the header describes. It stops as soon
+// as more arrives than the header says, so the size checked against the limit is all that gets written.
+func receiveFile(w io.Writer, stream pb.CommandManager_SendFileServer, file *pb.FileHeader) error {
+	sum := sha256.New()
+	var size uint64
+	for {
+		msg, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if msg.GetHeader() != nil {
+			return status.Error(codes.InvalidArgument, "transfer may only have one header")
+		}
+		size += uint64(len(msg.GetData()))
+		if size > file.Size {
+			return status.Errorf(codes.InvalidArgument, "received more than the %d bytes of the file", file.Size)
+		}
+		if _, err := w.Write(msg.GetData()); err != nil {
+			return fmt.Errorf("unable to buffer file: %w", err)
+		}
+		sum.Write(msg.GetData())
+	}
+	if size != file.Size {
+		return status.Errorf(codes.InvalidArgument, "received %d of %d bytes", size, file.Size)
+	}
+	if !bytes.Equal(sum.Sum(nil), file.Sha256) {
+		return status.Error(codes.InvalidArgument, "checksum doesn't match the content received")
+	}
+	return nil
+}
+
+// uploadFile copies the file to a single node, failing to reach the node is reported as the error of its response
+func (c *CommanderServer) uploadFile(ctx context.Context, file *pb.FileHeader, content io.Reader, host *queries.RegisteredHostsData) *pb.FileUploadResponse {
+	hostname := host.Hostname
+	log := c.CO.Logger().With("host", hostname)
+	failed := func(err error) *pb.FileUploadResponse {
+		log.Warn("unable to send file", "path", file.Path, "err", err)
+		return &pb.FileUploadResponse{
+			Hostname:  hostname,
+			Path:      file.Path,
+			Timestamp: timestamppb.Now(),
+			Error:     err.Error(),
+		}
+	}
+
+	conn, err := c.CO.dialNode(ctx, host)
+	if err != nil {
+		return failed(err)
+	}
+	defer conn.Close()
+
+	header := &pb.FileUploadHeader{
+		File:     file,
+		Hostname: hostname,
+		Key:      &pb.Key{Key: c.ID},
+	}
+	if err := services.SignRequest(c.CO.identity.Key, header); err != nil {
+		return failed(fmt.Errorf("unable to sign upload for host %s: %w", hostname, err))
+	}
+	stream, err := pb.NewFileTransferClient(conn).Upload(ctx)
+	if err != nil {
+		return failed(fmt.Errorf("unable to upload to host %s: %w", hostname, err))
+	}
+	if err := sendUpload(stream, header, content); err != nil {
+		return failed(fmt.Errorf("unable to upload to host %s: %w", hostname, err))
+	}
+	res, err := stream.CloseAndRecv()
+	if err != nil {
+		return failed(fmt.Errorf("unable to upload to host %s: %w", hostname, err))
+	}
+	res.Hostname = hostname
+	log.Info("file sent", "path", file.Path, "successful", res.Successful, "changed", res.Changed)
+	return res
+}
+
+// sendUpload streams the header and content to the node. A node that fails the upload answers before it has
+// everything and closes the stream, that answer is left for CloseAndRecv to read.
+func sendUpload(stream pb.FileTransfer_UploadClient, header *pb.FileUploadHeader, content io.Reader) error {
+	err := stream.Send(&pb.FileUpload{Payload: &pb.FileUpload_Header{Header: header}})
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	buf := make([]byte, ChunkSize)
+	for {
+		n, err := content.Read(buf)
+		if n > 0 {
+			sendErr := stream.Send(&pb.FileUpload{Payload: &pb.FileUpload_Data{Data: buf[:n]}})
+			if errors.Is(sendErr, io.EOF) {
+				return nil
+			}
+			if sendErr != nil {
+				return sendErr
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("unable to read buffered file: %w", err)
+		}
+	}
+}
+
+// DefaultFetchLimit is the most each node may send when fetching a file, unless the request says otherwise
+const DefaultFetchLimit = 100 * 1024 * 1024
+
+// FetchFile downloads the file or directory from every node the pattern matches and streams the chunks back as
+// they arrive. A node that fails ends its download with an error and doesn't stop the others.
+func (c *CommanderServer) FetchFile(req *pb.FileFetchRequest, stream pb.CommandManager_FetchFileServer) error {
+	if err := services.CheckPath(req.Path); err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid path: %v", err)
+	}
+	hosts, err := c.CO.matchNodes(stream.Context(), req.Pattern)
+	if err != nil {
+		return err
+	}
+	limit := req.MaxSize
+	if limit == 0 {
+		limit = DefaultFetchLimit
+	}
+	c.CO.Logger().Info("fetching file", "pattern", req.Pattern, "path", req.Path, "hosts", len(hosts))
+
+	ctx := stream.Context()
+	var mu sync.Mutex
+	var sendErr error
+	send := func(res *pb.FileFetchResponse) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if sendErr == nil {
+			sendErr = stream.Send(res)
+		}
+		return sendErr
+	}
+
+	sem := make(chan struct{}, 50)
+	var wg sync.WaitGroup
+	for _, host := range hosts {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func(host *queries.RegisteredHostsData) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			c.downloadFile(ctx, req.Path, limit, host, send)
+		}(host)
+	}
+	wg.Wait()
+	if sendErr != nil {
+		return sendErr
+	}
+	return ctx.Err()
+}
+
+// downloadFile relays the download of a single node, failing to reach the node is sent as the error of its download
+func (c *CommanderServer) downloadFile(ctx context.Context, path string, limit uint64, host *queries.RegisteredHostsData, send func(*pb.FileFetchResponse) error) {
+	hostname := host.Hostname
+	log := c.CO.Logger().With("host", hostname)
+	failed := func(err error) {
+		log.Warn("unable to fetch file", "path", path, "err", err)
+		send(&pb.FileFetchResponse{Hostname: hostname, Error: err.Error()})
+	}
+
+	conn, err := c.CO.dialNode(ctx, host)
+	if err != nil {
+		failed(err)
+		return
+	}
+	defer conn.Close()
+
+	req := &pb.FileDownloadRequest{
+		Path:     path,
+		MaxSize:  limit,
+		Hostname: hostname,
+		Key:      &pb.Key{Key: c.ID},
+	}
+	if err := services.SignRequest(c.CO.identity.Key, req); err != nil {
+		failed(fmt.Errorf("unable to sign download for host %s: %w", hostname, err))
+		return
+	}
+	//stops the node sending when the download is given up before it is done
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := pb.NewFileTransferClient(conn).Download(ctx, req)
+	if err != nil {
+		failed(fmt.Errorf("unable to download from host %s: %w", hostname, err))
+		return
+	}
+	var size uint64
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			failed(fmt.Errorf("download from host %s ended early", hostname))
+			return
+		}
+		if err != nil {
+			failed(fmt.Errorf("unable to download from host %s: %w", hostname, err))
+			return
+		}
+		//the node enforces the limit too, this only guards against one that doesn't
+		size += uint64(len(chunk.GetData()))
+		if size > limit {
+			failed(fmt.Errorf("host %s sent more than the limit of %d bytes", hostname, limit))
+			return
+		}
+		if err := send(&pb.FileFetchResponse{Hostname: hostname, Chunk: chunk}); err != nil {
+			return
+		}
+		if chunk.GetDone() != nil {
+			log.Info("file fetched", "path", path, "size", size)
+			return
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package coordination
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"io"
+	"testing"
+
+	pb "github.com/charles-d-burton/tailsys/commands"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// sendStream plays back the messages of an upload to SendFile
+type sendStream struct {
+	grpc.ServerStream
+	msgs []*pb.FileSend
+	recv int
+}
+
+func (s *sendStream) Context() context.Context { return context.Background() }
+
+func (s *sendStream) Send(*pb.FileUploadResponse) error { return nil }
+
+func (s *sendStream) Recv() (*pb.FileSend, error) {
+	if s.recv == len(s.msgs) {
+		return nil, io.EOF
+	}
+	s.recv++
+	return s.msgs[s.recv-1], nil
+}
+
+func upload(size uint64, chunks ...[]byte) *sendStream {
+	s := &sendStream{msgs: []*pb.FileSend{{Payload: &pb.FileSend_Header{Header: &pb.FileSendHeader{
+		Pattern: "web1",
+		File:    &pb.FileHeader{Path: "/etc/motd", Size: size},
+	}}}}}
+	for _, c := range chunks {
+		s.msgs = append(s.msgs, &pb.FileSend{Payload: &pb.FileSend_Data{Data: c}})
+	}
+	return s
+}
+
+func TestSendFileLimits(t *testing.T) {
+	chunk := make([]byte, 64)
+	tests := []struct {
+		name   string
+		max    uint64
+		stream *sendStream
+		code   codes.Code
+		recv   int
+	}{
+		{"larger than the limit", 100, upload(101, chunk, chunk), codes.ResourceExhausted, 1},
+		{"more than the header says", 100, upload(64, chunk, chunk, chunk), codes.InvalidArgument, 3},
+		{"less than the header says", 100, upload(100, chunk), codes.InvalidArgument, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			co := &Coordinator{}
+			co.WithMaxSendSize(tt.max)(co)
+			c := &CommanderServer{CO: co}
+			err := c.SendFile(tt.stream)
+			if status.Code(err) != tt.code {
+				t.Fatalf("expected %s, got %v", tt.code, err)
+			}
+			if tt.stream.recv != tt.recv {
+				t.Fatalf("read %d messages of the upload, want %d", tt.stream.recv, tt.recv)
+			}
+		})
+	}
+}
+
+func TestReceiveFile(t *testing.T) {
+	data := []byte("hello")
+	sum := sha256.Sum256(data)
+	s := upload(uint64(len(data)), data[:2], data[2:])
+	header := s.msgs[0].GetHeader().File
+	header.Sha256 = sum[:]
+	s.recv = 1
+	var w bytes.Buffer
+	if err := receiveFile(&w, s, header); err != nil {
+		t.Fatal(err)
+	}
+	if w.String() != "hello" {
+		t.Fatalf("received %q", w.String())
+	}
+}
@@ -0,0 +1,221 @@
+package coordination
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	pb "github.com/charles-d-burton/tailsys/commands"
+	"github.com/charles-d-burton/tailsys/connections"
+	"github.com/charles-d-burton/tailsys/data/queries"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// firstHeartbeatTimeout is how long a new tunnel may go without a heartbeat before the node said how often it sends them
+const firstHeartbeatTimeout = time.Minute
+
+// tunnels are the connections back to the nodes in reverse mode by hostname
+type tunnels struct {
+	mu     sync.Mutex
+	conns  map[string]*grpc.ClientConn
+	closed chan struct{}
+}
+
+// add makes conn the way to reach the node, closing the tunnel it had before
+func (t *tunnels) add(hostname string, conn *grpc.ClientConn) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.conns == nil {
+		t.conns = make(map[string]*grpc.ClientConn)
+	}
+	if old, ok := t.conns[hostname]; ok {
+		old.Close()
+	}
+	t.conns[hostname] = conn
+}
+
+// remove forgets conn unless the node already opened a newer tunnel
+func (t *tunnels) remove(hostname string, conn *grpc.ClientConn) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.conns[hostname] == conn {
+		delete(t.conns, hostname)
+	}
+}
+
+func (t *tunnels) get(hostname string) *grpc.ClientConn {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.conns[hostname]
+}
+
+// done is closed once every tunnel should close because the server is shutting down
+func (t *tunnels) done() <-chan struct{} {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed == nil {
+		t.closed = make(chan struct{})
+	}
+	return t.closed
+}
+
+// shutdown closes every tunnel, the nodes open them again once the server is back
+func (t *tunnels) shutdown() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed == nil {
+		t.closed = make(chan struct{})
+	}
+	select {
+	case <-t.closed:
+	default:
+		close(t.closed)
+	}
+}
+
+// nodeConn is a connection to the gRPC server of a node. Closing it gives it back to the pool, or leaves the tunnel
+// open for a tunneled one.
+type nodeConn struct {
+	grpc.ClientConnInterface
+	close func() error
+}
+
+func (c *nodeConn) Close() error {
+	return c.close()
+}
+
+// dialNode connects to the gRPC server of a registered node, through its tunnel when it has one open and otherwise
+// with the pooled connection to the node
+func (co *Coordinator) dialNode(ctx context.Context, host *queries.RegisteredHostsData) (*nodeConn, error) {
+	if conn := co.tunnels.get(host.Hostname); conn != nil {
+		return &nodeConn{ClientConnInterface: conn, close: func() error { return nil }}, nil
+	}
+	req := &pb.NodeRegistrationRequest{}
+	if err := proto.Unmarshal(host.Data, req); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal registration of %s: %w", host.Hostname, err)
+	}
+	addr := host.Hostname + ":" + req.GetInfo().GetPort()
+	pc, err := co.conns.get(host.Hostname, addr, func() (*grpc.ClientConn, error) {
+		//the connection outlives ctx, it is shared with later callers
+		return co.DialContext(context.Background(), addr)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect to %s: %w", host.Hostname, err)
+	}
+	return &nodeConn{ClientConnInterface: pc.conn, close: func() error {
+		co.conns.release(pc)
+		return nil
+	}}, nil
+}
+
+// TunnelServer accepts the tunnels of nodes in reverse mode
+type TunnelServer struct {
+	pb.UnimplementedTunnelServer
+	CO *Coordinator
+}
+
+// Connect keeps the tunnel of a node open until the node goes away or stops sending heartbeats. The node is called
+// over the data frames of the stream, heartbeats are echoed and inventories stored as they arrive.
+func (s *TunnelServer) Connect(stream pb.Tunnel_ConnectServer) error {
+	ctx := stream.Context()
+	cert := connections.PeerCertificate(ctx)
+	if cert == nil {
+		return status.Error(codes.Unauthenticated, "a tunnel needs a node certificate")
+	}
+	hostname := cert.Subject.CommonName
+	host, err := queries.GetRegisteredHost(s.CO.DB, hostname)
+	if err != nil || host.Status != queries.NodeAccepted {
+		return status.Errorf(codes.PermissionDenied, "%s is not an accepted node", hostname)
+	}
+
+	var sendMu sync.Mutex
+	send := func(f *pb.TunnelFrame) error {
+		sendMu.Lock()
+		defer sendMu.Unlock()
+		return stream.Send(f)
+	}
+	var remote net.Addr
+	if p, ok := peer.FromContext(ctx); ok {
+		remote = p.Addr
+	}
+	tc := connections.NewTunnelConn(
+		func(data []byte) error { return send(&pb.TunnelFrame{Frame: &pb.TunnelFrame_Data{Data: data}}) },
+		connections.TunnelAddr(nil, s.CO.Hostname),
+		connections.TunnelAddr(remote, hostname),
+	)
+	conn, err := s.CO.DialTunnel(ctx, hostname, tc)
+	if err != nil {
+		return status.Errorf(codes.Internal, "unable to start connection through tunnel: %v", err)
+	}
+	s.CO.tunnels.add(hostname, conn)
+	s.CO.Logger().Info("node connected through a tunnel", "host", hostname)
+	defer func() {
+		s.CO.tunnels.remove(hostname, conn)
+		conn.Close()
+		tc.Close()
+		s.CO.Logger().Info("tunnel closed", "host", hostname)
+	}()
+
+	ip := ""
+	if remote != nil {
+		ip, _, _ = net.SplitHostPort(remote.String())
+	}
+	beats := make(chan time.Duration, 1)
+	errc := make(chan error, 1)
+	go func() {
+		for {
+			f, err := stream.Recv()
+			if err != nil {
+				tc.CloseWithError(err)
+				errc <- err
+				return
+			}
+			switch frame := f.Frame.(type) {
+			case *pb.TunnelFrame_Data:
+				tc.Deliver(frame.Data)
+			case *pb.TunnelFrame_Heartbeat:
+				select {
+				case beats <- frame.Heartbeat.GetInterval().AsDuration():
+				default:
+				}
+				if err := send(f); err != nil {
+					errc <- err
+					return
+				}
+			case *pb.TunnelFrame_Inventory:
+				if err := storeInventory(s.CO.DB, hostname, ip, frame.Inventory); err != nil {
+					s.CO.Logger().Error("unable to store inventory", "host", hostname, "err", err)
+				}
+			}
+		}
+	}()
+
+	timeout := firstHeartbeatTimeout
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	for {
+		select {
+		case <-s.CO.tunnels.done():
+			return status.Error(codes.Unavailable, "coordination server is shutting down")
+		case err := <-errc:
+			if errors.Is(err, context.Canceled) || status.Code(err) == codes.Canceled {
+				return nil
+			}
+			return err
+		case interval := <-beats:
+			if interval > 0 {
+				timeout = 3 * interval
+			}
+			timer.Reset(timeout)
+		case <-timer.C:
+			return status.Errorf(codes.DeadlineExceeded, "no heartbeat from %s in %s", hostname, timeout)
+		}
+	}
+}
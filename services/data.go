@@ -3,7 +3,7 @@ package services
 import (
 	"context"
 	"database/sql"
-	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
 
@@ -11,7 +11,6 @@ import (
 	"github.com/pressly/goose/v3"
 	"github.com/pressly/goose/v3/database"
 	"go.uber.org/atomic"
-	_ "modernc.org/sqlite"
 )
 
 type DataManagement struct {
@@ -20,11 +19,13 @@ type DataManagement struct {
 	MigrationTable string
 	DatabaseName   string
 	NoTxWrp        bool
+	log            *slog.Logger
 }
 
-func (dm *DataManagement) StartDB(dir string) error {
+// StartDB opens the database in dir and brings its schema up to date
+func (dm *DataManagement) StartDB(dir string, log *slog.Logger) error {
 	dbDir := dir + "/db"
-	fmt.Println("creating database at: ", dbDir)
+	log.Info("opening database", "path", dbDir)
 	// if err := os.MkdirAll(dir, os.ModePerm); err != nil {
 	//   return err
 	// }
@@ -32,54 +33,49 @@ func (dm *DataManagement) StartDB(dir string) error {
 		return err
 	}
 
-	db, err := sql.Open("sqlite", dbDir+"/tailsys.db")
-	if err != nil {
-		return err
-	}
+	//WAL lets the command fan out record results while host lookups are still reading
+	db := sql.OpenDB(&timedConnector{dsn: dbDir + "/tailsys.db?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)"})
 	dm.DB = db
+	dm.log = log
 
-	fmt.Println("database is created an online")
-	fmt.Println("running migrations")
+	log.Debug("running migrations")
 	ctx := context.Background()
-	if err := ensureSchema(ctx, db); err != nil {
+	if err := ensureSchema(ctx, db, log); err != nil {
 		return err
 	}
 	return nil
 }
 
-func ensureSchema(ctx context.Context, db *sql.DB) error {
+// CloseDB closes the database, it is safe to call when the database was never started
+func (dm *DataManagement) CloseDB() error {
+	if dm.DB == nil {
+		return nil
+	}
+	dm.log.Info("closing database")
+	return dm.DB.Close()
+}
+
+func ensureSchema(ctx context.Context, db *sql.DB, log *slog.Logger) error {
 	provider, err := goose.NewProvider(database.DialectSQLite3, db, migrations.Embed)
 	if err != nil {
 		return err
 	}
 
-	// List migration sources the provider is aware of.
-	fmt.Println("\n=== migration list ===")
-	sources := provider.ListSources()
-	for _, s := range sources {
-		fmt.Printf("%-3s %-2v %v\n", s.Type, s.Version, filepath.Base(s.Path))
-	}
-
 	//List status of migrations before applying
 	stats, err := provider.Status(ctx)
 	if err != nil {
 		return err
 	}
-
-	fmt.Println("\n=== migration status ===")
 	for _, s := range stats {
-		fmt.Printf("%-3s %-2v %v\n", s.Source.Type, s.Source.Version, s.State)
+		log.Debug("migration status", "type", s.Source.Type, "version", s.Source.Version, "file", filepath.Base(s.Source.Path), "state", s.State)
 	}
 
-	fmt.Println("\n=== log migration output  ===")
 	results, err := provider.Up(ctx)
 	if err != nil {
 		return err
 	}
-
-	fmt.Println("\n=== migration results  ===")
 	for _, r := range results {
-		fmt.Printf("%-3s %-2v done: %v\n", r.Source.Type, r.Source.Version, r.Duration)
+		log.Info("applied migration", "type", r.Source.Type, "version", r.Source.Version, "file", filepath.Base(r.Source.Path), "duration", r.Duration)
 	}
 	return nil
 }
@@ -0,0 +1,83 @@
+package services
+
+import (
+	"context"
+	"database/sql/driver"
+	"strings"
+	"time"
+
+	"github.com/charles-d-burton/tailsys/metrics"
+	"modernc.org/sqlite"
+)
+
+var dbQueryDuration = metrics.NewHistogramVec("tailsys_db_query_duration_seconds",
+	"Time spent running database statements, by statement and table.",
+	[]float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 5}, "query")
+
+// timedConnector opens sqlite connections that record how long their statements take
+type timedConnector struct {
+	dsn string
+	drv sqlite.Driver
+}
+
+func (c *timedConnector) Connect(context.Context) (driver.Conn, error) {
+	conn, err := c.drv.Open(c.dsn)
+	if err != nil {
+		return nil, err
+	}
+	return &timedConn{conn}, nil
+}
+
+func (c *timedConnector) Driver() driver.Driver { return &c.drv }
+
+// timedConn times the statements sent straight to the connection, every query of tailsys is sent that way
+type timedConn struct {
+	driver.Conn
+}
+
+func (c *timedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	defer observeQuery(query, time.Now())
+	return c.Conn.(driver.ExecerContext).ExecContext(ctx, query, args)
+}
+
+// QueryContext only times the query until the first rows are ready, reading them is up to the caller
+func (c *timedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	defer observeQuery(query, time.Now())
+	return c.Conn.(driver.QueryerContext).QueryContext(ctx, query, args)
+}
+
+func (c *timedConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	return c.Conn.(driver.ConnPrepareContext).PrepareContext(ctx, query)
+}
+
+func (c *timedConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	return c.Conn.(driver.ConnBeginTx).BeginTx(ctx, opts)
+}
+
+func (c *timedConn) Ping(ctx context.Context) error {
+	return c.Conn.(driver.Pinger).Ping(ctx)
+}
+
+func observeQuery(query string, start time.Time) {
+	dbQueryDuration.With(queryLabel(query)).Observe(time.Since(start).Seconds())
+}
+
+// queryLabel names a statement by what it does and the table it does it to, like "select node_health", so the label
+// doesn't grow with every query
+func queryLabel(query string) string {
+	words := strings.Fields(strings.ToLower(query))
+	if len(words) == 0 {
+		return "unknown"
+	}
+	verb := words[0]
+	for i := 0; i < len(words)-1; i++ {
+		switch words[i] {
+		case "from", "into", "update", "table", "on":
+			//skip the if not exists of schema changes
+			for i++; i < len(words)-1 && (words[i] == "if" || words[i] == "not" || words[i] == "exists"); i++ {
+			}
+			return verb + " " + strings.Trim(words[i], "`\"();")
+		}
+	}
+	return verb
+}
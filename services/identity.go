@@ -0,0 +1,126 @@
+package services
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+)
+
+// NodeIdentity is the keypair a coordination server or client is known by. The ID is derived from the public key,
+// so it stays the same across restarts for as long as the data directory is kept.
+type NodeIdentity struct {
+	ID   string
+	Key  ed25519.PrivateKey
+	Path string
+}
+
+// ErrNoIdentity is returned by ReadIdentity when the data directory has no identity yet
+var ErrNoIdentity = errors.New("no identity")
+
+// LoadIdentity loads the identity kept in the data directory, creating it the first time
+func LoadIdentity(dir string, log *slog.Logger) (*NodeIdentity, error) {
+	path := identityPath(dir)
+	//coordination servers used to keep only a signing key, it becomes their identity
+	legacy := filepath.Join(dir, "certs", "signing.key")
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		if err := os.Rename(legacy, path); err == nil {
+			log.Info("using signing key as identity", "path", path)
+		}
+	}
+
+	id, err := readIdentity(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return createIdentity(path, log)
+	}
+	return id, err
+}
+
+// ReadIdentity reads the identity kept in the data directory without creating or moving anything, it returns
+// ErrNoIdentity when there is none
+func ReadIdentity(dir string) (*NodeIdentity, error) {
+	id, err := readIdentity(identityPath(dir))
+	if errors.Is(err, os.ErrNotExist) {
+		//a coordination server that hasn't started since keeping only a signing key
+		id, err = readIdentity(filepath.Join(dir, "certs", "signing.key"))
+	}
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w in %s", ErrNoIdentity, dir)
+	}
+	return id, err
+}
+
+func identityPath(dir string) string {
+	return filepath.Join(dir, "certs", "identity.key")
+}
+
+func readIdentity(path string) (*NodeIdentity, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("invalid identity key in %s", path)
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	edKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("unsupported identity key type %T in %s", key, path)
+	}
+	return &NodeIdentity{
+		ID:   IdentityID(edKey.Public().(ed25519.PublicKey)),
+		Key:  edKey,
+		Path: path,
+	}, nil
+}
+
+func createIdentity(path string, log *slog.Logger) (*NodeIdentity, error) {
+	log.Info("no identity found, generating one", "path", path)
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0600); err != nil {
+		return nil, err
+	}
+	return &NodeIdentity{
+		ID:   IdentityID(key.Public().(ed25519.PublicKey)),
+		Key:  key,
+		Path: path,
+	}, nil
+}
+
+// PublicKey is the key other systems verify our signatures with
+func (id *NodeIdentity) PublicKey() ed25519.PublicKey {
+	return id.Key.Public().(ed25519.PublicKey)
+}
+
+// EncodedPublicKey is the public key in a form that can be shown and copied
+func (id *NodeIdentity) EncodedPublicKey() string {
+	return base64.StdEncoding.EncodeToString(id.PublicKey())
+}
+
+// IdentityID derives the ID of a system from its public key
+func IdentityID(pub ed25519.PublicKey) string {
+	sum := sha256.Sum256(pub)
+	return hex.EncodeToString(sum[:16])
+}
@@ -0,0 +1,55 @@
+package services
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadIdentityDoesNotCreate(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := ReadIdentity(dir); !errors.Is(err, ErrNoIdentity) {
+		t.Fatalf("expected ErrNoIdentity, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "certs")); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("reading the identity should not create anything, stat: %v", err)
+	}
+
+	created, err := LoadIdentity(dir, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	read, err := ReadIdentity(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read.ID != created.ID || read.Path != created.Path {
+		t.Fatalf("read %s from %s, created %s in %s", read.ID, read.Path, created.ID, created.Path)
+	}
+}
+
+func TestReadIdentityLegacySigningKey(t *testing.T) {
+	dir := t.TempDir()
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	created, err := LoadIdentity(dir, log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	legacy := filepath.Join(dir, "certs", "signing.key")
+	if err := os.Rename(created.Path, legacy); err != nil {
+		t.Fatal(err)
+	}
+	read, err := ReadIdentity(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read.ID != created.ID || read.Path != legacy {
+		t.Fatalf("read %s from %s, want %s from %s", read.ID, read.Path, created.ID, legacy)
+	}
+	if _, err := os.Stat(legacy); err != nil {
+		t.Fatalf("reading the identity should leave the signing key in place: %v", err)
+	}
+}
@@ -0,0 +1,18 @@
+package services
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// CheckPath checks a path a file is copied to or fetched from. It has to be absolute and clean, a path with ..
+// in it could leave the directory an acl rule allows and name any file on the node.
+func CheckPath(path string) error {
+	if !filepath.IsAbs(path) {
+		return fmt.Errorf("%s has to be absolute", path)
+	}
+	if clean := filepath.Clean(path); clean != path {
+		return fmt.Errorf("%s has to be a clean path like %s", path, clean)
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+package services
+
+import "testing"
+
+func TestCheckPath(t *testing.T) {
+	tests := []struct {
+		path string
+		ok   bool
+	}{
+		{"/etc/nginx/nginx.conf", true},
+		{"/", true},
+		{"etc/nginx", false},
+		{"/etc/nginx/../../root/.ssh/authorized_keys", false},
+		{"/var/log/../../etc/shadow", false},
+		{"/etc/./nginx", false},
+		{"/etc//nginx", false},
+		{"/etc/nginx/", false},
+		{"/../etc/shadow", false},
+	}
+	for _, tt := range tests {
+		if err := CheckPath(tt.path); (err == nil) != tt.ok {
+			t.Errorf("CheckPath(%q) = %v, want ok %v", tt.path, err, tt.ok)
+		}
+	}
+}
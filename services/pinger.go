@@ -3,7 +3,7 @@ package services
 import (
 	"context"
 	"database/sql"
-	"fmt"
+	"log/slog"
 	"time"
 
 	pb "github.com/charles-d-burton/tailsys/commands"
@@ -12,13 +12,14 @@ import (
 
 type Pinger struct {
 	pb.UnimplementedPingerServer
-	DB *sql.DB
-	ID string
+	DB  *sql.DB
+	ID  string
+	Log *slog.Logger
 }
 
 // Ping GRPC service for the service to ping clients and provide response time
 func (p *Pinger) Ping(ctx context.Context, in *pb.PingRequest) (*pb.PongResponse, error) {
-	fmt.Println("received ping request")
+	p.Log.Debug("received ping request")
 	now := time.Now()
 	latency := now.Sub(in.Ping.AsTime())
 
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// MaxRequestAge is how far the time a command request was signed can be from the clock of the node running it
+const MaxRequestAge = 2 * time.Minute
+
+// SignedRequest is a request the coordination server signs for a single node, like running a command or applying
+// a state. It carries the hostname, requested, nonce and signature fields of the CommandRequest.
+type SignedRequest interface {
+	proto.Message
+	GetHostname() string
+	GetRequested() *timestamppb.Timestamp
+	GetNonce() []byte
+	GetSignature() []byte
+}
+
+// SignRequest stamps the request with the current time and a fresh nonce and signs it
+func SignRequest(key ed25519.PrivateKey, req SignedRequest) error {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	m := req.ProtoReflect()
+	m.Set(field(m, "requested"), protoreflect.ValueOfMessage(timestamppb.Now().ProtoReflect()))
+	m.Set(field(m, "nonce"), protoreflect.ValueOfBytes(nonce))
+	digest, err := requestDigest(req)
+	if err != nil {
+		return err
+	}
+	m.Set(field(m, "signature"), protoreflect.ValueOfBytes(ed25519.Sign(key, digest)))
+	return nil
+}
+
+// requestDigest is the signed form of the request, everything but the signature itself
+func requestDigest(req SignedRequest) ([]byte, error) {
+	unsigned := proto.Clone(req)
+	m := unsigned.ProtoReflect()
+	m.Clear(field(m, "signature"))
+	return proto.MarshalOptions{Deterministic: true}.Marshal(unsigned)
+}
+
+func field(m protoreflect.Message, name protoreflect.Name) protoreflect.FieldDescriptor {
+	fd := m.Descriptor().Fields().ByName(name)
+	if fd == nil {
+		panic(fmt.Sprintf("%s has no %s field to sign", m.Descriptor().FullName(), name))
+	}
+	return fd
+}
+
+// CommandVerifier checks command and state requests were signed by the coordination server, are meant for this node and
+// haven't been seen before. The zero value is ready to use.
+type CommandVerifier struct {
+	mu   sync.Mutex
+	seen map[string]time.Time
+}
+
+// Verify returns an error unless the request was signed with key for hostname within MaxRequestAge and its
+// nonce hasn't been used yet
+func (v *CommandVerifier) Verify(key ed25519.PublicKey, hostname string, req SignedRequest) error {
+	if len(key) != ed25519.PublicKeySize {
+		return errors.New("no signing key registered for the coordination server")
+	}
+	if len(req.GetSignature()) == 0 || len(req.GetNonce()) == 0 || req.GetRequested() == nil {
+		return errors.New("request is not signed")
+	}
+	digest, err := requestDigest(req)
+	if err != nil {
+		return err
+	}
+	if !ed25519.Verify(key, digest, req.GetSignature()) {
+		return errors.New("invalid request signature")
+	}
+	if req.GetHostname() != hostname {
+		return fmt.Errorf("request is for %s, not %s", req.GetHostname(), hostname)
+	}
+	requested := req.GetRequested().AsTime()
+	if age := time.Since(requested); age > MaxRequestAge || age < -MaxRequestAge {
+		return fmt.Errorf("request was signed at %s, outside of the %s allowed", requested.Format(time.RFC3339), MaxRequestAge)
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	now := time.Now()
+	if v.seen == nil {
+		v.seen = make(map[string]time.Time)
+	}
+	for nonce, expires := range v.seen {
+		if now.After(expires) {
+			delete(v.seen, nonce)
+		}
+	}
+	nonce := string(req.GetNonce())
+	if _, ok := v.seen[nonce]; ok {
+		return errors.New("request has already been used")
+	}
+	v.seen[nonce] = requested.Add(MaxRequestAge)
+	return nil
+}
@@ -0,0 +1,166 @@
+package services
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/charles-d-burton/tailsys/commands"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func signedCommand(t *testing.T, key ed25519.PrivateKey, hostname string) *pb.CommandRequest {
+	t.Helper()
+	req := &pb.CommandRequest{Command: "uptime", Hostname: hostname}
+	if err := SignRequest(key, req); err != nil {
+		t.Fatal(err)
+	}
+	return req
+}
+
+// signAt signs the request as if it was signed at requested
+func signAt(t *testing.T, key ed25519.PrivateKey, req *pb.CommandRequest, requested time.Time) {
+	t.Helper()
+	req.Requested = timestamppb.New(requested)
+	req.Nonce = []byte("nonce-" + requested.String())
+	req.Signature = nil
+	digest, err := requestDigest(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Signature = ed25519.Sign(key, digest)
+}
+
+func TestVerify(t *testing.T) {
+	pub, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherPub, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		key  ed25519.PublicKey
+		req  func() *pb.CommandRequest
+		err  string
+	}{
+		{
+			name: "valid",
+			key:  pub,
+			req:  func() *pb.CommandRequest { return signedCommand(t, key, "web1") },
+		},
+		{
+			name: "wrong host",
+			key:  pub,
+			req:  func() *pb.CommandRequest { return signedCommand(t, key, "web2") },
+			err:  "request is for web2, not web1",
+		},
+		{
+			name: "tampered command",
+			key:  pub,
+			req: func() *pb.CommandRequest {
+				req := signedCommand(t, key, "web1")
+				req.Command = "rm -rf /"
+				return req
+			},
+			err: "invalid request signature",
+		},
+		{
+			name: "tampered host",
+			key:  pub,
+			req: func() *pb.CommandRequest {
+				req := signedCommand(t, key, "web2")
+				req.Hostname = "web1"
+				return req
+			},
+			err: "invalid request signature",
+		},
+		{
+			name: "tampered env",
+			key:  pub,
+			req: func() *pb.CommandRequest {
+				req := signedCommand(t, key, "web1")
+				req.Env = map[string]string{"LD_PRELOAD": "/tmp/x.so"}
+				return req
+			},
+			err: "invalid request signature",
+		},
+		{
+			name: "other key",
+			key:  otherPub,
+			req:  func() *pb.CommandRequest { return signedCommand(t, key, "web1") },
+			err:  "invalid request signature",
+		},
+		{
+			name: "unsigned",
+			key:  pub,
+			req:  func() *pb.CommandRequest { return &pb.CommandRequest{Command: "uptime", Hostname: "web1"} },
+			err:  "request is not signed",
+		},
+		{
+			name: "expired",
+			key:  pub,
+			req: func() *pb.CommandRequest {
+				req := &pb.CommandRequest{Command: "uptime", Hostname: "web1"}
+				signAt(t, key, req, time.Now().Add(-MaxRequestAge-time.Minute))
+				return req
+			},
+			err: "outside of the",
+		},
+		{
+			name: "from the future",
+			key:  pub,
+			req: func() *pb.CommandRequest {
+				req := &pb.CommandRequest{Command: "uptime", Hostname: "web1"}
+				signAt(t, key, req, time.Now().Add(MaxRequestAge+time.Minute))
+				return req
+			},
+			err: "outside of the",
+		},
+		{
+			name: "no server key",
+			key:  nil,
+			req:  func() *pb.CommandRequest { return signedCommand(t, key, "web1") },
+			err:  "no signing key",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &CommandVerifier{}
+			err := v.Verify(tt.key, "web1", tt.req())
+			if tt.err == "" {
+				if err != nil {
+					t.Fatalf("expected request to verify, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Fatalf("expected error containing %q, got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestVerifyReplayed(t *testing.T) {
+	pub, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := &CommandVerifier{}
+	req := signedCommand(t, key, "web1")
+	if err := v.Verify(pub, "web1", req); err != nil {
+		t.Fatalf("first use: %v", err)
+	}
+	if err := v.Verify(pub, "web1", req); err == nil || !strings.Contains(err.Error(), "already been used") {
+		t.Fatalf("expected replay to be rejected, got %v", err)
+	}
+	//a new request is still accepted after the replay was turned away
+	if err := v.Verify(pub, "web1", signedCommand(t, key, "web1")); err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+}
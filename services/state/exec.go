@@ -0,0 +1,41 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// run runs the program and returns its output. The error carries the last line of the output when it fails, that
+// is where package managers and the like say what went wrong.
+func run(ctx context.Context, env []string, name string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	if len(env) > 0 {
+		cmd.Env = append(cmd.Environ(), env...)
+	}
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+		if msg := strings.TrimSpace(lines[len(lines)-1]); msg != "" {
+			return "", fmt.Errorf("%s %s: %w: %s", name, strings.Join(args, " "), err, msg)
+		}
+		return "", fmt.Errorf("%s %s: %w", name, strings.Join(args, " "), err)
+	}
+	return string(out), nil
+}
+
+// succeeds runs a program used as a check, it is false when the program exits with an error status and only
+// returns an error when it couldn't be run at all
+func succeeds(ctx context.Context, name string, args ...string) (bool, error) {
+	err := exec.CommandContext(ctx, name, args...).Run()
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.As(err, &exitErr) && ctx.Err() == nil:
+		return false, nil
+	}
+	return false, fmt.Errorf("%s %s: %w", name, strings.Join(args, " "), err)
+}
@@ -0,0 +1,246 @@
+package state
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// File states of the file resource
+const (
+	FilePresent   = "present"
+	FileAbsent    = "absent"
+	FileDirectory = "directory"
+)
+
+// File is a file or directory with its content, mode and owner. Content, mode, owner and group are only managed
+// when they are set, a file created without content is empty.
+//
+//	resources:
+//	  - file: /etc/motd
+//	    content: "welcome\n"
+//	    mode: "0644"
+//	    owner: root
+//	    group: root
+//	    makedirs: true
+type File struct {
+	Path     string  `yaml:"file"`
+	State    string  `yaml:"state"`
+	Content  *string `yaml:"content"`
+	Mode     string  `yaml:"mode"`
+	Owner    string  `yaml:"owner"`
+	Group    string  `yaml:"group"`
+	MakeDirs bool    `yaml:"makedirs"`
+
+	//mode is the unix form of Mode, setuid is 0o4000
+	mode fs.FileMode
+}
+
+func (f *File) ID() string { return "file:" + f.Path }
+
+func (f *File) validate() error {
+	if !filepath.IsAbs(f.Path) {
+		return errors.New("path has to be absolute")
+	}
+	switch f.State {
+	case "":
+		f.State = FilePresent
+	case FilePresent, FileAbsent, FileDirectory:
+	default:
+		return fmt.Errorf("unknown state %q, use %s, %s or %s", f.State, FilePresent, FileAbsent, FileDirectory)
+	}
+	if f.Content != nil && f.State != FilePresent {
+		return fmt.Errorf("content can only be set on a file that is %s", FilePresent)
+	}
+	if f.Mode != "" {
+		m, err := strconv.ParseUint(f.Mode, 8, 32)
+		if err != nil || m > 0o7777 {
+			return fmt.Errorf("invalid mode %q, use an octal mode like 0644", f.Mode)
+		}
+		f.mode = fs.FileMode(m)
+	}
+	return nil
+}
+
+func (f *File) Apply(ctx context.Context, test bool) ([]string, error) {
+	fi, err := os.Lstat(f.Path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	exists := err == nil
+
+	if f.State == FileAbsent {
+		if !exists {
+			return nil, nil
+		}
+		if !test {
+			if err := os.Remove(f.Path); err != nil {
+				return nil, err
+			}
+		}
+		return []string{"removed"}, nil
+	}
+
+	changes := make([]string, 0)
+	dir := f.State == FileDirectory
+	if exists && fi.IsDir() && !dir {
+		return nil, fmt.Errorf("%s is a directory", f.Path)
+	}
+	if exists && !fi.IsDir() && dir {
+		return nil, fmt.Errorf("%s exists but is not a directory", f.Path)
+	}
+	if !exists {
+		if err := f.create(test); err != nil {
+			return nil, err
+		}
+		changes = append(changes, "created")
+		if test {
+			//nothing to compare a file that doesn't exist yet with
+			return changes, nil
+		}
+		if fi, err = os.Lstat(f.Path); err != nil {
+			return nil, err
+		}
+	}
+
+	if f.Content != nil && exists {
+		current, err := os.ReadFile(f.Path)
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(current, []byte(*f.Content)) {
+			changes = append(changes, fmt.Sprintf("content changed from %d to %d bytes", len(current), len(*f.Content)))
+			if !test {
+				if err := os.WriteFile(f.Path, []byte(*f.Content), fi.Mode().Perm()); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	if f.Mode != "" && UnixMode(fi) != f.mode {
+		changes = append(changes, fmt.Sprintf("mode %04o -> %04o", UnixMode(fi), f.mode))
+		if !test {
+			if err := os.Chmod(f.Path, GoMode(f.mode)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	owned, err := f.chown(fi, test)
+	if err != nil {
+		return nil, err
+	}
+	return append(changes, owned...), nil
+}
+
+// create makes the missing file or directory, in test mode it only checks it could be made
+func (f *File) create(test bool) error {
+	parent := filepath.Dir(f.Path)
+	if _, err := os.Stat(parent); errors.Is(err, fs.ErrNotExist) && !f.MakeDirs {
+		return fmt.Errorf("directory %s doesn't exist, set makedirs to create it", parent)
+	}
+	if test {
+		return nil
+	}
+	if f.MakeDirs {
+		if err := os.MkdirAll(parent, 0o755); err != nil {
+			return err
+		}
+	}
+	if f.State == FileDirectory {
+		mode := f.mode
+		if f.Mode == "" {
+			mode = 0o755
+		}
+		if err := os.Mkdir(f.Path, GoMode(mode)); err != nil {
+			return err
+		}
+		//the umask is applied when creating, set the mode that was asked for
+		return os.Chmod(f.Path, GoMode(mode))
+	}
+	mode := f.mode
+	if f.Mode == "" {
+		mode = 0o644
+	}
+	content := []byte{}
+	if f.Content != nil {
+		content = []byte(*f.Content)
+	}
+	if err := os.WriteFile(f.Path, content, GoMode(mode)); err != nil {
+		return err
+	}
+	return os.Chmod(f.Path, GoMode(mode))
+}
+
+// chown sets the owner and group that are set and differ from the ones of the file
+func (f *File) chown(fi fs.FileInfo, test bool) ([]string, error) {
+	if f.Owner == "" && f.Group == "" {
+		return nil, nil
+	}
+	uid, gid, err := FileOwner(fi)
+	if err != nil {
+		return nil, err
+	}
+	changes := make([]string, 0)
+	wantUID, wantGID := -1, -1
+	if f.Owner != "" {
+		id, err := LookupUser(f.Owner)
+		if err != nil {
+			return nil, err
+		}
+		if id != uid {
+			changes = append(changes, fmt.Sprintf("owner %d -> %s", uid, f.Owner))
+			wantUID = id
+		}
+	}
+	if f.Group != "" {
+		id, err := LookupGroup(f.Group)
+		if err != nil {
+			return nil, err
+		}
+		if id != gid {
+			changes = append(changes, fmt.Sprintf("group %d -> %s", gid, f.Group))
+			wantGID = id
+		}
+	}
+	if len(changes) > 0 && !test {
+		if err := os.Lchown(f.Path, wantUID, wantGID); err != nil {
+			return nil, err
+		}
+	}
+	return changes, nil
+}
+
+// UnixMode is the permissions of the file along with the setuid, setgid and sticky bits in unix form
+func UnixMode(fi fs.FileInfo) fs.FileMode {
+	m := fi.Mode().Perm()
+	if fi.Mode()&fs.ModeSetuid != 0 {
+		m |= 0o4000
+	}
+	if fi.Mode()&fs.ModeSetgid != 0 {
+		m |= 0o2000
+	}
+	if fi.Mode()&fs.ModeSticky != 0 {
+		m |= 0o1000
+	}
+	return m
+}
+
+// GoMode turns a unix mode into the form os functions take
+func GoMode(m fs.FileMode) fs.FileMode {
+	mode := m.Perm()
+	if m&0o4000 != 0 {
+		mode |= fs.ModeSetuid
+	}
+	if m&0o2000 != 0 {
+		mode |= fs.ModeSetgid
+	}
+	if m&0o1000 != 0 {
+		mode |= fs.ModeSticky
+	}
+	return mode
+}
@@ -0,0 +1,179 @@
+//go:build !windows
+// +build !windows
+
+package state
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFileCreate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "motd")
+	state := fmt.Sprintf("resources:\n  - file: %s\n    content: \"welcome\\n\"\n    mode: \"0600\"\n", path)
+
+	changes, err := apply(t, state, true)
+	if err != nil || strings.Join(changes, ",") != "created" {
+		t.Fatalf("expected test mode to report created, got %v %v", changes, err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("expected test mode not to create the file, got %v", err)
+	}
+
+	changes, err = apply(t, state, false)
+	if err != nil || strings.Join(changes, ",") != "created" {
+		t.Fatalf("expected the file to be created, got %v %v", changes, err)
+	}
+	checkFile(t, path, "welcome\n", 0o600)
+
+	changes, err = apply(t, state, false)
+	if err != nil || len(changes) != 0 {
+		t.Fatalf("expected applying again to change nothing, got %v %v", changes, err)
+	}
+}
+
+func TestFileChanges(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "motd")
+	if err := os.WriteFile(path, []byte("old\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	state := fmt.Sprintf("resources:\n  - file: %s\n    content: \"new content\\n\"\n    mode: \"0640\"\n", path)
+
+	changes, err := apply(t, state, true)
+	if err != nil || len(changes) != 2 {
+		t.Fatalf("expected test mode to report the content and mode, got %v %v", changes, err)
+	}
+	checkFile(t, path, "old\n", 0o644)
+
+	changes, err = apply(t, state, false)
+	want := "content changed from 4 to 12 bytes,mode 0644 -> 0640"
+	if err != nil || strings.Join(changes, ",") != want {
+		t.Fatalf("expected %s, got %v %v", want, changes, err)
+	}
+	checkFile(t, path, "new content\n", 0o640)
+}
+
+func TestFileOnlyManagesWhatIsSet(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "motd")
+	if err := os.WriteFile(path, []byte("keep\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	changes, err := apply(t, fmt.Sprintf("resources:\n  - file: %s\n", path), false)
+	if err != nil || len(changes) != 0 {
+		t.Fatalf("expected a file without content or mode to be left alone, got %v %v", changes, err)
+	}
+	checkFile(t, path, "keep\n", 0o600)
+}
+
+func TestFileOwner(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "motd")
+	if err := os.WriteFile(path, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	//the file already belongs to the user running the test, by uid and gid
+	state := fmt.Sprintf("resources:\n  - file: %s\n    owner: \"%d\"\n    group: \"%d\"\n", path, os.Getuid(), os.Getgid())
+	changes, err := apply(t, state, false)
+	if err != nil || len(changes) != 0 {
+		t.Fatalf("expected the owner to be unchanged, got %v %v", changes, err)
+	}
+
+	state = fmt.Sprintf("resources:\n  - file: %s\n    owner: no-such-user-tailsys\n", path)
+	if _, err := apply(t, state, true); err == nil {
+		t.Fatal("expected an unknown owner to fail")
+	}
+}
+
+func TestFileAbsent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "motd")
+	if err := os.WriteFile(path, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	state := fmt.Sprintf("resources:\n  - file: %s\n    state: absent\n", path)
+
+	if changes, err := apply(t, state, true); err != nil || strings.Join(changes, ",") != "removed" {
+		t.Fatalf("expected test mode to report removed, got %v %v", changes, err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("expected test mode not to remove the file, got %v", err)
+	}
+	if changes, err := apply(t, state, false); err != nil || strings.Join(changes, ",") != "removed" {
+		t.Fatalf("expected the file to be removed, got %v %v", changes, err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("expected the file to be gone, got %v", err)
+	}
+	if changes, err := apply(t, state, false); err != nil || len(changes) != 0 {
+		t.Fatalf("expected removing a missing file to change nothing, got %v %v", changes, err)
+	}
+}
+
+func TestFileDirectory(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "a", "b")
+	state := fmt.Sprintf("resources:\n  - file: %s\n    state: directory\n    mode: \"0750\"\n", path)
+
+	if _, err := apply(t, state, false); err == nil || !strings.Contains(err.Error(), "set makedirs") {
+		t.Fatalf("expected a missing parent to fail without makedirs, got %v", err)
+	}
+	changes, err := apply(t, state+"    makedirs: true\n", false)
+	if err != nil || strings.Join(changes, ",") != "created" {
+		t.Fatalf("expected the directory to be created, got %v %v", changes, err)
+	}
+	fi, err := os.Stat(path)
+	if err != nil || !fi.IsDir() || UnixMode(fi) != 0o750 {
+		t.Fatalf("expected a directory with mode 0750, got %v %v", fi, err)
+	}
+
+	file := fmt.Sprintf("resources:\n  - file: %s\n", path)
+	if _, err := apply(t, file, false); err == nil || !strings.Contains(err.Error(), "is a directory") {
+		t.Fatalf("expected a directory not to be managed as a file, got %v", err)
+	}
+	file = filepath.Join(dir, "file")
+	if err := os.WriteFile(file, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	state = fmt.Sprintf("resources:\n  - file: %s\n    state: directory\n", file)
+	if _, err := apply(t, state, false); err == nil || !strings.Contains(err.Error(), "not a directory") {
+		t.Fatalf("expected a file not to be managed as a directory, got %v", err)
+	}
+}
+
+func TestModes(t *testing.T) {
+	for _, m := range []os.FileMode{0o644, 0o4755, 0o2750, 0o1777, 0o7777} {
+		path := filepath.Join(t.TempDir(), "f")
+		if err := os.WriteFile(path, nil, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chmod(path, GoMode(m)); err != nil {
+			t.Fatal(err)
+		}
+		fi, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := UnixMode(fi); got != m {
+			t.Errorf("mode %04o came back as %04o", m, got)
+		}
+	}
+}
+
+func checkFile(t *testing.T, path, content string, mode os.FileMode) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != content {
+		t.Errorf("expected %s to hold %q, got %q", path, content, data)
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if UnixMode(fi) != mode {
+		t.Errorf("expected %s to have mode %04o, got %04o", path, mode, UnixMode(fi))
+	}
+}
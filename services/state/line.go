@@ -0,0 +1,141 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Line states of the line resource
+const (
+	LinePresent = "present"
+	LineAbsent  = "absent"
+)
+
+// Line is a line in a file. With match the lines matching the regular expression are replaced by content, or
+// content is appended when none match. Absent removes the lines equal to content, or matching match when it is set.
+//
+//	resources:
+//	  - line: /etc/ssh/sshd_config
+//	    content: "PermitRootLogin no"
+//	    match: '^#?PermitRootLogin\s'
+type Line struct {
+	Path    string `yaml:"line"`
+	State   string `yaml:"state"`
+	Content string `yaml:"content"`
+	Match   string `yaml:"match"`
+	Create  bool   `yaml:"create"`
+
+	match *regexp.Regexp
+}
+
+func (l *Line) ID() string { return "line:" + l.Path }
+
+func (l *Line) validate() error {
+	if !filepath.IsAbs(l.Path) {
+		return errors.New("path has to be absolute")
+	}
+	switch l.State {
+	case "":
+		l.State = LinePresent
+	case LinePresent, LineAbsent:
+	default:
+		return fmt.Errorf("unknown state %q, use %s or %s", l.State, LinePresent, LineAbsent)
+	}
+	if strings.Contains(l.Content, "\n") {
+		return errors.New("content has to be a single line")
+	}
+	if l.State == LinePresent && l.Content == "" {
+		return errors.New("content is required")
+	}
+	if l.State == LineAbsent && l.Content == "" && l.Match == "" {
+		return errors.New("content or match is required")
+	}
+	if l.Match != "" {
+		re, err := regexp.Compile(l.Match)
+		if err != nil {
+			return fmt.Errorf("invalid match: %w", err)
+		}
+		l.match = re
+	}
+	return nil
+}
+
+// matches is true for the lines the resource is about
+func (l *Line) matches(line string) bool {
+	if l.match != nil {
+		return l.match.MatchString(line)
+	}
+	return line == l.Content
+}
+
+func (l *Line) Apply(ctx context.Context, test bool) ([]string, error) {
+	data, err := os.ReadFile(l.Path)
+	missing := errors.Is(err, fs.ErrNotExist)
+	switch {
+	case missing && l.State == LineAbsent:
+		return nil, nil
+	case missing && !l.Create:
+		return nil, fmt.Errorf("%s doesn't exist, set create to create it", l.Path)
+	case err != nil && !missing:
+		return nil, err
+	}
+
+	text := string(data)
+	trailing := text == "" || strings.HasSuffix(text, "\n")
+	lines := strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+	if text == "" {
+		lines = nil
+	}
+
+	changes := make([]string, 0)
+	kept := make([]string, 0, len(lines)+1)
+	if l.State == LineAbsent {
+		for _, line := range lines {
+			if l.matches(line) {
+				continue
+			}
+			kept = append(kept, line)
+		}
+		if removed := len(lines) - len(kept); removed > 0 {
+			changes = append(changes, fmt.Sprintf("removed %d lines", removed))
+		}
+	} else {
+		found, replaced := false, 0
+		for _, line := range lines {
+			if l.matches(line) {
+				found = true
+				if line != l.Content {
+					line = l.Content
+					replaced++
+				}
+			}
+			kept = append(kept, line)
+		}
+		switch {
+		case !found:
+			kept = append(kept, l.Content)
+			changes = append(changes, "appended "+l.Content)
+		case replaced > 0:
+			changes = append(changes, fmt.Sprintf("replaced %d lines with %s", replaced, l.Content))
+		}
+	}
+	if len(changes) == 0 || test {
+		return changes, nil
+	}
+
+	out := strings.Join(kept, "\n")
+	if trailing && len(kept) > 0 {
+		out += "\n"
+	}
+	//writing in place keeps the mode and owner of the file
+	if err := os.WriteFile(l.Path, []byte(out), 0o644); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
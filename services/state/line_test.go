@@ -0,0 +1,142 @@
+package state
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLine(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		line    string
+		want    string
+		changes string
+	}{
+		{
+			name:    "append",
+			content: "127.0.0.1 localhost\n",
+			line:    "content: \"10.0.0.5 db\"",
+			want:    "127.0.0.1 localhost\n10.0.0.5 db\n",
+			changes: "appended 10.0.0.5 db",
+		},
+		{
+			name:    "already there",
+			content: "127.0.0.1 localhost\n10.0.0.5 db\n",
+			line:    "content: \"10.0.0.5 db\"",
+			want:    "127.0.0.1 localhost\n10.0.0.5 db\n",
+		},
+		{
+			name:    "replace matching",
+			content: "Port 22\n#PermitRootLogin yes\nPermitRootLogin yes\n",
+			line:    "content: \"PermitRootLogin no\"\n    match: '^#?PermitRootLogin\\s'",
+			want:    "Port 22\nPermitRootLogin no\nPermitRootLogin no\n",
+			changes: "replaced 2 lines with PermitRootLogin no",
+		},
+		{
+			name:    "match already replaced",
+			content: "Port 22\nPermitRootLogin no\n",
+			line:    "content: \"PermitRootLogin no\"\n    match: '^#?PermitRootLogin\\s'",
+			want:    "Port 22\nPermitRootLogin no\n",
+		},
+		{
+			name:    "append without trailing newline",
+			content: "a\nb",
+			line:    "content: c",
+			want:    "a\nb\nc",
+			changes: "appended c",
+		},
+		{
+			name:    "append to empty",
+			content: "",
+			line:    "content: a",
+			want:    "a\n",
+			changes: "appended a",
+		},
+		{
+			name:    "remove",
+			content: "a\nb\na\n",
+			line:    "content: a\n    state: absent",
+			want:    "b\n",
+			changes: "removed 2 lines",
+		},
+		{
+			name:    "remove matching",
+			content: "a1\nb\na2\n",
+			line:    "match: ^a\n    state: absent",
+			want:    "b\n",
+			changes: "removed 2 lines",
+		},
+		{
+			name:    "remove everything",
+			content: "a\n",
+			line:    "content: a\n    state: absent",
+			want:    "",
+			changes: "removed 1 lines",
+		},
+		{
+			name:    "nothing to remove",
+			content: "b\n",
+			line:    "content: a\n    state: absent",
+			want:    "b\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "file")
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			state := fmt.Sprintf("resources:\n  - line: %s\n    %s\n", path, tt.line)
+
+			changes, err := apply(t, state, true)
+			if err != nil || strings.Join(changes, ",") != tt.changes {
+				t.Fatalf("expected test mode to report %q, got %v %v", tt.changes, changes, err)
+			}
+			checkContent(t, path, tt.content)
+
+			changes, err = apply(t, state, false)
+			if err != nil || strings.Join(changes, ",") != tt.changes {
+				t.Fatalf("expected %q, got %v %v", tt.changes, changes, err)
+			}
+			checkContent(t, path, tt.want)
+
+			if changes, err = apply(t, state, false); err != nil || len(changes) != 0 {
+				t.Fatalf("expected applying again to change nothing, got %v %v", changes, err)
+			}
+		})
+	}
+}
+
+func TestLineMissingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "file")
+	state := fmt.Sprintf("resources:\n  - line: %s\n    content: a\n", path)
+
+	if _, err := apply(t, state, false); err == nil || !strings.Contains(err.Error(), "set create") {
+		t.Fatalf("expected a missing file to fail without create, got %v", err)
+	}
+	if changes, err := apply(t, state+"    create: true\n", false); err != nil || len(changes) != 1 {
+		t.Fatalf("expected the line to be added to a new file, got %v %v", changes, err)
+	}
+	checkContent(t, path, "a\n")
+
+	absent := filepath.Join(t.TempDir(), "missing")
+	state = fmt.Sprintf("resources:\n  - line: %s\n    content: a\n    state: absent\n", absent)
+	if changes, err := apply(t, state, false); err != nil || len(changes) != 0 {
+		t.Fatalf("expected an absent line in a missing file to change nothing, got %v %v", changes, err)
+	}
+}
+
+func checkContent(t *testing.T, path, content string) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != content {
+		t.Fatalf("expected %s to hold %q, got %q", path, content, data)
+	}
+}
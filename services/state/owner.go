@@ -0,0 +1,45 @@
+//go:build !windows
+// +build !windows
+
+package state
+
+import (
+	"errors"
+	"io/fs"
+	"os/user"
+	"strconv"
+	"syscall"
+)
+
+// FileOwner returns the uid and gid of the file
+func FileOwner(fi fs.FileInfo) (int, int, error) {
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, 0, errors.New("unable to read the owner of " + fi.Name())
+	}
+	return int(st.Uid), int(st.Gid), nil
+}
+
+// LookupUser returns the uid of the user, the name can also be a uid
+func LookupUser(name string) (int, error) {
+	if id, err := strconv.Atoi(name); err == nil {
+		return id, nil
+	}
+	u, err := user.Lookup(name)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(u.Uid)
+}
+
+// LookupGroup returns the gid of the group, the name can also be a gid
+func LookupGroup(name string) (int, error) {
+	if id, err := strconv.Atoi(name); err == nil {
+		return id, nil
+	}
+	g, err := user.LookupGroup(name)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(g.Gid)
+}
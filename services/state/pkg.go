@@ -0,0 +1,149 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Package states of the pkg resource
+const (
+	PkgInstalled = "installed"
+	PkgRemoved   = "removed"
+)
+
+// Package is a package installed with the package manager of the node, found by looking for apt, dnf, yum, apk
+// and pacman in that order unless manager names one.
+//
+//	resources:
+//	  - pkg: nginx
+//	    state: installed
+type Package struct {
+	Name    string `yaml:"pkg"`
+	State   string `yaml:"state"`
+	Manager string `yaml:"manager"`
+}
+
+// packageManager knows how to check, install and remove packages
+type packageManager struct {
+	// binary looked up on the path to decide the manager is there
+	binary    string
+	installed func(ctx context.Context, name string) (bool, error)
+	install   []string
+	remove    []string
+	env       []string
+}
+
+var packageManagers = map[string]*packageManager{
+	"apt": {
+		binary: "apt-get",
+		installed: func(ctx context.Context, name string) (bool, error) {
+			out, err := exec.CommandContext(ctx, "dpkg-query", "-W", "-f=${Status}", name).Output()
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				return false, nil
+			}
+			if err != nil {
+				return false, err
+			}
+			return strings.Contains(string(out), "install ok installed"), nil
+		},
+		install: []string{"apt-get", "install", "-y", "-q"},
+		remove:  []string{"apt-get", "remove", "-y", "-q"},
+		env:     []string{"DEBIAN_FRONTEND=noninteractive"},
+	},
+	"dnf": {
+		binary:    "dnf",
+		installed: rpmInstalled,
+		install:   []string{"dnf", "install", "-y", "-q"},
+		remove:    []string{"dnf", "remove", "-y", "-q"},
+	},
+	"yum": {
+		binary:    "yum",
+		installed: rpmInstalled,
+		install:   []string{"yum", "install", "-y", "-q"},
+		remove:    []string{"yum", "remove", "-y", "-q"},
+	},
+	"apk": {
+		binary: "apk",
+		installed: func(ctx context.Context, name string) (bool, error) {
+			return succeeds(ctx, "apk", "info", "-e", name)
+		},
+		install: []string{"apk", "add", "-q"},
+		remove:  []string{"apk", "del", "-q"},
+	},
+	"pacman": {
+		binary: "pacman",
+		installed: func(ctx context.Context, name string) (bool, error) {
+			return succeeds(ctx, "pacman", "-Q", name)
+		},
+		install: []string{"pacman", "-S", "--noconfirm", "--needed"},
+		remove:  []string{"pacman", "-R", "--noconfirm"},
+	},
+}
+
+// managerOrder is the order package managers are looked for in, dnf before yum as it replaced it
+var managerOrder = []string{"apt", "dnf", "yum", "apk", "pacman"}
+
+func rpmInstalled(ctx context.Context, name string) (bool, error) {
+	return succeeds(ctx, "rpm", "-q", name)
+}
+
+func (p *Package) ID() string { return "pkg:" + p.Name }
+
+func (p *Package) validate() error {
+	if p.Name == "" {
+		return errors.New("package name is required")
+	}
+	switch p.State {
+	case "":
+		p.State = PkgInstalled
+	case PkgInstalled, PkgRemoved:
+	default:
+		return fmt.Errorf("unknown state %q, use %s or %s", p.State, PkgInstalled, PkgRemoved)
+	}
+	if _, ok := packageManagers[p.Manager]; p.Manager != "" && !ok {
+		return fmt.Errorf("unknown package manager %q, use one of %s", p.Manager, strings.Join(managerOrder, ", "))
+	}
+	return nil
+}
+
+// manager returns the package manager set on the resource or the first one found on the node
+func (p *Package) manager() (*packageManager, error) {
+	if p.Manager != "" {
+		return packageManagers[p.Manager], nil
+	}
+	for _, name := range managerOrder {
+		if _, err := exec.LookPath(packageManagers[name].binary); err == nil {
+			return packageManagers[name], nil
+		}
+	}
+	return nil, errors.New("no supported package manager found")
+}
+
+func (p *Package) Apply(ctx context.Context, test bool) ([]string, error) {
+	pm, err := p.manager()
+	if err != nil {
+		return nil, err
+	}
+	installed, err := pm.installed(ctx, p.Name)
+	if err != nil {
+		return nil, err
+	}
+	if installed == (p.State == PkgInstalled) {
+		return nil, nil
+	}
+
+	args, change := pm.install, "installed"
+	if p.State == PkgRemoved {
+		args, change = pm.remove, "removed"
+	}
+	if !test {
+		if _, err := run(ctx, pm.env, args[0], append(args[1:len(args):len(args)], p.Name)...); err != nil {
+			return nil, err
+		}
+	}
+	return []string{change}, nil
+}
@@ -0,0 +1,72 @@
+package state
+
+import (
+	"context"
+	"errors"
+)
+
+// Service is a systemd service, running and enabled are only managed when they are set
+//
+//	resources:
+//	  - service: nginx
+//	    running: true
+//	    enabled: true
+type Service struct {
+	Name    string `yaml:"service"`
+	Running *bool  `yaml:"running"`
+	Enabled *bool  `yaml:"enabled"`
+}
+
+func (s *Service) ID() string { return "service:" + s.Name }
+
+func (s *Service) validate() error {
+	if s.Name == "" {
+		return errors.New("service name is required")
+	}
+	if s.Running == nil && s.Enabled == nil {
+		return errors.New("running or enabled is required")
+	}
+	return nil
+}
+
+func (s *Service) Apply(ctx context.Context, test bool) ([]string, error) {
+	changes := make([]string, 0)
+	//enabling first so a service that fails to start is still started on the next boot
+	if s.Enabled != nil {
+		enabled, err := succeeds(ctx, "systemctl", "is-enabled", "--quiet", s.Name)
+		if err != nil {
+			return nil, err
+		}
+		if enabled != *s.Enabled {
+			action := "disable"
+			if *s.Enabled {
+				action = "enable"
+			}
+			if !test {
+				if _, err := run(ctx, nil, "systemctl", action, s.Name); err != nil {
+					return changes, err
+				}
+			}
+			changes = append(changes, action+"d")
+		}
+	}
+	if s.Running != nil {
+		active, err := succeeds(ctx, "systemctl", "is-active", "--quiet", s.Name)
+		if err != nil {
+			return changes, err
+		}
+		if active != *s.Running {
+			action, change := "stop", "stopped"
+			if *s.Running {
+				action, change = "start", "started"
+			}
+			if !test {
+				if _, err := run(ctx, nil, "systemctl", action, s.Name); err != nil {
+					return changes, err
+				}
+			}
+			changes = append(changes, change)
+		}
+	}
+	return changes, nil
+}
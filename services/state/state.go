@@ -0,0 +1,196 @@
+// Package state applies declarative state files on a node. A state file lists resources in the order they are
+// applied, each one names its type with the key holding what it manages:
+//
+//	resources:
+//	  - pkg: nginx
+//	  - file: /etc/nginx/conf.d/site.conf
+//	    content: |
+//	      server { listen 80; }
+//	    mode: "0644"
+//	    owner: root
+//	  - service: nginx
+//	    running: true
+//	    enabled: true
+//	  - user: deploy
+//	    shell: /bin/bash
+//	    groups: [www-data]
+//	  - line: /etc/hosts
+//	    content: "10.0.0.5 db"
+//	    match: '^10\.0\.0\.5\s'
+//
+// Resources only change what differs from the state, applying the same state twice changes nothing the second
+// time. In test mode they report what they would change without changing it.
+package state
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"reflect"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Resource is something on the node a state file declares
+type Resource interface {
+	// ID names the resource as it was written, like file:/etc/motd
+	ID() string
+	// Apply brings the resource in line with the state and returns what it changed, in test mode nothing is
+	// changed and it returns what it would change
+	Apply(ctx context.Context, test bool) ([]string, error)
+}
+
+// Status is what applying a resource did
+type Status int
+
+const (
+	Unchanged Status = iota
+	Changed
+	Failed
+)
+
+func (s Status) String() string {
+	switch s {
+	case Changed:
+		return "changed"
+	case Failed:
+		return "failed"
+	}
+	return "unchanged"
+}
+
+// Result of applying a single resource
+type Result struct {
+	ID       string
+	Status   Status
+	Changes  []string
+	Err      error
+	Duration time.Duration
+}
+
+// State is a parsed state file
+type State struct {
+	Resources []Resource
+}
+
+// types are the resource types by the key naming them in a state file
+var types = map[string]func() Resource{
+	"file":    func() Resource { return &File{} },
+	"line":    func() Resource { return &Line{} },
+	"pkg":     func() Resource { return &Package{} },
+	"service": func() Resource { return &Service{} },
+	"user":    func() Resource { return &User{} },
+}
+
+// validator is implemented by resources that check their fields once they are parsed
+type validator interface {
+	validate() error
+}
+
+// Parse reads a state file, every resource has to be of a known type and may only have the fields of that type
+func Parse(data []byte) (*State, error) {
+	file := struct {
+		Resources []yaml.Node `yaml:"resources"`
+	}{}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&file); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("invalid state: %w", err)
+	}
+
+	state := &State{}
+	for i := range file.Resources {
+		r, err := parseResource(&file.Resources[i])
+		if err != nil {
+			return nil, fmt.Errorf("resource %d on line %d: %w", i+1, file.Resources[i].Line, err)
+		}
+		state.Resources = append(state.Resources, r)
+	}
+	return state, nil
+}
+
+func parseResource(node *yaml.Node) (Resource, error) {
+	if node.Kind != yaml.MappingNode {
+		return nil, errors.New("a resource has to be a mapping")
+	}
+	var r Resource
+	kind := ""
+	for i := 0; i < len(node.Content); i += 2 {
+		key := node.Content[i].Value
+		create, ok := types[key]
+		if !ok {
+			continue
+		}
+		if r != nil {
+			return nil, fmt.Errorf("a resource can't be both %s and %s", kind, key)
+		}
+		r = create()
+		kind = key
+	}
+	if r == nil {
+		return nil, errors.New("no resource type, use one of file, line, pkg, service or user")
+	}
+
+	//Node.Decode ignores fields the resource doesn't have, a typo would silently leave something unmanaged
+	fields := yamlFields(r)
+	for i := 0; i < len(node.Content); i += 2 {
+		if key := node.Content[i]; !fields[key.Value] {
+			return nil, fmt.Errorf("%s has no field %s", kind, key.Value)
+		}
+	}
+	if err := node.Decode(r); err != nil {
+		return nil, err
+	}
+	if v, ok := r.(validator); ok {
+		if err := v.validate(); err != nil {
+			return nil, fmt.Errorf("%s: %w", r.ID(), err)
+		}
+	}
+	return r, nil
+}
+
+// yamlFields returns the names of the fields of the resource in a state file
+func yamlFields(r Resource) map[string]bool {
+	fields := make(map[string]bool)
+	t := reflect.TypeOf(r).Elem()
+	for i := 0; i < t.NumField(); i++ {
+		if name, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ","); name != "" {
+			fields[name] = true
+		}
+	}
+	return fields
+}
+
+// Apply applies every resource in order, a resource failing doesn't stop the ones after it
+func (s *State) Apply(ctx context.Context, test bool, log *slog.Logger) []*Result {
+	results := make([]*Result, 0, len(s.Resources))
+	for _, r := range s.Resources {
+		start := time.Now()
+		res := &Result{ID: r.ID()}
+		if err := ctx.Err(); err != nil {
+			res.Status = Failed
+			res.Err = err
+			results = append(results, res)
+			continue
+		}
+		res.Changes, res.Err = r.Apply(ctx, test)
+		res.Duration = time.Since(start)
+		switch {
+		case res.Err != nil:
+			res.Status = Failed
+			log.Warn("resource failed", "resource", res.ID, "test", test, "err", res.Err)
+		case len(res.Changes) > 0:
+			res.Status = Changed
+			log.Info("resource changed", "resource", res.ID, "test", test, "changes", res.Changes)
+		default:
+			log.Debug("resource unchanged", "resource", res.ID)
+		}
+		results = append(results, res)
+	}
+	return results
+}
@@ -0,0 +1,143 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+var discard = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+// parse parses the state and fails the test when it is invalid
+func parse(t *testing.T, data string) *State {
+	t.Helper()
+	st, err := Parse([]byte(data))
+	if err != nil {
+		t.Fatalf("unable to parse state: %v\n%s", err, data)
+	}
+	return st
+}
+
+// apply applies a state with a single resource and returns the changes
+func apply(t *testing.T, data string, test bool) ([]string, error) {
+	t.Helper()
+	st := parse(t, data)
+	if len(st.Resources) != 1 {
+		t.Fatalf("expected a single resource, got %d", len(st.Resources))
+	}
+	return st.Resources[0].Apply(context.Background(), test)
+}
+
+func TestParse(t *testing.T) {
+	st := parse(t, `
+resources:
+  - pkg: nginx
+  - file: /etc/motd
+    content: "welcome\n"
+    mode: "0644"
+  - service: nginx
+    running: true
+  - user: deploy
+  - line: /etc/hosts
+    content: "10.0.0.5 db"
+`)
+	ids := make([]string, 0)
+	for _, r := range st.Resources {
+		ids = append(ids, r.ID())
+	}
+	want := "pkg:nginx,file:/etc/motd,service:nginx,user:deploy,line:/etc/hosts"
+	if got := strings.Join(ids, ","); got != want {
+		t.Fatalf("parsed %s, want %s", got, want)
+	}
+	if f := st.Resources[1].(*File); f.State != FilePresent || f.mode != 0o644 {
+		t.Fatalf("expected a present file with mode 0644, got %s %04o", f.State, f.mode)
+	}
+
+	if st := parse(t, ""); len(st.Resources) != 0 {
+		t.Fatalf("expected an empty state to have no resources, got %d", len(st.Resources))
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		state string
+		err   string
+	}{
+		{"unknown top level key", "resource:\n  - pkg: nginx\n", "invalid state"},
+		{"not a mapping", "resources:\n  - nginx\n", "has to be a mapping"},
+		{"no type", "resources:\n  - content: x\n", "no resource type"},
+		{"two types", "resources:\n  - pkg: nginx\n    service: nginx\n", "both pkg and service"},
+		{"unknown field", "resources:\n  - file: /etc/motd\n    contents: x\n", "file has no field contents"},
+		{"relative path", "resources:\n  - file: etc/motd\n", "path has to be absolute"},
+		{"unknown file state", "resources:\n  - file: /etc/motd\n    state: gone\n", "unknown state"},
+		{"content on a directory", "resources:\n  - file: /etc/app\n    state: directory\n    content: x\n", "content can only be set"},
+		{"invalid mode", "resources:\n  - file: /etc/motd\n    mode: \"0999\"\n", "invalid mode"},
+		{"mode too large", "resources:\n  - file: /etc/motd\n    mode: \"17777\"\n", "invalid mode"},
+		{"line without content", "resources:\n  - line: /etc/hosts\n", "content is required"},
+		{"absent line without content", "resources:\n  - line: /etc/hosts\n    state: absent\n", "content or match is required"},
+		{"multiple lines", "resources:\n  - line: /etc/hosts\n    content: \"a\\nb\"\n", "single line"},
+		{"invalid match", "resources:\n  - line: /etc/hosts\n    content: a\n    match: \"(\"\n", "invalid match"},
+		{"position", "resources:\n  - pkg: nginx\n  - file: motd\n", "resource 2 on line 3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.state))
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Fatalf("expected error containing %q, got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+// fake is a resource that returns what it was told to
+type fake struct {
+	id      string
+	changes []string
+	err     error
+	applied bool
+}
+
+func (f *fake) ID() string { return f.id }
+
+func (f *fake) Apply(ctx context.Context, test bool) ([]string, error) {
+	f.applied = true
+	return f.changes, f.err
+}
+
+func TestApply(t *testing.T) {
+	failing := &fake{id: "fake:failing", err: errors.New("broken")}
+	changed := &fake{id: "fake:changed", changes: []string{"created"}}
+	unchanged := &fake{id: "fake:unchanged"}
+	st := &State{Resources: []Resource{failing, changed, unchanged}}
+
+	results := st.Apply(context.Background(), false, discard)
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+	want := []Status{Failed, Changed, Unchanged}
+	for i, res := range results {
+		if res.ID != st.Resources[i].ID() || res.Status != want[i] {
+			t.Errorf("result %d is %s %s, want %s %s", i, res.ID, res.Status, st.Resources[i].ID(), want[i])
+		}
+	}
+	if results[0].Err == nil || results[1].Changes[0] != "created" {
+		t.Fatalf("expected the error and changes of the resources in the results, got %v %v", results[0].Err, results[1].Changes)
+	}
+}
+
+func TestApplyCanceled(t *testing.T) {
+	r := &fake{id: "fake:canceled", changes: []string{"created"}}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	results := (&State{Resources: []Resource{r}}).Apply(ctx, false, discard)
+	if r.applied {
+		t.Fatal("expected a resource not to be applied once the context is canceled")
+	}
+	if results[0].Status != Failed || !errors.Is(results[0].Err, context.Canceled) {
+		t.Fatalf("expected the resource to fail with the context error, got %s %v", results[0].Status, results[0].Err)
+	}
+}
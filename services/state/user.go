@@ -0,0 +1,170 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// User states of the user resource
+const (
+	UserPresent = "present"
+	UserAbsent  = "absent"
+)
+
+// User is a local account managed with useradd and usermod. Uid, home and shell are only managed when they are
+// set, groups are added to the supplementary groups of the user without removing the others. Removing a user
+// leaves its home directory.
+//
+//	resources:
+//	  - user: deploy
+//	    shell: /bin/bash
+//	    groups: [sudo, docker]
+type User struct {
+	Name   string   `yaml:"user"`
+	State  string   `yaml:"state"`
+	UID    *int     `yaml:"uid"`
+	Home   string   `yaml:"home"`
+	Shell  string   `yaml:"shell"`
+	Groups []string `yaml:"groups"`
+	System bool     `yaml:"system"`
+}
+
+// passwdEntry is the account of a user as getent reports it
+type passwdEntry struct {
+	uid   int
+	home  string
+	shell string
+}
+
+func (u *User) ID() string { return "user:" + u.Name }
+
+func (u *User) validate() error {
+	if u.Name == "" {
+		return errors.New("user name is required")
+	}
+	switch u.State {
+	case "":
+		u.State = UserPresent
+	case UserPresent, UserAbsent:
+	default:
+		return fmt.Errorf("unknown state %q, use %s or %s", u.State, UserPresent, UserAbsent)
+	}
+	return nil
+}
+
+func (u *User) Apply(ctx context.Context, test bool) ([]string, error) {
+	entry, err := lookupPasswd(ctx, u.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.State == UserAbsent {
+		if entry == nil {
+			return nil, nil
+		}
+		if !test {
+			if _, err := run(ctx, nil, "userdel", u.Name); err != nil {
+				return nil, err
+			}
+		}
+		return []string{"removed"}, nil
+	}
+
+	if entry == nil {
+		if !test {
+			if _, err := run(ctx, nil, "useradd", u.addArgs()...); err != nil {
+				return nil, err
+			}
+		}
+		return []string{"created"}, nil
+	}
+
+	changes := make([]string, 0)
+	args := make([]string, 0)
+	if u.UID != nil && *u.UID != entry.uid {
+		changes = append(changes, fmt.Sprintf("uid %d -> %d", entry.uid, *u.UID))
+		args = append(args, "-u", strconv.Itoa(*u.UID))
+	}
+	if u.Home != "" && u.Home != entry.home {
+		changes = append(changes, fmt.Sprintf("home %s -> %s", entry.home, u.Home))
+		args = append(args, "-d", u.Home)
+	}
+	if u.Shell != "" && u.Shell != entry.shell {
+		changes = append(changes, fmt.Sprintf("shell %s -> %s", entry.shell, u.Shell))
+		args = append(args, "-s", u.Shell)
+	}
+	if len(u.Groups) > 0 {
+		out, err := run(ctx, nil, "id", "-nG", u.Name)
+		if err != nil {
+			return nil, err
+		}
+		current := strings.Fields(out)
+		missing := make([]string, 0)
+		for _, g := range u.Groups {
+			if !slices.Contains(current, g) {
+				missing = append(missing, g)
+			}
+		}
+		if len(missing) > 0 {
+			changes = append(changes, "added to "+strings.Join(missing, ","))
+			args = append(args, "-a", "-G", strings.Join(missing, ","))
+		}
+	}
+	if len(args) == 0 || test {
+		return changes, nil
+	}
+	if _, err := run(ctx, nil, "usermod", append(args, u.Name)...); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// addArgs are the useradd arguments creating the user, users that aren't system users get a home directory
+func (u *User) addArgs() []string {
+	args := make([]string, 0)
+	if u.System {
+		args = append(args, "-r")
+	} else {
+		args = append(args, "-m")
+	}
+	if u.UID != nil {
+		args = append(args, "-u", strconv.Itoa(*u.UID))
+	}
+	if u.Home != "" {
+		args = append(args, "-d", u.Home)
+	}
+	if u.Shell != "" {
+		args = append(args, "-s", u.Shell)
+	}
+	if len(u.Groups) > 0 {
+		args = append(args, "-G", strings.Join(u.Groups, ","))
+	}
+	return append(args, u.Name)
+}
+
+// lookupPasswd returns the account of the user, nil when there is none
+func lookupPasswd(ctx context.Context, name string) (*passwdEntry, error) {
+	out, err := exec.CommandContext(ctx, "getent", "passwd", name).Output()
+	var exitErr *exec.ExitError
+	//getent exits with 2 when the key isn't found
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 2 {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getent passwd %s: %w", name, err)
+	}
+	fields := strings.Split(strings.TrimSpace(string(out)), ":")
+	if len(fields) < 7 {
+		return nil, fmt.Errorf("unexpected passwd entry for %s", name)
+	}
+	uid, err := strconv.Atoi(fields[2])
+	if err != nil {
+		return nil, fmt.Errorf("unexpected uid for %s: %w", name, err)
+	}
+	return &passwdEntry{uid: uid, home: fields[5], shell: fields[6]}, nil
+}
@@ -0,0 +1,187 @@
+package targeting
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	wordToken tokenKind = iota
+	opToken
+	openToken
+	closeToken
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	// quoted words are never keywords, so a node called and can still be targeted as "and"
+	quoted bool
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+func isOp(c byte) bool {
+	return c == '=' || c == '!' || c == '<' || c == '>'
+}
+
+// lex splits the expression into words, operators and parentheses
+func lex(s string) ([]token, error) {
+	toks := make([]token, 0)
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case isSpace(c):
+			i++
+		case c == '(':
+			toks = append(toks, token{kind: openToken, text: "("})
+			i++
+		case c == ')':
+			toks = append(toks, token{kind: closeToken, text: ")"})
+			i++
+		case isOp(c):
+			op := string(c)
+			if i+1 < len(s) {
+				switch two := s[i : i+2]; two {
+				case "!=", ">=", "<=", "=~":
+					op = two
+				}
+			}
+			if op == "!" {
+				return nil, errors.New("unexpected !, use not to negate a term")
+			}
+			toks = append(toks, token{kind: opToken, text: op})
+			i += len(op)
+		default:
+			var word strings.Builder
+			quoted := false
+			for i < len(s) && !isSpace(s[i]) && !isOp(s[i]) && s[i] != '(' && s[i] != ')' {
+				if q := s[i]; q == '"' || q == '\'' {
+					end := strings.IndexByte(s[i+1:], q)
+					if end < 0 {
+						return nil, fmt.Errorf("unterminated quote in %s", s[i:])
+					}
+					word.WriteString(s[i+1 : i+1+end])
+					i += end + 2
+					quoted = true
+					continue
+				}
+				word.WriteByte(s[i])
+				i++
+			}
+			toks = append(toks, token{kind: wordToken, text: word.String(), quoted: quoted})
+		}
+	}
+	return toks, nil
+}
+
+// parser is a recursive descent parser, not binds tighter than and which binds tighter than or
+type parser struct {
+	toks   []token
+	pos    int
+	groups Groups
+	stack  []string
+}
+
+func (p *parser) done() bool {
+	return p.pos >= len(p.toks)
+}
+
+func (p *parser) peek() token {
+	return p.toks[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.toks[p.pos]
+	p.pos++
+	return t
+}
+
+func (p *parser) keyword(kw string) bool {
+	if p.done() {
+		return false
+	}
+	t := p.peek()
+	return t.kind == wordToken && !t.quoted && strings.EqualFold(t.text, kw)
+}
+
+func (p *parser) or() (Expression, error) {
+	left, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("or") {
+		p.next()
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		left = or{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) and() (Expression, error) {
+	left, err := p.not()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("and") {
+		p.next()
+		right, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		left = and{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) not() (Expression, error) {
+	if p.keyword("not") {
+		p.next()
+		e, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		return not{e: e}, nil
+	}
+	return p.primary()
+}
+
+func (p *parser) primary() (Expression, error) {
+	if p.done() {
+		return nil, errors.New("expression ends early")
+	}
+	t := p.next()
+	switch t.kind {
+	case openToken:
+		e, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if p.done() || p.next().kind != closeToken {
+			return nil, errors.New("missing )")
+		}
+		return e, nil
+	case closeToken, opToken:
+		return nil, fmt.Errorf("unexpected %q", t.text)
+	}
+
+	if !t.quoted && (strings.EqualFold(t.text, "and") || strings.EqualFold(t.text, "or")) {
+		return nil, fmt.Errorf("unexpected %q", t.text)
+	}
+	if !p.done() && p.peek().kind == opToken {
+		op := p.next()
+		if p.done() || p.peek().kind != wordToken {
+			return nil, fmt.Errorf("%s %s needs a value", t.text, op.text)
+		}
+		return newFact(t.text, op.text, p.next().text)
+	}
+	return p.term(t.text, t.quoted)
+}
@@ -0,0 +1,154 @@
+package targeting
+
+import (
+	"strings"
+	"testing"
+)
+
+var testNodes = []*Node{
+	{Hostname: "web1", Facts: map[string][]string{
+		"hostname": {"web1"}, "os": {"ubuntu"}, "arch": {"amd64"}, "mem_gb": {"32.00"}, "tag": {"tag:prod", "tag:web"},
+		"ip": {"100.64.0.1", "10.0.0.1"}, "kernel": {"6.5.0-generic"},
+	}},
+	{Hostname: "web2", Facts: map[string][]string{
+		"hostname": {"web2"}, "os": {"ubuntu"}, "arch": {"arm64"}, "mem_gb": {"8.00"}, "tag": {"tag:canary", "tag:web"},
+		"ip": {"100.64.0.2"}, "kernel": {"5.15.0-generic"},
+	}},
+	{Hostname: "DB-1", Facts: map[string][]string{
+		"hostname": {"DB-1"}, "os": {"Debian"}, "arch": {"amd64"}, "mem_gb": {"64.00"}, "tag": {"tag:prod"},
+		"ip": {"100.64.0.3"},
+	}},
+	{Hostname: "and", Facts: map[string][]string{"hostname": {"and"}}},
+}
+
+var testGroups = map[string]string{
+	"web":   "web*",
+	"big":   "mem_gb>=32",
+	"prod":  "tag:prod and not N@web",
+	"loop":  "N@loop2",
+	"loop2": "N@loop",
+	"bad":   "os=",
+}
+
+func lookupGroup(name string) (string, bool) {
+	g, ok := testGroups[name]
+	return g, ok
+}
+
+// matching returns the hostnames of the test nodes the expression matches
+func matching(t *testing.T, expr string) string {
+	t.Helper()
+	e, err := Parse(expr, lookupGroup)
+	if err != nil {
+		t.Fatalf("unable to parse %q: %v", expr, err)
+	}
+	hosts := make([]string, 0)
+	for _, n := range testNodes {
+		if e.Match(n) {
+			hosts = append(hosts, n.Hostname)
+		}
+	}
+	return strings.Join(hosts, ",")
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{"", "web1,web2,DB-1,and"},
+		{"web1", "web1"},
+		{"web*", "web1,web2"},
+		{"web?", "web1,web2"},
+		{"web[2-9]", "web2"},
+		{"web1,db-*", "web1,DB-1"},
+		{"L@web2,DB-1", "web2,DB-1"},
+		{"WEB*", "web1,web2"},
+		{"db-1", "DB-1"},
+		{"E@^web[0-9]+$", "web1,web2"},
+		{"E@^db", ""},
+		{"E@\"(?i)^db\"", "DB-1"},
+		{"E@^DB", "DB-1"},
+		{"tag:prod", "web1,DB-1"},
+		{"tag:PROD", "web1,DB-1"},
+		{"tag:*", "web1,web2,DB-1"},
+		{"os=ubuntu", "web1,web2"},
+		{"os=debian", "DB-1"},
+		{"OS=Debian", "DB-1"},
+		{"os!=ubuntu", "DB-1,and"},
+		{"os=ubu*", "web1,web2"},
+		{"kernel=~^6\\.", "web1"},
+		{"kernel=~\"^[56]\\.\"", "web1,web2"},
+		{"mem_gb>16", "web1,DB-1"},
+		{"mem_gb>=32", "web1,DB-1"},
+		{"mem_gb<32", "web2"},
+		{"mem_gb<=8", "web2"},
+		{"ip=100.64.0.*", "web1,web2,DB-1"},
+		{"ip=10.0.0.1", "web1"},
+		{"web* and arch=arm64", "web2"},
+		{"web* or tag:prod", "web1,web2,DB-1"},
+		{"not web*", "DB-1,and"},
+		{"not not web1", "web1"},
+		{"tag:prod or web2 and arch=amd64", "web1,DB-1"},
+		{"(tag:prod or web2) and arch=amd64", "web1,DB-1"},
+		{"(tag:prod or web2) and arch=arm64", "web2"},
+		{"tag:web AND NOT tag:canary", "web1"},
+		{"\"and\"", "and"},
+		{"'and' or web1", "web1,and"},
+		{"N@web", "web1,web2"},
+		{"@web and N@big", "web1"},
+		{"N@prod", "DB-1"},
+		{"\"web.*\"", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			if got := matching(t, tt.expr); got != tt.want {
+				t.Fatalf("%q matched %q, want %q", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		expr string
+		err  string
+	}{
+		{"web.*", "looks like a regular expression"},
+		{"^web", "looks like a regular expression"},
+		{"web1|web2", "looks like a regular expression"},
+		{"web[0-9]+", "looks like a regular expression"},
+		{"(web1", "missing )"},
+		{"web1)", "unexpected \")\""},
+		{"web1 and", "expression ends early"},
+		{"and web1", "unexpected \"and\""},
+		{"web1 web2", "unexpected \"web2\""},
+		{"not", "expression ends early"},
+		{"os=", "needs a value"},
+		{"os!ubuntu", "use not to negate"},
+		{"color=red", "unknown fact color"},
+		{"mem_gb>lots", "needs a number"},
+		{"kernel=~\"(\"", "invalid regular expression"},
+		{"E@\"(\"", "invalid regular expression"},
+		{"web[", "invalid glob"},
+		{"\"web1", "unterminated quote"},
+		{",", "empty list"},
+		{"N@missing", "unknown node group missing"},
+		{"N@loop", "refers to itself"},
+		{"N@bad", "node group bad"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := Parse(tt.expr, lookupGroup)
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Fatalf("expected %q to fail with %q, got %v", tt.expr, tt.err, err)
+			}
+		})
+	}
+}
+
+func TestParseWithoutGroups(t *testing.T) {
+	if _, err := Parse("N@web", nil); err == nil {
+		t.Fatal("expected a node group to be unknown without groups")
+	}
+}
@@ -0,0 +1,269 @@
+// Package targeting matches nodes against target expressions, a small language similar to salt compound matchers.
+//
+// A term on its own matches the hostname:
+//
+//	web-*            glob
+//	web1,web2,db-*   list of globs
+//	E@^web[0-9]+$    regular expression
+//	tag:prod         tailscale tag, globs allowed
+//	N@web or @web    node group, defined as an expression of its own
+//
+// A term with an operator compares an inventory fact, see Facts:
+//
+//	os=ubuntu  arch!=arm64  mem_gb>16  cpus>=4  kernel=~"^6\."
+//
+// Terms are combined with and, or, not and parentheses. Values containing spaces, parentheses or operators
+// can be quoted with " or '. An empty expression matches every node.
+//
+// Globs ignore case, regular expressions use go syntax and are matched as written, they can ignore case with (?i).
+// Quote a regular expression with parentheses, like E@"(?i)^db". Bare words used to be regular expressions, an
+// unquoted word that can only be one, like web.* or ^db, is rejected instead of silently matching nothing. Quote it
+// to match it as a glob.
+package targeting
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Facts are the inventory facts an expression can compare, along with what they hold
+var Facts = map[string]string{
+	"hostname":     "hostname of the node",
+	"os":           "distro id like ubuntu or debian, the platform when the distro is unknown",
+	"os_version":   "distro version like 22.04",
+	"platform":     "operating system like linux or darwin",
+	"kernel":       "kernel release",
+	"arch":         "architecture like amd64 or arm64",
+	"cpus":         "number of cpus",
+	"cpu_model":    "cpu model name",
+	"mem_gb":       "total memory in GiB",
+	"mem_free_gb":  "available memory in GiB",
+	"uptime_hours": "hours since boot",
+	"version":      "tailsys version",
+	"ip":           "tailscale ips of the node as the tailnet reports them",
+	"tag":          "tailscale tags of the node as the tailnet reports them",
+}
+
+// Node is what an expression is matched against, a fact can have more than one value like the ips of a node
+type Node struct {
+	Hostname string
+	Facts    map[string][]string
+}
+
+// Expression is a parsed target expression
+type Expression interface {
+	Match(n *Node) bool
+}
+
+// Groups looks up the expression a node group stands for
+type Groups func(name string) (string, bool)
+
+// Parse parses the expression, node groups are looked up with groups which can be nil when there are none
+func Parse(expr string, groups Groups) (Expression, error) {
+	return parse(expr, groups, nil)
+}
+
+func parse(expr string, groups Groups, stack []string) (Expression, error) {
+	toks, err := lex(expr)
+	if err != nil {
+		return nil, err
+	}
+	if len(toks) == 0 {
+		return all{}, nil
+	}
+	p := &parser{toks: toks, groups: groups, stack: stack}
+	e, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if !p.done() {
+		return nil, fmt.Errorf("unexpected %q", p.peek().text)
+	}
+	return e, nil
+}
+
+type all struct{}
+
+func (all) Match(*Node) bool { return true }
+
+type and struct{ left, right Expression }
+
+func (e and) Match(n *Node) bool { return e.left.Match(n) && e.right.Match(n) }
+
+type or struct{ left, right Expression }
+
+func (e or) Match(n *Node) bool { return e.left.Match(n) || e.right.Match(n) }
+
+type not struct{ e Expression }
+
+func (e not) Match(n *Node) bool { return !e.e.Match(n) }
+
+// hostGlobs matches the hostname against any of the globs, the globs are lower case
+type hostGlobs []string
+
+func (e hostGlobs) Match(n *Node) bool {
+	hostname := strings.ToLower(n.Hostname)
+	for _, glob := range e {
+		if ok, _ := path.Match(glob, hostname); ok {
+			return true
+		}
+	}
+	return false
+}
+
+type hostRegexp struct{ re *regexp.Regexp }
+
+func (e hostRegexp) Match(n *Node) bool { return e.re.MatchString(n.Hostname) }
+
+// fact compares the values of a fact, it matches when any value does. != matches when no value is equal.
+type fact struct {
+	key   string
+	op    string
+	value string
+	num   float64
+	re    *regexp.Regexp
+}
+
+func (e fact) Match(n *Node) bool {
+	values := n.Facts[e.key]
+	if e.op == "!=" {
+		return !(fact{key: e.key, op: "=", value: e.value}).Match(n)
+	}
+	for _, v := range values {
+		switch e.op {
+		case "=":
+			if ok, _ := path.Match(e.value, strings.ToLower(v)); ok {
+				return true
+			}
+		case "=~":
+			if e.re.MatchString(v) {
+				return true
+			}
+		default:
+			num, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			if compare(num, e.op, e.num) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func compare(a float64, op string, b float64) bool {
+	switch op {
+	case ">":
+		return a > b
+	case ">=":
+		return a >= b
+	case "<":
+		return a < b
+	case "<=":
+		return a <= b
+	}
+	return false
+}
+
+func newFact(key, op, value string) (Expression, error) {
+	key = strings.ToLower(key)
+	if _, ok := Facts[key]; !ok {
+		return nil, fmt.Errorf("unknown fact %s", key)
+	}
+	e := fact{key: key, op: op, value: strings.ToLower(value)}
+	switch op {
+	case "=", "!=":
+		if _, err := path.Match(e.value, ""); err != nil {
+			return nil, fmt.Errorf("invalid glob %s: %w", value, err)
+		}
+	case "=~":
+		re, err := regexp.Compile(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression %s: %w", value, err)
+		}
+		e.re = re
+	default:
+		num, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s %s needs a number, not %s", key, op, value)
+		}
+		e.num = num
+	}
+	return e, nil
+}
+
+// term turns a word without an operator into an expression
+func (p *parser) term(word string, quoted bool) (Expression, error) {
+	switch {
+	case strings.HasPrefix(word, "E@"):
+		re, err := regexp.Compile(word[2:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression %s: %w", word[2:], err)
+		}
+		return hostRegexp{re: re}, nil
+	case strings.HasPrefix(word, "N@"):
+		return p.group(word[2:])
+	case strings.HasPrefix(word, "@"):
+		return p.group(word[1:])
+	case strings.HasPrefix(word, "L@"):
+		return globs(word[2:])
+	case strings.HasPrefix(word, "tag:"):
+		return newFact("tag", "=", word)
+	}
+	if !quoted && looksLikeRegexp(word) {
+		return nil, fmt.Errorf("%s looks like a regular expression, bare words are globs, use E@%s or quote it to match it as a glob", word, word)
+	}
+	return globs(word)
+}
+
+// regexpOnly are characters that can't be in a hostname or a glob of one, a word with them is meant as a regular
+// expression
+const regexpOnly = `^$+|\{}`
+
+// looksLikeRegexp reports whether the word was meant as a regular expression, like patterns before they were globs
+func looksLikeRegexp(word string) bool {
+	return strings.ContainsAny(word, regexpOnly) || strings.Contains(word, ".*")
+}
+
+func globs(word string) (Expression, error) {
+	list := strings.Split(word, ",")
+	res := make(hostGlobs, 0, len(list))
+	for _, glob := range list {
+		if glob == "" {
+			continue
+		}
+		if _, err := path.Match(glob, ""); err != nil {
+			return nil, fmt.Errorf("invalid glob %s: %w", glob, err)
+		}
+		res = append(res, strings.ToLower(glob))
+	}
+	if len(res) == 0 {
+		return nil, fmt.Errorf("empty list %q", word)
+	}
+	return res, nil
+}
+
+// group parses the expression of a node group, a group can refer to other groups but not to itself
+func (p *parser) group(name string) (Expression, error) {
+	for _, g := range p.stack {
+		if g == name {
+			return nil, fmt.Errorf("node group %s refers to itself", name)
+		}
+	}
+	if p.groups == nil {
+		return nil, fmt.Errorf("unknown node group %s", name)
+	}
+	expr, ok := p.groups(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown node group %s", name)
+	}
+	e, err := parse(expr, p.groups, append(p.stack, name))
+	if err != nil {
+		return nil, fmt.Errorf("node group %s: %w", name, err)
+	}
+	return e, nil
+}
@@ -0,0 +1,4 @@
+package services
+
+// Version of tailsys, set at build time with -ldflags "-X github.com/charles-d-burton/tailsys/services.Version=<version>"
+var Version = "dev"